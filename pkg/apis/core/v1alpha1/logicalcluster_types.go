@@ -166,6 +166,42 @@ type LogicalClusterStatus struct {
 	//
 	// +optional
 	Initializers []LogicalClusterInitializer `json:"initializers,omitempty"`
+
+	// deletion reports the progress of the content deletion once the logical cluster is deleted.
+	//
+	// +optional
+	Deletion *LogicalClusterDeletionStatus `json:"deletion,omitempty"`
+}
+
+// LogicalClusterDeletionStatus reports the progress of the content deletion of a logical cluster.
+type LogicalClusterDeletionStatus struct {
+	// phase is the state of the content deletion (NotStarted, InProgress, Failed, Completed).
+	//
+	// +optional
+	// +kubebuilder:validation:Enum=NotStarted;InProgress;Failed;Completed
+	Phase string `json:"phase,omitempty"`
+
+	// remaining is the number of resource instances remaining after the last deletion pass.
+	//
+	// +optional
+	Remaining int64 `json:"remaining,omitempty"`
+
+	// lastProcessedResource is the resource, in resource.group format, processed last by the last
+	// deletion pass.
+	//
+	// +optional
+	LastProcessedResource string `json:"lastProcessedResource,omitempty"`
+
+	// lastUpdated is the time the deletion status was last updated.
+	//
+	// +optional
+	LastUpdated v1.Time `json:"lastUpdated,omitempty"`
+
+	// estimatedCompletion is the time the content deletion is estimated to complete, extrapolated
+	// from the deletion rate observed across deletion passes. It is unset while there is no estimate.
+	//
+	// +optional
+	EstimatedCompletion *v1.Time `json:"estimatedCompletion,omitempty"`
 }
 
 func (in *LogicalCluster) SetConditions(c conditionsv1alpha1.Conditions) {
@@ -56,6 +56,27 @@ func (in *LogicalCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogicalClusterDeletionStatus) DeepCopyInto(out *LogicalClusterDeletionStatus) {
+	*out = *in
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	if in.EstimatedCompletion != nil {
+		in, out := &in.EstimatedCompletion, &out.EstimatedCompletion
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogicalClusterDeletionStatus.
+func (in *LogicalClusterDeletionStatus) DeepCopy() *LogicalClusterDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LogicalClusterDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogicalClusterList) DeepCopyInto(out *LogicalClusterList) {
 	*out = *in
@@ -146,6 +167,11 @@ func (in *LogicalClusterStatus) DeepCopyInto(out *LogicalClusterStatus) {
 		*out = make([]LogicalClusterInitializer, len(*in))
 		copy(*out, *in)
 	}
+	if in.Deletion != nil {
+		in, out := &in.Deletion, &out.Deletion
+		*out = new(LogicalClusterDeletionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
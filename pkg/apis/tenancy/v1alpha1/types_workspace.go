@@ -67,6 +67,27 @@ const (
 
 	// WorkspaceContentDeleted represents the status that all resources in the workspace are deleted.
 	WorkspaceContentDeleted conditionsv1alpha1.ConditionType = "WorkspaceContentDeleted"
+	// WorkspaceDeletionStalled represents the status that the content deletion of the workspace has not made
+	// progress for a number of deletion passes.
+	WorkspaceDeletionStalled conditionsv1alpha1.ConditionType = "WorkspaceDeletionStalled"
+	// WorkspaceDeletionFailedTerminal represents the status that the content deletion of the workspace has given up
+	// after the maximum number of attempts.
+	WorkspaceDeletionFailedTerminal conditionsv1alpha1.ConditionType = "WorkspaceDeletionFailedTerminal"
+	// WorkspaceDeletionForbidden represents the status that the content deletion of the workspace is not permitted
+	// to delete some resources, e.g. because of missing RBAC permissions of the deletion identity.
+	WorkspaceDeletionForbidden conditionsv1alpha1.ConditionType = "WorkspaceDeletionForbidden"
+	// WorkspaceDeletionContention represents the status that instances of some resources of the workspace are
+	// recreated during the content deletion, e.g. by a controller fighting the deletion.
+	WorkspaceDeletionContention conditionsv1alpha1.ConditionType = "WorkspaceDeletionContention"
+	// WorkspaceDeletionAwaitingFinalizers represents the status that instances of the workspace have been terminating
+	// for longer than a grace period, waiting for controllers to remove their finalizers.
+	WorkspaceDeletionAwaitingFinalizers conditionsv1alpha1.ConditionType = "WorkspaceDeletionAwaitingFinalizers"
+	// WorkspaceNotReadyForDeletion represents the status that the content deletion of the workspace has not started
+	// because the workspace is not in a deletable state yet, e.g. its logical cluster is still initializing.
+	WorkspaceNotReadyForDeletion conditionsv1alpha1.ConditionType = "WorkspaceNotReadyForDeletion"
+	// WorkspaceResourceDiscoverySuccess represents the status that the resources of the workspace were discovered
+	// completely for the last content deletion pass. It is False if discovery failed for some or all group versions.
+	WorkspaceResourceDiscoverySuccess conditionsv1alpha1.ConditionType = "WorkspaceResourceDiscoverySuccess"
 
 	// WorkspaceInitialized represents the status that initialization has finished.
 	WorkspaceInitialized conditionsv1alpha1.ConditionType = "WorkspaceInitialized"
@@ -58,6 +58,9 @@ var (
 
 	# create a context with the current workspace, named context-name
 	%[1]s workspace create-context context-name
+
+	# delete a workspace and wait for its content to be deleted
+	%[1]s workspace delete my-workspace --wait
 `
 )
 
@@ -72,7 +75,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 
 	cmd := &cobra.Command{
 		Aliases:          []string{"ws", "workspaces"},
-		Use:              "workspace [create|create-context|use|current|<workspace>|..|.|-|~|<root:absolute:workspace>]",
+		Use:              "workspace [create|create-context|delete|use|current|<workspace>|..|.|-|~|<root:absolute:workspace>]",
 		Short:            "Manages KCP workspaces",
 		Example:          fmt.Sprintf(workspaceExample, cliName),
 		SilenceUsage:     true,
@@ -176,6 +179,25 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 	}
 	createContextOpts.BindFlags(createContextCmd)
 
+	deleteWorkspaceOpts := plugin.NewDeleteWorkspaceOptions(streams)
+	deleteCmd := &cobra.Command{
+		Use:          "delete",
+		Short:        "Deletes a workspace",
+		Example:      "kcp workspace delete <workspace name> [--wait [--timeout=<duration>]]",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := deleteWorkspaceOpts.Complete(args); err != nil {
+				return err
+			}
+			if err := deleteWorkspaceOpts.Validate(); err != nil {
+				return err
+			}
+			return deleteWorkspaceOpts.Run(cmd.Context())
+		},
+	}
+	deleteWorkspaceOpts.BindFlags(deleteCmd)
+
 	treeCmdOpts := plugin.NewTreeOptions(streams)
 	treeCmd := &cobra.Command{
 		Use:          "tree",
@@ -201,6 +223,7 @@ func New(streams genericclioptions.IOStreams) (*cobra.Command, error) {
 	cmd.AddCommand(treeCmd)
 	cmd.AddCommand(currentCmd)
 	cmd.AddCommand(createCmd)
+	cmd.AddCommand(deleteCmd)
 	cmd.AddCommand(createContextCmd)
 	return cmd, nil
 }
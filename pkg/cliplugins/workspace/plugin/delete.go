@@ -0,0 +1,237 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	kcpdiscovery "github.com/kcp-dev/client-go/discovery"
+	kcpmetadata "github.com/kcp-dev/client-go/metadata"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/cobra"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	"github.com/kcp-dev/kcp/pkg/cliplugins/base"
+	pluginhelpers "github.com/kcp-dev/kcp/pkg/cliplugins/helpers"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+)
+
+// DeleteWorkspaceOptions contains options for deleting a workspace.
+type DeleteWorkspaceOptions struct {
+	*base.Options
+
+	// Name is the name of the workspace to delete.
+	Name string
+	// Wait waits until the content of the workspace is deleted.
+	Wait bool
+	// Timeout is how long to wait for the content of the workspace to be deleted.
+	Timeout time.Duration
+
+	kcpClusterClient kcpclientset.ClusterInterface
+
+	// for testing
+	pollInterval     time.Duration
+	remainingContent func(ctx context.Context, cluster logicalcluster.Path) (*deletion.DeletionReport, error)
+}
+
+// NewDeleteWorkspaceOptions returns a new DeleteWorkspaceOptions.
+func NewDeleteWorkspaceOptions(streams genericclioptions.IOStreams) *DeleteWorkspaceOptions {
+	return &DeleteWorkspaceOptions{
+		Options: base.NewOptions(streams),
+
+		Timeout:      5 * time.Minute,
+		pollInterval: 2 * time.Second,
+	}
+}
+
+// Complete ensures all dynamically populated fields are initialized.
+func (o *DeleteWorkspaceOptions) Complete(args []string) error {
+	if err := o.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		o.Name = args[0]
+	}
+
+	config, err := clusterConfig(o.ClientConfig)
+	if err != nil {
+		return err
+	}
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	o.kcpClusterClient = kcpClusterClient
+
+	discoveryClient, err := kcpdiscovery.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	metadataClient, err := kcpmetadata.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	o.remainingContent = func(ctx context.Context, cluster logicalcluster.Path) (*deletion.DeletionReport, error) {
+		return remainingContent(ctx, discoveryClient, metadataClient, cluster)
+	}
+
+	return nil
+}
+
+// Validate validates the DeleteWorkspaceOptions are complete and usable.
+func (o *DeleteWorkspaceOptions) Validate() error {
+	if o.Name == "" {
+		return errors.New("workspace name is required")
+	}
+	if o.Wait && o.Timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+	return o.Options.Validate()
+}
+
+// BindFlags binds fields to cmd's flagset.
+func (o *DeleteWorkspaceOptions) BindFlags(cmd *cobra.Command) {
+	o.Options.BindFlags(cmd)
+	cmd.Flags().BoolVar(&o.Wait, "wait", o.Wait, "Wait until the content of the workspace is deleted, printing the resources still remaining")
+	cmd.Flags().DurationVar(&o.Timeout, "timeout", o.Timeout, "How long to wait for the content of the workspace to be deleted")
+}
+
+// Run deletes a workspace, and with --wait waits for its content to be deleted.
+func (o *DeleteWorkspaceOptions) Run(ctx context.Context) error {
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	_, currentClusterName, err := pluginhelpers.ParseClusterURL(config.Host)
+	if err != nil {
+		return fmt.Errorf("current URL %q does not point to a workspace", config.Host)
+	}
+
+	workspaces := o.kcpClusterClient.Cluster(currentClusterName).TenancyV1alpha1().Workspaces()
+	if err := workspaces.Delete(ctx, o.Name, metav1.DeleteOptions{}); err != nil {
+		return err
+	}
+	if !o.Wait {
+		_, err := fmt.Fprintf(o.Out, "Workspace %q deleted.\n", o.Name)
+		return err
+	}
+	if _, err := fmt.Fprintf(o.Out, "Workspace %q deleted. Waiting for its content to be deleted...\n", o.Name); err != nil {
+		return err
+	}
+
+	var lastTable, lastMessage string
+	err = wait.PollImmediate(o.pollInterval, o.Timeout, func() (bool, error) {
+		ws, err := workspaces.Get(ctx, o.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		if conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+			return true, nil
+		}
+		lastMessage = conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted)
+
+		report, err := o.remainingContent(ctx, currentClusterName.Join(o.Name))
+		if err != nil {
+			// the content may already be inaccessible while the workspace goes away.
+			if _, err := fmt.Fprintf(o.ErrOut, "Unable to list the remaining content: %v\n", err); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+		if table := remainingTable(report); table != lastTable {
+			lastTable = table
+			if _, err := fmt.Fprint(o.Out, table); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		stalled := lastTable
+		if stalled == "" {
+			stalled = lastMessage + "\n"
+		}
+		if _, err := fmt.Fprintf(o.Out, "Content of workspace %q is still remaining after %s:\n%s", o.Name, o.Timeout, stalled); err != nil {
+			return err
+		}
+		return fmt.Errorf("timed out waiting for the content of workspace %q to be deleted", o.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(o.Out, "Content of workspace %q is deleted.\n", o.Name)
+	return err
+}
+
+// remainingContent returns the instances of the resources that are deleted with a logical cluster
+// that remain in the given workspace.
+func remainingContent(ctx context.Context, discoveryClient kcpdiscovery.DiscoveryClusterInterface, metadataClient kcpmetadata.ClusterInterface, cluster logicalcluster.Path) (*deletion.DeletionReport, error) {
+	// discovery errors are not fatal, the content of the discovered groups is still listed.
+	_, resources, err := discoveryClient.Cluster(cluster).ServerGroupsAndResources()
+	if err != nil && len(resources) == 0 {
+		return nil, err
+	}
+
+	report := &deletion.DeletionReport{}
+	for _, gvr := range deletion.DeletableResources(resources) {
+		list, err := metadataClient.Cluster(cluster).Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			report.Resources = append(report.Resources, deletion.ResourceReport{GVR: gvr, Found: -1, Err: err})
+			continue
+		}
+		if len(list.Items) > 0 {
+			report.Resources = append(report.Resources, deletion.ResourceReport{GVR: gvr, Found: -1, Remaining: len(list.Items)})
+		}
+	}
+	return report, nil
+}
+
+// remainingTable renders the resources with remaining instances as a table.
+func remainingTable(report *deletion.DeletionReport) string {
+	if report == nil || len(report.Resources) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tREMAINING")
+	for _, rr := range report.Resources {
+		name := rr.GVR.GroupResource().String()
+		if rr.Err != nil {
+			fmt.Fprintf(w, "%s\t%v\n", name, rr.Err)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\n", name, rr.Remaining)
+	}
+	w.Flush()
+	return buf.String()
+}
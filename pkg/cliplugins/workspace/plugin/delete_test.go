@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+)
+
+func TestDelete(t *testing.T) {
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	contentRemaining := conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceContentDeleted,
+		Status:   corev1.ConditionFalse,
+		Severity: conditionsv1alpha1.ConditionSeverityInfo,
+		Reason:   "SomeResourcesRemain",
+		Message:  "Some resources are remaining",
+	}
+	contentDeleted := conditionsv1alpha1.Condition{
+		Type:   tenancyv1alpha1.WorkspaceContentDeleted,
+		Status: corev1.ConditionTrue,
+	}
+
+	tests := []struct {
+		name string
+		wait bool
+		// polls are the WorkspaceContentDeleted conditions of successive polls. Nil is a deleted workspace.
+		polls   []*conditionsv1alpha1.Condition
+		reports []*deletion.DeletionReport
+
+		wantErr    bool
+		wantOutput []string
+	}{
+		{
+			name:       "no wait",
+			wantOutput: []string{`Workspace "bar" deleted.`},
+		},
+		{
+			name:  "wait until content deleted",
+			wait:  true,
+			polls: []*conditionsv1alpha1.Condition{&contentRemaining, &contentRemaining, &contentDeleted},
+			reports: []*deletion.DeletionReport{
+				{Resources: []deletion.ResourceReport{{GVR: namespaces, Remaining: 2}, {GVR: crds, Remaining: 1}}},
+				{Resources: []deletion.ResourceReport{{GVR: crds, Remaining: 1}}},
+			},
+			wantOutput: []string{
+				"namespaces 2",
+				"customresourcedefinitions.apiextensions.k8s.io 1",
+				`Content of workspace "bar" is deleted.`,
+			},
+		},
+		{
+			name:       "wait until workspace gone",
+			wait:       true,
+			polls:      []*conditionsv1alpha1.Condition{&contentRemaining, nil},
+			reports:    []*deletion.DeletionReport{{}},
+			wantOutput: []string{`Content of workspace "bar" is deleted.`},
+		},
+		{
+			name:    "timeout",
+			wait:    true,
+			polls:   []*conditionsv1alpha1.Condition{&contentRemaining},
+			reports: []*deletion.DeletionReport{{Resources: []deletion.ResourceReport{{GVR: crds, Remaining: 1}}}},
+			wantErr: true,
+			wantOutput: []string{
+				`Content of workspace "bar" is still remaining after`,
+				"customresourcedefinitions.apiextensions.k8s.io 1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			currentClusterName := logicalcluster.NewPath("root:foo")
+			client := kcpfakeclient.NewSimpleClientset(&tenancyv1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "bar",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: currentClusterName.String()},
+				},
+			})
+			polls := 0
+			client.PrependReactor("get", "workspaces", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				condition := tt.polls[len(tt.polls)-1]
+				if polls < len(tt.polls) {
+					condition = tt.polls[polls]
+				}
+				polls++
+				if condition == nil {
+					return true, nil, errors.NewNotFound(tenancyv1alpha1.Resource("workspaces"), "bar")
+				}
+				now := metav1.Now()
+				return true, &tenancyv1alpha1.Workspace{
+					ObjectMeta: metav1.ObjectMeta{Name: "bar", DeletionTimestamp: &now},
+					Status:     tenancyv1alpha1.WorkspaceStatus{Conditions: conditionsv1alpha1.Conditions{*condition}},
+				}, nil
+			})
+
+			out := &bytes.Buffer{}
+			opts := NewDeleteWorkspaceOptions(genericclioptions.IOStreams{In: &bytes.Buffer{}, Out: out, ErrOut: out})
+			opts.Name = "bar"
+			opts.Wait = tt.wait
+			opts.Timeout = wait.ForeverTestTimeout
+			if tt.wantErr {
+				opts.Timeout = 100 * time.Millisecond
+			}
+			opts.pollInterval = 10 * time.Millisecond
+			opts.kcpClusterClient = client
+			reports := 0
+			opts.remainingContent = func(ctx context.Context, cluster logicalcluster.Path) (*deletion.DeletionReport, error) {
+				require.Equal(t, currentClusterName.Join("bar"), cluster)
+				report := tt.reports[len(tt.reports)-1]
+				if reports < len(tt.reports) {
+					report = tt.reports[reports]
+				}
+				reports++
+				return report, nil
+			}
+			opts.ClientConfig = clientcmd.NewDefaultClientConfig(clientcmdapi.Config{CurrentContext: "test",
+				Contexts:  map[string]*clientcmdapi.Context{"test": {Cluster: "test", AuthInfo: "test"}},
+				Clusters:  map[string]*clientcmdapi.Cluster{"test": {Server: "https://test/clusters/root:foo"}},
+				AuthInfos: map[string]*clientcmdapi.AuthInfo{"test": {Token: "test"}},
+			}, nil)
+
+			err := opts.Run(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			// ignore the alignment of table columns.
+			output := regexp.MustCompile(` +`).ReplaceAllString(out.String(), " ")
+			for _, expected := range tt.wantOutput {
+				if !strings.Contains(output, expected) {
+					t.Errorf("expected output to contain %q, got:\n%s", expected, out.String())
+				}
+			}
+
+			var deleted bool
+			for _, action := range client.Actions() {
+				if action.Matches("delete", "workspaces") {
+					deleted = true
+				}
+			}
+			require.True(t, deleted, "expected the workspace to be deleted")
+		})
+	}
+}
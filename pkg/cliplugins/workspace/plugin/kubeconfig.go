@@ -752,6 +752,16 @@ func (o *CreateContextOptions) Run(ctx context.Context) error {
 }
 
 func newKCPClusterClient(clientConfig clientcmd.ClientConfig) (kcpclientset.ClusterInterface, error) {
+	config, err := clusterConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	return kcpclientset.NewForConfig(config)
+}
+
+// clusterConfig returns the rest config of the kcp server the client config points to, without
+// the workspace path.
+func clusterConfig(clientConfig clientcmd.ClientConfig) (*rest.Config, error) {
 	config, err := clientConfig.ClientConfig()
 	if err != nil {
 		return nil, err
@@ -764,7 +774,7 @@ func newKCPClusterClient(clientConfig clientcmd.ClientConfig) (kcpclientset.Clus
 	u.Path = ""
 	clusterConfig.Host = u.String()
 	clusterConfig.UserAgent = rest.DefaultKubernetesUserAgent()
-	return kcpclientset.NewForConfig(clusterConfig)
+	return clusterConfig, nil
 }
 
 // TreeOptions contains options for displaying the workspace tree.
@@ -80,6 +80,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.ResourceSelector":                            schema_pkg_apis_apis_v1alpha1_ResourceSelector(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.VirtualWorkspace":                            schema_pkg_apis_apis_v1alpha1_VirtualWorkspace(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalCluster":                              schema_pkg_apis_core_v1alpha1_LogicalCluster(ref),
+		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterDeletionStatus":                schema_pkg_apis_core_v1alpha1_LogicalClusterDeletionStatus(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterList":                          schema_pkg_apis_core_v1alpha1_LogicalClusterList(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterOwner":                         schema_pkg_apis_core_v1alpha1_LogicalClusterOwner(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterSpec":                          schema_pkg_apis_core_v1alpha1_LogicalClusterSpec(ref),
@@ -2617,6 +2618,55 @@ func schema_pkg_apis_core_v1alpha1_LogicalCluster(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_LogicalClusterDeletionStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogicalClusterDeletionStatus reports the progress of the content deletion of a logical cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "phase is the state of the content deletion (NotStarted, InProgress, Failed, Completed).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"remaining": {
+						SchemaProps: spec.SchemaProps{
+							Description: "remaining is the number of resource instances remaining after the last deletion pass.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"lastProcessedResource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "lastProcessedResource is the resource, in resource.group format, processed last by the last deletion pass.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastUpdated": {
+						SchemaProps: spec.SchemaProps{
+							Description: "lastUpdated is the time the deletion status was last updated.",
+							Default:     map[string]interface{}{},
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"estimatedCompletion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "estimatedCompletion is the time the content deletion is estimated to complete, extrapolated from the deletion rate observed across deletion passes. It is unset while there is no estimate.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_LogicalClusterList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2820,11 +2870,17 @@ func schema_pkg_apis_core_v1alpha1_LogicalClusterStatus(ref common.ReferenceCall
 							},
 						},
 					},
+					"deletion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "deletion reports the progress of the content deletion once the logical cluster is deleted.",
+							Ref:         ref("github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterDeletionStatus"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1.Condition"},
+			"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterDeletionStatus", "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1.Condition"},
 	}
 }
 
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// DeletionAttemptsAnnotationKey counts the content deletion passes of a LogicalCluster that failed. It is only maintained if a maximum number of attempts is configured. Removing it resets
+// the count, and resumes the deletion once it has failed terminally.
+const DeletionAttemptsAnnotationKey = "internal.core.kcp.io/deletion-attempts"
+
+// DeletionAttemptsExceededError is returned once the content deletion of a logical cluster has failed
+// the maximum number of attempts. The deletion is not attempted again, it should not be requeued.
+type DeletionAttemptsExceededError struct {
+	Attempts int
+	// Err is the error of the last attempt. Nil if no attempt was made.
+	Err error
+}
+
+func (e *DeletionAttemptsExceededError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("content deletion gave up after %d attempts", e.Attempts)
+	}
+	return fmt.Sprintf("content deletion gave up after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *DeletionAttemptsExceededError) Unwrap() error {
+	return e.Err
+}
+
+// deletionAttempts returns the number of failed deletion attempts recorded on the logical cluster.
+func deletionAttempts(logicalCluster *corev1alpha1.LogicalCluster) int {
+	attempts, err := strconv.Atoi(logicalCluster.Annotations[DeletionAttemptsAnnotationKey])
+	if err != nil || attempts < 0 {
+		return 0
+	}
+	return attempts
+}
+
+// countAttempt records a deletion pass that failed with err. Remaining content is not a failure, so a
+// ResourcesRemainingError is returned unchanged without counting. Once the maximum number of attempts is
+// reached, the WorkspaceDeletionFailedTerminal condition is set and a DeletionAttemptsExceededError
+// wrapping err is returned. Otherwise, err is returned unchanged.
+func (d *logicalClusterResourcesDeleter) countAttempt(logicalCluster *corev1alpha1.LogicalCluster, err error) error {
+	if d.maxAttempts <= 0 || errors.Is(err, context.Canceled) {
+		// e.g. on shutdown. This says nothing about the deletion.
+		return err
+	}
+	if errors.Is(err, ErrResourcesRemaining) {
+		// e.g. content waiting for finalizers, which is expected to go away.
+		return err
+	}
+	attempts := deletionAttempts(logicalCluster) + 1
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[DeletionAttemptsAnnotationKey] = strconv.Itoa(attempts)
+	if attempts < d.maxAttempts {
+		return err
+	}
+
+	exceeded := &DeletionAttemptsExceededError{Attempts: attempts, Err: err}
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceDeletionFailedTerminal,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsv1alpha1.ConditionSeverityError,
+		Reason:   "MaxAttemptsExceeded",
+		Message:  truncateFailure(exceeded),
+	})
+	d.event(logicalCluster, corev1.EventTypeWarning, eventReasonDeletionFailed, "Gave up deleting the content after %d attempts", attempts)
+	return exceeded
+}
+
+// attemptsExceeded returns a DeletionAttemptsExceededError if the logical cluster has already failed
+// the maximum number of deletion attempts. Otherwise, e.g. after the count has been reset, the
+// WorkspaceDeletionFailedTerminal condition is removed and nil is returned.
+func (d *logicalClusterResourcesDeleter) attemptsExceeded(logicalCluster *corev1alpha1.LogicalCluster) error {
+	if d.maxAttempts <= 0 {
+		return nil
+	}
+	if attempts := deletionAttempts(logicalCluster); attempts >= d.maxAttempts {
+		return &DeletionAttemptsExceededError{Attempts: attempts}
+	}
+	conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceDeletionFailedTerminal)
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// deleteItems deletes the given items one by one, in concurrent batches. The batch size doubles
+// after every batch the server handled without throttling, up to the configured maximum, and is
+// halved when the server throttles or times out, in which case the affected items are retried.
+// Without batching configured, items are deleted serially.
+//
+// It returns the items that were actually deleted by this call.
+func (d *logicalClusterResourcesDeleter) deleteItems(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) ([]metav1.PartialObjectMetadata, error) {
+	logger := klog.FromContext(ctx)
+
+	size, max := 1, 1
+	if d.deleteBatchMax > 1 {
+		size, max = d.deleteBatchInitial, d.deleteBatchMax
+	}
+
+	pending := items
+	deleted := make([]metav1.PartialObjectMetadata, 0, len(items))
+	for len(pending) > 0 {
+		n := size
+		if n > len(pending) {
+			n = len(pending)
+		}
+		batch := pending[:n]
+		pending = pending[n:]
+
+		errs := make([]error, len(batch))
+		var wg sync.WaitGroup
+		for i := range batch {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				item := &batch[i]
+				if errs[i] = spendDeletionBudget(ctx); errs[i] != nil {
+					return
+				}
+				if errs[i] = d.throttle(ctx); errs[i] != nil {
+					return
+				}
+				errs[i] = d.resourceClient(clusterName, gvr).Namespace(item.Namespace).Delete(ctx, item.Name, d.deleteOptions())
+			}(i)
+		}
+		wg.Wait()
+
+		var retry []metav1.PartialObjectMetadata
+		var failed []error
+		for i, err := range errs {
+			switch {
+			case err == nil:
+				deleted = append(deleted, batch[i])
+				recordDeletionIssued(ctx)
+			case errors.IsNotFound(err) || errors.IsMethodNotSupported(err):
+			case isThrottled(err) && n > 1:
+				retry = append(retry, batch[i])
+			default:
+				failed = append(failed, err)
+			}
+		}
+		if len(failed) > 0 {
+			return deleted, utilerrors.NewAggregate(failed)
+		}
+
+		if len(retry) > 0 {
+			size = n / 2
+			logger.V(4).Info("server is throttling deletions, decreasing batch size", "batchSize", size, "retrying", len(retry))
+			pending = append(retry, pending...)
+		} else if size < max {
+			size *= 2
+			if size > max {
+				size = max
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+// isThrottled returns true if the server asks to back off.
+func isThrottled(err error) bool {
+	return errors.IsTooManyRequests(err) || errors.IsServerTimeout(err) || errors.IsTimeout(err)
+}
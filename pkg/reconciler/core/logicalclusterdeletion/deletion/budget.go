@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// deletionBudgetEstimate is the estimate in seconds before resources deferred by the deletion budget
+// are deleted in the next pass.
+const deletionBudgetEstimate = int64(1)
+
+// errDeletionBudgetExhausted is returned for deletion calls exceeding the deletion budget of a pass.
+var errDeletionBudgetExhausted = errors.New("deletion budget of the pass is exhausted")
+
+// deletionBudget counts the delete and delete-collection calls a deletion pass may still issue. It is
+// shared by the workers of the pass.
+type deletionBudget struct {
+	lock      sync.Mutex
+	remaining int
+}
+
+type deletionBudgetKey struct{}
+
+// withDeletionBudget returns a context carrying a fresh deletion budget for a pass, if one is configured.
+func (d *logicalClusterResourcesDeleter) withDeletionBudget(ctx context.Context) context.Context {
+	if d.maxDeletionsPerPass <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, deletionBudgetKey{}, &deletionBudget{remaining: d.maxDeletionsPerPass})
+}
+
+// spendDeletionBudget takes one call from the deletion budget of the pass, or returns
+// errDeletionBudgetExhausted if none is left. Without a budget, it always succeeds.
+func spendDeletionBudget(ctx context.Context) error {
+	budget, ok := ctx.Value(deletionBudgetKey{}).(*deletionBudget)
+	if !ok {
+		return nil
+	}
+	budget.lock.Lock()
+	defer budget.lock.Unlock()
+	if budget.remaining <= 0 {
+		return errDeletionBudgetExhausted
+	}
+	budget.remaining--
+	return nil
+}
+
+// isDeletionBudgetExhausted returns true if err was caused by an exhausted deletion budget.
+func isDeletionBudgetExhausted(err error) bool {
+	return errors.Is(err, errDeletionBudgetExhausted)
+}
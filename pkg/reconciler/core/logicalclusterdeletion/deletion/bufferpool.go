@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxPooledListItems bounds the capacity of buffers kept in a ListBufferPool, such that a single huge
+// list does not pin its memory for the lifetime of the pool.
+const maxPooledListItems = 64 * 1024
+
+// ListBufferPool reuses the buffers collecting the items of paginated lists across resources, passes
+// and logical clusters, which saves growing a new buffer page by page for every list. It is safe for
+// concurrent use and can be shared by deleters.
+type ListBufferPool struct {
+	pool sync.Pool
+}
+
+// NewListBufferPool returns an empty ListBufferPool.
+func NewListBufferPool() *ListBufferPool {
+	return &ListBufferPool{}
+}
+
+// get returns an empty buffer, with the capacity of a released one if available.
+func (p *ListBufferPool) get() []metav1.PartialObjectMetadata {
+	if buf, ok := p.pool.Get().(*[]metav1.PartialObjectMetadata); ok {
+		return (*buf)[:0]
+	}
+	return nil
+}
+
+// put releases a buffer for reuse. The buffer must not be used by the caller afterwards.
+func (p *ListBufferPool) put(buf []metav1.PartialObjectMetadata) {
+	if cap(buf) == 0 || cap(buf) > maxPooledListItems {
+		return
+	}
+	// drop the references to the objects of the previous list.
+	buf = buf[:cap(buf)]
+	for i := range buf {
+		buf[i] = metav1.PartialObjectMetadata{}
+	}
+	buf = buf[:0]
+	p.pool.Put(&buf)
+}
+
+// releaseList hands the items of a list that is not used anymore back to the buffer pool, if any.
+// Buffers of single pages are not worth pooling, they would only be outgrown by the next list.
+func (d *logicalClusterResourcesDeleter) releaseList(list *metav1.PartialObjectMetadataList) {
+	if d.bufferPool == nil || list == nil || d.listPageSize <= 0 || len(list.Items) <= int(d.listPageSize) {
+		return
+	}
+	d.bufferPool.put(list.Items)
+	list.Items = nil
+}
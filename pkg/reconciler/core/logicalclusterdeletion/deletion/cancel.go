@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// DeletionIssuedAnnotationKey records when a content deletion pass of a LogicalCluster first succeeded
+// to issue a delete or delete-collection call, in RFC 3339 format. From then on, its content deletion
+// cannot be cancelled anymore.
+const DeletionIssuedAnnotationKey = "deletion.kcp.io/deletion-issued"
+
+// DeletionCancelledAnnotationKey records when the content deletion of a LogicalCluster was cancelled,
+// in RFC 3339 format. Deletion passes do not delete anything while it is set.
+const DeletionCancelledAnnotationKey = "deletion.kcp.io/deletion-cancelled"
+
+var (
+	// ErrDeletionCancelled is returned by Delete for logical clusters whose content deletion was cancelled.
+	ErrDeletionCancelled = errors.New("content deletion was cancelled")
+	// ErrDeletionNotCancellable is returned by CancelDeletion if the content deletion cannot be cancelled.
+	ErrDeletionNotCancellable = errors.New("content deletion cannot be cancelled")
+)
+
+// cancelledDeletionConditions are the conditions of a content deletion that are cleared when it is cancelled.
+var cancelledDeletionConditions = []conditionsv1alpha1.ConditionType{
+	tenancyv1alpha1.WorkspaceContentDeleted,
+	tenancyv1alpha1.WorkspaceDeletionStalled,
+	tenancyv1alpha1.WorkspaceDeletionForbidden,
+	tenancyv1alpha1.WorkspaceDeletionContention,
+	tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers,
+	tenancyv1alpha1.WorkspaceNotReadyForDeletion,
+}
+
+// CancelDeletion cancels the content deletion of a LogicalCluster that has not deleted anything yet,
+// i.e. whose passes have only listed its content so far. It clears the deletion conditions and sets
+// DeletionCancelledAnnotationKey, which stops later passes. The caller persists the LogicalCluster.
+// It returns ErrDeletionNotCancellable if the LogicalCluster is not being deleted, or a delete or
+// delete-collection call has succeeded already.
+func (d *logicalClusterResourcesDeleter) CancelDeletion(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+	if logicalCluster.DeletionTimestamp.IsZero() {
+		return fmt.Errorf("%w: logical cluster %s is not being deleted", ErrDeletionNotCancellable, logicalcluster.From(logicalCluster))
+	}
+	if issued, ok := logicalCluster.Annotations[DeletionIssuedAnnotationKey]; ok {
+		return fmt.Errorf("%w: content of logical cluster %s is being deleted since %s", ErrDeletionNotCancellable, logicalcluster.From(logicalCluster), issued)
+	}
+	for _, t := range cancelledDeletionConditions {
+		conditions.Delete(logicalCluster, t)
+	}
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[DeletionCancelledAnnotationKey] = d.clock.Now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+// checkCancelled returns ErrDeletionCancelled if the content deletion of the logical cluster was cancelled.
+func checkCancelled(logicalCluster *corev1alpha1.LogicalCluster) error {
+	if _, ok := logicalCluster.Annotations[DeletionCancelledAnnotationKey]; ok {
+		return fmt.Errorf("%w for logical cluster %s", ErrDeletionCancelled, logicalcluster.From(logicalCluster))
+	}
+	return nil
+}
+
+type deletionIssuedKey struct{}
+
+// withDeletionIssued returns a context recording whether a pass succeeded to issue a deletion call.
+func withDeletionIssued(ctx context.Context) context.Context {
+	return context.WithValue(ctx, deletionIssuedKey{}, new(int32))
+}
+
+// recordDeletionIssued records in ctx that a delete or delete-collection call succeeded.
+func recordDeletionIssued(ctx context.Context) {
+	if issued, ok := ctx.Value(deletionIssuedKey{}).(*int32); ok {
+		atomic.StoreInt32(issued, 1)
+	}
+}
+
+// markDeletionIssued sets DeletionIssuedAnnotationKey to the current time if a deletion call recorded
+// in ctx succeeded, unless an earlier pass already did.
+func (d *logicalClusterResourcesDeleter) markDeletionIssued(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) {
+	issued, ok := ctx.Value(deletionIssuedKey{}).(*int32)
+	if !ok || atomic.LoadInt32(issued) == 0 {
+		return
+	}
+	if _, ok := logicalCluster.Annotations[DeletionIssuedAnnotationKey]; ok {
+		return
+	}
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[DeletionIssuedAnnotationKey] = d.clock.Now().UTC().Format(time.RFC3339)
+}
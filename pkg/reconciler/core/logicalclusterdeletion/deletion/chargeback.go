@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+var namespacesGVR = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+// NamespaceLabelAggregator aggregates the number of objects deleted one by one per value
+// of a set of namespace labels, e.g. team or cost-center for chargeback. Objects in namespaces
+// without the label are counted with the empty value. It is safe for concurrent use.
+type NamespaceLabelAggregator struct {
+	keys []string
+
+	lock   sync.Mutex
+	counts map[string]map[string]int
+}
+
+// NewNamespaceLabelAggregator returns an aggregator for the given namespace label keys.
+func NewNamespaceLabelAggregator(keys ...string) *NamespaceLabelAggregator {
+	counts := make(map[string]map[string]int, len(keys))
+	for _, key := range keys {
+		counts[key] = map[string]int{}
+	}
+	return &NamespaceLabelAggregator{
+		keys:   keys,
+		counts: counts,
+	}
+}
+
+// Counts returns a copy of the deleted object counts by label value for the given label key.
+func (a *NamespaceLabelAggregator) Counts(key string) map[string]int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	ret := make(map[string]int, len(a.counts[key]))
+	for value, count := range a.counts[key] {
+		ret[value] = count
+	}
+	return ret
+}
+
+func (a *NamespaceLabelAggregator) add(namespaceLabels map[string]string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, key := range a.keys {
+		a.counts[key][namespaceLabels[key]]++
+	}
+}
+
+// namespaceLabelCache looks up namespace labels once per deletion pass.
+type namespaceLabelCache map[string]map[string]string
+
+func (d *logicalClusterResourcesDeleter) namespaceLabels(ctx context.Context, clusterName logicalcluster.Name, cache namespaceLabelCache, namespace string) map[string]string {
+	if labels, ok := cache[namespace]; ok {
+		return labels
+	}
+
+	ns, err := d.resourceClient(clusterName, namespacesGVR).Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.FromContext(ctx).V(4).Error(err, "unable to get namespace labels", "namespace", namespace)
+		}
+		cache[namespace] = nil
+		return nil
+	}
+	cache[namespace] = ns.Labels
+	return ns.Labels
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// DeletionCheckpointAnnotationKey lists the resources, as comma-separated resource.group, that were found
+// empty by earlier content deletion passes of a LogicalCluster. It is only maintained if checkpointing is
+// enabled. Removing it makes the next pass process all resources.
+const DeletionCheckpointAnnotationKey = "internal.core.kcp.io/deletion-checkpoint"
+
+// checkpointVerifyEstimate is the estimate in seconds before the final pass verifying the resources of
+// the checkpoint.
+const checkpointVerifyEstimate = int64(1)
+
+// deletionCheckpoint returns the resources recorded as empty on the logical cluster, as resource.group.
+func deletionCheckpoint(logicalCluster *corev1alpha1.LogicalCluster) sets.String {
+	completed := sets.NewString()
+	for _, name := range strings.Split(logicalCluster.Annotations[DeletionCheckpointAnnotationKey], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			completed.Insert(name)
+		}
+	}
+	return completed
+}
+
+// partitionCheckpointed removes the resources recorded as empty by earlier passes from gvrs, and returns
+// how many were removed.
+func (d *logicalClusterResourcesDeleter) partitionCheckpointed(logicalCluster *corev1alpha1.LogicalCluster, gvrs map[schema.GroupVersionResource]sets.String) (map[schema.GroupVersionResource]sets.String, int) {
+	if !d.checkpoint {
+		return gvrs, 0
+	}
+	completed := deletionCheckpoint(logicalCluster)
+	if completed.Len() == 0 {
+		return gvrs, 0
+	}
+	remaining := make(map[schema.GroupVersionResource]sets.String, len(gvrs))
+	for gvr, verbs := range gvrs {
+		if !completed.Has(gvr.GroupResource().String()) {
+			remaining[gvr] = verbs
+		}
+	}
+	return remaining, len(gvrs) - len(remaining)
+}
+
+// addToDeletionCheckpoint records the resources found empty in a pass on the logical cluster.
+func (d *logicalClusterResourcesDeleter) addToDeletionCheckpoint(logicalCluster *corev1alpha1.LogicalCluster, emptied []schema.GroupVersionResource) {
+	if !d.checkpoint || len(emptied) == 0 {
+		return
+	}
+	completed := deletionCheckpoint(logicalCluster)
+	for _, gvr := range emptied {
+		completed.Insert(gvr.GroupResource().String())
+	}
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[DeletionCheckpointAnnotationKey] = strings.Join(completed.List(), ",")
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// setDeletionConditions sets the WorkspaceContentDeleted condition of the logical cluster. All content
+// deletion outcomes go through here, such that the deletion conditions never contradict each other:
+// once the content is deleted, the logical cluster is not reported as stalled anymore.
+func setDeletionConditions(logicalCluster *corev1alpha1.LogicalCluster, status corev1.ConditionStatus, reason string, severity conditionsv1alpha1.ConditionSeverity, message string) {
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceContentDeleted,
+		Status:   status,
+		Severity: severity,
+		Reason:   reason,
+		Message:  message,
+	})
+	if status == corev1.ConditionTrue {
+		conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceDeletionStalled)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// DeletionHaltedAnnotationKey lists the resources, as comma-separated resource.group, whose deletion
+// was halted because their instances were recreated during deletion. It is only maintained if halting
+// is enabled. Removing a resource from the list resumes its deletion.
+const DeletionHaltedAnnotationKey = "internal.core.kcp.io/deletion-halted"
+
+// haltedResources returns the resources whose deletion is halted, as resource.group.
+func haltedResources(logicalCluster *corev1alpha1.LogicalCluster) sets.String {
+	halted := sets.NewString()
+	for _, name := range strings.Split(logicalCluster.Annotations[DeletionHaltedAnnotationKey], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			halted.Insert(name)
+		}
+	}
+	return halted
+}
+
+// partitionHalted moves the resources whose deletion is halted from deletable to protected, such that
+// they are not deleted, but block the completion of the content deletion while instances remain.
+func partitionHalted(logicalCluster *corev1alpha1.LogicalCluster, deletable, protected map[schema.GroupVersionResource]sets.String) (map[schema.GroupVersionResource]sets.String, map[schema.GroupVersionResource]sets.String) {
+	halted := haltedResources(logicalCluster)
+	if halted.Len() == 0 {
+		return deletable, protected
+	}
+	if protected == nil {
+		protected = map[schema.GroupVersionResource]sets.String{}
+	}
+	for gvr, verbs := range deletable {
+		if halted.Has(gvr.GroupResource().String()) {
+			protected[gvr] = verbs
+			delete(deletable, gvr)
+		}
+	}
+	return deletable, protected
+}
+
+// markContention compares the remaining instances of the pass with those of the previous report
+// supplied by the caller. It sets the WorkspaceDeletionContention condition naming the resources
+// whose remaining instances grew, i.e. that something recreates faster than they are deleted, whose
+// instances were recreated under the same name, or whose deletion is halted. Otherwise, the condition
+// is removed.
+func (d *logicalClusterResourcesDeleter) markContention(logicalCluster *corev1alpha1.LogicalCluster, report *DeletionReport) {
+	if (d.previousReport == nil && d.recreation == nil) || report == nil {
+		return
+	}
+	contended := sets.NewString()
+	if d.previousReport != nil {
+		if previous := d.previousReport(logicalcluster.From(logicalCluster)); previous != nil {
+			for _, rd := range DiffReports(*previous, *report).Growing() {
+				contended.Insert(rd.GVR.GroupResource().String())
+			}
+		}
+	}
+	for _, rr := range report.Resources {
+		if len(rr.Recreated) > 0 {
+			contended.Insert(rr.GVR.GroupResource().String())
+		}
+	}
+
+	halted := haltedResources(logicalCluster)
+	if d.haltContended && contended.Len() > 0 {
+		halted = halted.Union(contended)
+		if logicalCluster.Annotations == nil {
+			logicalCluster.Annotations = map[string]string{}
+		}
+		logicalCluster.Annotations[DeletionHaltedAnnotationKey] = strings.Join(halted.List(), ",")
+	}
+
+	if contended.Len() == 0 && halted.Len() == 0 {
+		conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceDeletionContention)
+		return
+	}
+	var messages []string
+	if contended.Len() > 0 {
+		messages = append(messages, fmt.Sprintf("Instances of %s are recreated during deletion", strings.Join(contended.List(), ", ")))
+	}
+	if halted.Len() > 0 {
+		messages = append(messages, fmt.Sprintf("Deletion of %s is halted until removed from the %s annotation", strings.Join(halted.List(), ", "), DeletionHaltedAnnotationKey))
+	}
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceDeletionContention,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsv1alpha1.ConditionSeverityWarning,
+		Reason:   "ObjectsRecreated",
+		Message:  strings.Join(messages, "; "),
+	})
+}
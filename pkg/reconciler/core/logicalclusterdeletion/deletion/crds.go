@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// customResourceDefinitionsGroupResource is the resource of the CRDs defining the custom resources of a logical cluster.
+var customResourceDefinitionsGroupResource = schema.GroupResource{Group: apiextensions.GroupName, Resource: "customresourcedefinitions"}
+
+// splitCustomResourceDefinitions separates the CRD resource from the other resources of a phase,
+// keeping the order of both.
+func splitCustomResourceDefinitions(phase []schema.GroupVersionResource) (resources, crds []schema.GroupVersionResource) {
+	for _, gvr := range phase {
+		if gvr.GroupResource() == customResourceDefinitionsGroupResource {
+			crds = append(crds, gvr)
+		} else {
+			resources = append(resources, gvr)
+		}
+	}
+	return resources, crds
+}
+
+// deleteCustomResourceDefinitions deletes the CRDs after the other resources of their phase, whose
+// results are given. If instances of custom resources defined by the CRDs remain, the CRDs are not
+// deleted in this pass, such that the instances are not orphaned by a CRD that is gone. The results
+// of deferred CRDs name the resources they wait for.
+func (d *logicalClusterResourcesDeleter) deleteCustomResourceDefinitions(
+	ctx context.Context,
+	clusterName logicalcluster.Name,
+	crds []schema.GroupVersionResource,
+	results []gvrDeletionResult,
+	groupVersionResources map[schema.GroupVersionResource]sets.String,
+	clusterDeletedAt metav1.Time,
+) []gvrDeletionResult {
+	blocking := d.remainingCustomResources(ctx, clusterName, crds, results, groupVersionResources)
+	if len(blocking) == 0 {
+		return d.deleteResources(ctx, clusterName, crds, groupVersionResources, clusterDeletedAt)
+	}
+
+	klog.FromContext(ctx).V(4).Info("deferring deletion of CRDs until their instances are gone", "resources", blocking)
+	ret := make([]gvrDeletionResult, 0, len(crds))
+	for _, crd := range crds {
+		ret = append(ret, gvrDeletionResult{gvr: crd, metadata: gvrDeletionMetadata{numFound: -1}, deferredBy: blocking})
+	}
+	return ret
+}
+
+// remainingCustomResources returns the resources of results that are defined by one of the CRDs,
+// and whose instances remain or failed to be deleted. The CRDs are only listed if some resource
+// remains. If they cannot be listed, all remaining resources are assumed to be custom resources.
+func (d *logicalClusterResourcesDeleter) remainingCustomResources(
+	ctx context.Context,
+	clusterName logicalcluster.Name,
+	crds []schema.GroupVersionResource,
+	results []gvrDeletionResult,
+	groupVersionResources map[schema.GroupVersionResource]sets.String,
+) []schema.GroupVersionResource {
+	var remaining []schema.GroupVersionResource
+	for _, result := range results {
+		if !result.gvr.Empty() && (result.err != nil || result.metadata.numRemaining > 0) {
+			remaining = append(remaining, result.gvr)
+		}
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+
+	// CRDs are named after the plural and group of the resource they define.
+	defined := sets.NewString()
+	for _, crd := range crds {
+		list, listSupported, err := d.listCollection(ctx, clusterName, crd, groupVersionResources[crd])
+		if err != nil || !listSupported {
+			klog.FromContext(ctx).V(4).Info("unable to list CRDs, assuming remaining resources are custom resources", "err", err)
+			return remaining
+		}
+		for _, item := range list.Items {
+			defined.Insert(item.Name)
+		}
+	}
+
+	var ret []schema.GroupVersionResource
+	for _, gvr := range remaining {
+		if defined.Has(gvr.Resource + "." + gvr.Group) {
+			ret = append(ret, gvr)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// ClustersRemainingError is returned by DeleteAll if content remains in some of the logical clusters.
+type ClustersRemainingError struct {
+	// Remaining is the ResourcesRemainingError by logical cluster with remaining content.
+	Remaining map[logicalcluster.Name]*ResourcesRemainingError
+}
+
+func (e *ClustersRemainingError) Error() string {
+	clusters := make([]string, 0, len(e.Remaining))
+	for clusterName := range e.Remaining {
+		clusters = append(clusters, clusterName.String())
+	}
+	sort.Strings(clusters)
+	return fmt.Sprintf("some content remains in logical clusters %s", strings.Join(clusters, ", "))
+}
+
+// DeleteAll runs Delete for the LogicalCluster returned by lcFor for each of the given logical clusters,
+// e.g. to cascade the deletion into child logical clusters. Up to the configured number of workers
+// logical clusters are processed concurrently. Logical clusters for which lcFor returns nil are skipped.
+// Remaining content is aggregated into a ClustersRemainingError, which is returned as is if there are
+// no other errors.
+func (d *logicalClusterResourcesDeleter) DeleteAll(ctx context.Context, clusters []logicalcluster.Name, lcFor func(logicalcluster.Name) *corev1alpha1.LogicalCluster) error {
+	errs := make([]error, len(clusters))
+
+	workers := d.workerCount
+	if workers < 1 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(clusters); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if logicalCluster := lcFor(clusters[i]); logicalCluster != nil {
+					errs[i] = d.Delete(ctx, logicalCluster)
+				}
+			}
+		}()
+	}
+	for i := range clusters {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	remaining := &ClustersRemainingError{Remaining: map[logicalcluster.Name]*ResourcesRemainingError{}}
+	var failed []error
+	for i, err := range errs {
+		var remainingErr *ResourcesRemainingError
+		switch {
+		case err == nil:
+		case errors.As(err, &remainingErr):
+			remaining.Remaining[clusters[i]] = remainingErr
+		default:
+			failed = append(failed, fmt.Errorf("logical cluster %s: %w", clusters[i], err))
+		}
+	}
+	if len(remaining.Remaining) == 0 {
+		return utilerrors.NewAggregate(failed)
+	}
+	if len(failed) == 0 {
+		return remaining
+	}
+	return utilerrors.NewAggregate(append(failed, remaining))
+}
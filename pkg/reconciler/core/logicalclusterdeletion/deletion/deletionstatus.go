@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// deletionStatusOf returns the deletion status of the logical cluster after a pass described by report.
+func (d *logicalClusterResourcesDeleter) deletionStatusOf(logicalCluster *corev1alpha1.LogicalCluster, report *DeletionReport) *corev1alpha1.LogicalClusterDeletionStatus {
+	status := &corev1alpha1.LogicalClusterDeletionStatus{
+		Phase:       string(DeletionStatusOf(logicalCluster)),
+		LastUpdated: metav1.NewTime(d.clock.Now()),
+	}
+	if previous := logicalCluster.Status.Deletion; previous != nil {
+		// estimated by the pass from the deletion rate.
+		status.EstimatedCompletion = previous.EstimatedCompletion.DeepCopy()
+	}
+	if report == nil {
+		return status
+	}
+	for _, rr := range report.Resources {
+		status.Remaining += int64(rr.Remaining)
+	}
+	if !report.lastProcessed.Empty() {
+		status.LastProcessedResource = report.lastProcessed.GroupResource().String()
+	}
+	return status
+}
+
+// writeDeletionStatus sets the deletion status of the logical cluster after a pass, and writes it to the
+// status of the latest LogicalCluster through the status client. Conflicts are retried. Failures are
+// logged, but do not fail the pass: the status is written again by the next one. On success, the
+// resource version of the logical cluster is updated to the written one, such that writes of the caller
+// conditional on it, e.g. of the conditions, do not conflict with the status write.
+func (d *logicalClusterResourcesDeleter) writeDeletionStatus(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, report *DeletionReport) {
+	status := d.deletionStatusOf(logicalCluster, report)
+	logicalCluster.Status.Deletion = status
+
+	client := d.statusClient.Cluster(logicalcluster.From(logicalCluster).Path()).CoreV1alpha1().LogicalClusters()
+	var written *corev1alpha1.LogicalCluster
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := client.Get(ctx, logicalCluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		latest.Status.Deletion = status.DeepCopy()
+		written, err = client.UpdateStatus(ctx, latest, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "failed to write the deletion status")
+		return
+	}
+	logicalCluster.ResourceVersion = written.ResourceVersion
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+)
+
+// CachedDiscovery memoizes the resources discovered per logical cluster for a TTL. It is safe
+// for concurrent use.
+type CachedDiscovery struct {
+	discover func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error)
+	ttl      time.Duration
+	clock    clock.PassiveClock
+
+	lock    sync.Mutex
+	entries map[logicalcluster.Path]cachedDiscoveryEntry
+	// generation is bumped by every Invalidate and Forget. Discoveries running meanwhile might have
+	// seen the stale resources, so their results are not cached.
+	generation uint64
+}
+
+type cachedDiscoveryEntry struct {
+	resources    []*metav1.APIResourceList
+	discoveredAt time.Time
+}
+
+// NewCachedDiscovery wraps the discovery function of a deleter with a cache. Failed discoveries
+// are not cached.
+func NewCachedDiscovery(discover func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error), ttl time.Duration) *CachedDiscovery {
+	return &CachedDiscovery{
+		discover: discover,
+		ttl:      ttl,
+		clock:    clock.RealClock{},
+		entries:  map[logicalcluster.Path]cachedDiscoveryEntry{},
+	}
+}
+
+// Discover returns the cached resources of the logical cluster, or discovers them if they are
+// not cached or older than the TTL. It can be passed to NewWorkspacedResourcesDeleter.
+func (c *CachedDiscovery) Discover(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+	c.lock.Lock()
+	entry, ok := c.entries[clusterName]
+	generation := c.generation
+	c.lock.Unlock()
+	if ok && c.clock.Since(entry.discoveredAt) < c.ttl {
+		return entry.resources, nil
+	}
+
+	now := c.clock.Now()
+	resources, err := c.discover(clusterName)
+	if err != nil {
+		return resources, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.generation == generation {
+		c.entries[clusterName] = cachedDiscoveryEntry{resources: resources, discoveredAt: now}
+	}
+	return resources, nil
+}
+
+// Invalidate drops the cached resources of the logical cluster, e.g. after a CRD or an APIBinding
+// changed. Discoveries running meanwhile are not cached.
+func (c *CachedDiscovery) Invalidate(clusterName logicalcluster.Path) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.entries, clusterName)
+	c.generation++
+}
+
+// Forget drops the cached resources of the logical cluster once it is not deleted anymore, e.g.
+// because it was finalized, such that the cache does not grow with every logical cluster deleted.
+func (c *CachedDiscovery) Forget(clusterName logicalcluster.Path) {
+	c.Invalidate(clusterName)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"errors"
+)
+
+var (
+	// ErrResourcesRemaining matches a ResourcesRemainingError with errors.Is.
+	ErrResourcesRemaining = errors.New("resources remaining in the logical cluster")
+	// ErrDiscoveryFailed matches a DiscoveryFailedError with errors.Is.
+	ErrDiscoveryFailed = errors.New("resource discovery failed")
+	// ErrLogicalClusterGone is returned by discovery functions, possibly wrapped, if the logical cluster
+	// is not served anymore, e.g. because its shard was decommissioned. Other errors, including NotFound,
+	// never mean that the content is gone.
+	ErrLogicalClusterGone = errors.New("logical cluster is not served anymore")
+)
+
+// isLogicalClusterGone returns true if the discovery error means the logical cluster is not served anymore.
+func isLogicalClusterGone(err error) bool {
+	return errors.Is(err, ErrLogicalClusterGone)
+}
+
+// Is returns true for ErrResourcesRemaining.
+func (e *ResourcesRemainingError) Is(target error) bool {
+	return target == ErrResourcesRemaining
+}
+
+// DiscoveryFailedError is returned if the resources of the logical cluster could not be discovered
+// completely. The resources that were discovered are still deleted.
+type DiscoveryFailedError struct {
+	Err error
+}
+
+func (e *DiscoveryFailedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DiscoveryFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true for ErrDiscoveryFailed.
+func (e *DiscoveryFailedError) Is(target error) bool {
+	return target == ErrDiscoveryFailed
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// DeletionEstimate is the number of objects of a resource in a namespace that a deletion would delete.
+// Namespace is empty for cluster-scoped resources.
+type DeletionEstimate struct {
+	GVR       schema.GroupVersionResource
+	Namespace string
+	Count     int
+}
+
+// EstimateDeletion discovers and lists the content of the logical cluster like Delete, but does
+// not issue any delete or delete-collection calls. Resources that do not support list are skipped.
+// The estimates are sorted by resource and namespace.
+func (d *logicalClusterResourcesDeleter) EstimateDeletion(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) ([]DeletionEstimate, error) {
+	ctx, logger := withLogicalClusterLogger(ctx, logicalCluster)
+	logger = logger.WithValues("operation", "estimateDeletion")
+	clusterName := logicalcluster.From(logicalCluster)
+
+	var errs []error
+	resources, err := d.discoverResources(clusterName)
+	if isLogicalClusterGone(err) {
+		return nil, nil
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	groupVersionResources, err := d.deletableGroupVersionResources(resources)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	var estimates []DeletionEstimate
+	for gvr, verbs := range groupVersionResources {
+		partialList, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !listSupported {
+			logger.V(5).Info("skipping resource not supporting list", "gvr", gvr)
+			continue
+		}
+		counts := map[string]int{}
+		for _, item := range partialList.Items {
+			counts[item.Namespace]++
+		}
+		for ns, count := range counts {
+			estimates = append(estimates, DeletionEstimate{GVR: gvr, Namespace: ns, Count: count})
+		}
+	}
+
+	sort.Slice(estimates, func(i, j int) bool {
+		if a, b := estimates[i].GVR.String(), estimates[j].GVR.String(); a != b {
+			return a < b
+		}
+		return estimates[i].Namespace < estimates[j].Namespace
+	})
+	return estimates, utilerrors.NewAggregate(errs)
+}
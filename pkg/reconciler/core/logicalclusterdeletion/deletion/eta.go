@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// etaSmoothing is the weight of the latest observed deletion rate against the smoothed rate
+// of earlier passes.
+const etaSmoothing = 0.5
+
+// estimateCompletion records the number of objects remaining at the given time in status.deletion of the
+// logical cluster, and estimates the time until none remain from the smoothed deletion rate. The
+// estimate is stored as the estimated completion time, from which the next pass derives the rate of
+// earlier passes, such that the estimate survives controller restarts. It returns false if there is
+// no estimate yet, i.e. on the first pass or while no progress has been observed.
+func estimateCompletion(logicalCluster *corev1alpha1.LogicalCluster, remaining int, now time.Time) (time.Duration, bool) {
+	previous := logicalCluster.Status.Deletion
+	if previous == nil || previous.LastUpdated.IsZero() {
+		logicalCluster.Status.Deletion = &corev1alpha1.LogicalClusterDeletionStatus{
+			Remaining:   int64(remaining),
+			LastUpdated: metav1.NewTime(now),
+		}
+		return 0, false
+	}
+
+	status := previous.DeepCopy()
+	rate := deletionRate(previous)
+	if elapsed := now.Sub(previous.LastUpdated.Time).Seconds(); elapsed > 0 {
+		deleted := previous.Remaining - int64(remaining)
+		if deleted < 0 {
+			// new content showed up, which does not make deletion faster.
+			deleted = 0
+		}
+		sample := float64(deleted) / elapsed
+		if rate < 0 {
+			rate = sample
+		} else {
+			rate = etaSmoothing*sample + (1-etaSmoothing)*rate
+		}
+		status.Remaining = int64(remaining)
+		status.LastUpdated = metav1.NewTime(now)
+	}
+	logicalCluster.Status.Deletion = status
+
+	if rate <= 0 || status.Remaining == 0 {
+		status.EstimatedCompletion = nil
+		return 0, false
+	}
+	eta := time.Duration(float64(status.Remaining) / rate * float64(time.Second))
+	completion := metav1.NewTime(status.LastUpdated.Add(eta))
+	status.EstimatedCompletion = &completion
+	return eta, true
+}
+
+// deletionRate returns the smoothed number of objects deleted per second as of the given status, or a
+// negative value if there is no rate yet.
+func deletionRate(status *corev1alpha1.LogicalClusterDeletionStatus) float64 {
+	if status.EstimatedCompletion == nil || status.Remaining <= 0 {
+		return -1
+	}
+	seconds := status.EstimatedCompletion.Sub(status.LastUpdated.Time).Seconds()
+	if seconds <= 0 {
+		return -1
+	}
+	return float64(status.Remaining) / seconds
+}
+
+// forgetEstimatedCompletion drops the estimated completion of the logical cluster once its content is gone.
+func forgetEstimatedCompletion(logicalCluster *corev1alpha1.LogicalCluster) {
+	if logicalCluster.Status.Deletion != nil {
+		logicalCluster.Status.Deletion.EstimatedCompletion = nil
+	}
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+const (
+	eventReasonDeletingContent  = "DeletingContent"
+	eventReasonContentDeleted   = "ContentDeleted"
+	eventReasonDeletionFailed   = "DeletionFailed"
+	eventReasonContentAbandoned = "ContentAbandoned"
+
+	eventActionDeleteContent = "DeleteContent"
+)
+
+// event records an event about the deletion of the content of the logical cluster, if an event
+// recorder is configured.
+func (d *logicalClusterResourcesDeleter) event(logicalCluster *corev1alpha1.LogicalCluster, eventtype, reason, note string, args ...interface{}) {
+	if d.eventRecorder == nil {
+		return
+	}
+	d.eventRecorder.Eventf(logicalCluster, nil, eventtype, reason, eventActionDeleteContent, note, args...)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// ExplainFinalizerBlock returns a human-readable explanation why the deletion finalizer
+// cannot be removed from the given LogicalCluster yet, based on its conditions. It returns
+// an empty string if nothing blocks the finalizer.
+func ExplainFinalizerBlock(logicalCluster *corev1alpha1.LogicalCluster) string {
+	if logicalCluster.DeletionTimestamp.IsZero() {
+		return "cannot remove finalizer: the logical cluster is not being deleted"
+	}
+	hasFinalizer := false
+	for _, f := range logicalCluster.Finalizers {
+		if f == LogicalClusterDeletionFinalizer {
+			hasFinalizer = true
+			break
+		}
+	}
+	if !hasFinalizer {
+		return ""
+	}
+
+	condition := conditions.Get(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted)
+	if condition == nil {
+		return "cannot remove finalizer: content deletion has not started yet"
+	}
+	if conditions.IsTrue(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted) {
+		return ""
+	}
+
+	var explanation string
+	switch condition.Reason {
+	case "SomeResourcesRemain":
+		explanation = "waiting for content to go away"
+	case "DiscoveryFailed":
+		explanation = "not all resources could be discovered"
+	case "GroupVersionParsingFailed":
+		explanation = "not all discovered resources could be parsed"
+	case "ContentDeletionFailed":
+		explanation = "content could not be deleted"
+	case "WaitingOnQuota":
+		explanation = "content deletion is blocked by a quota"
+	case "ProtectedResourcesRemaining":
+		explanation = "protected resources must be removed manually"
+	default:
+		explanation = fmt.Sprintf("content deletion is blocked (%s)", condition.Reason)
+	}
+	if condition.Message == "" {
+		return fmt.Sprintf("cannot remove finalizer: %s", explanation)
+	}
+	return fmt.Sprintf("cannot remove finalizer: %s: %s", explanation, condition.Message)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// discoverResources returns the resources of the logical cluster: the explicit resources if configured,
+// the discovered resources otherwise.
+func (d *logicalClusterResourcesDeleter) discoverResources(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+	if d.explicitResources == nil {
+		return d.discoverResourcesFn(clusterName.Path())
+	}
+	return d.explicitResourceLists(clusterName)
+}
+
+// explicitResourceLists returns the explicit resources served in the logical cluster as resource lists,
+// with their scope and verbs as discovered for their GroupVersion. Only the GroupVersions of the explicit
+// resources are discovered. Resources that are not served are skipped.
+func (d *logicalClusterResourcesDeleter) explicitResourceLists(clusterName logicalcluster.Name) ([]*metav1.APIResourceList, error) {
+	var groupVersions []schema.GroupVersion
+	byGroupVersion := map[schema.GroupVersion][]string{}
+	for _, gvr := range d.explicitResources {
+		gv := gvr.GroupVersion()
+		if _, ok := byGroupVersion[gv]; !ok {
+			groupVersions = append(groupVersions, gv)
+		}
+		byGroupVersion[gv] = append(byGroupVersion[gv], gvr.Resource)
+	}
+
+	var lists []*metav1.APIResourceList
+	var errs []error
+	for _, gv := range groupVersions {
+		discovered, err := d.discoverGroupVersionFn(clusterName.Path(), gv.String())
+		if isResourceGone(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to discover %s: %w", gv, err))
+			continue
+		}
+		list := &metav1.APIResourceList{GroupVersion: gv.String()}
+		for _, resource := range byGroupVersion[gv] {
+			for _, r := range discovered.APIResources {
+				if r.Name == resource {
+					list.APIResources = append(list.APIResources, *r.DeepCopy())
+					break
+				}
+			}
+		}
+		if len(list.APIResources) > 0 {
+			lists = append(lists, list)
+		}
+	}
+	return lists, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// localOwners caches by UID whether owners were found in the logical cluster during a pass.
+type localOwners map[types.UID]bool
+
+// deleteEligible lists the items of gvr and deletes those that are neither owned by objects outside of
+// the logical cluster, if skipped, nor younger than the minimum age, if any, nor vetoed, one by one.
+// Externally owned items are left to the garbage collector, which removes them once their owners are
+// gone. It returns the items listed, nil if listing is not supported.
+func (d *logicalClusterResourcesDeleter) deleteEligible(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String, owners localOwners) (*metav1.PartialObjectMetadataList, error) {
+	logger := klog.FromContext(ctx).WithValues("operation", "deleteEligible", "gvr", gvr)
+	logger.V(5).Info("running operation")
+
+	unstructuredList, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+	if err != nil {
+		return nil, err
+	}
+	if !listSupported {
+		return nil, nil
+	}
+
+	local := unstructuredList.Items
+	if d.skipExternallyOwned {
+		var external []metav1.PartialObjectMetadata
+		local, external, err = d.partitionExternallyOwned(ctx, clusterName, local, owners)
+		if err != nil {
+			return unstructuredList, err
+		}
+		if len(external) > 0 {
+			logger.V(4).Info("skipping externally owned items", "skipped", len(external))
+		}
+	}
+	if d.minAge > 0 {
+		var retained []metav1.PartialObjectMetadata
+		local, retained, _ = d.partitionRetained(local)
+		if len(retained) > 0 {
+			logger.V(4).Info("retaining items younger than the minimum age", "retained", len(retained), "minAge", d.minAge)
+		}
+	}
+	if d.shouldDeleteObject != nil {
+		var vetoed []metav1.PartialObjectMetadata
+		local, vetoed = d.partitionVetoed(gvr, local)
+		if len(vetoed) > 0 {
+			logger.V(4).Info("skipping vetoed items", "vetoed", len(vetoed))
+		}
+	}
+	local, _ = d.partitionAwaitingFinalizers(local)
+	if len(local) == 0 {
+		return unstructuredList, nil
+	}
+	if d.needsItemsBeforeDeletion(gvr) {
+		if err := d.beforeDeletion(ctx, clusterName, gvr, local); err != nil {
+			return unstructuredList, err
+		}
+	}
+
+	deleted, err := d.deleteItems(ctx, clusterName, gvr, local)
+	if d.manifest != nil && len(deleted) > 0 {
+		if manifestErr := d.writeManifest(ctx, clusterName, gvr, deleted); manifestErr != nil && err == nil {
+			err = manifestErr
+		}
+	}
+	return unstructuredList, err
+}
+
+// partitionExternallyOwned splits items into those owned locally or not at all, and those with an
+// owner reference that does not resolve to an object in the logical cluster.
+func (d *logicalClusterResourcesDeleter) partitionExternallyOwned(ctx context.Context, clusterName logicalcluster.Name, items []metav1.PartialObjectMetadata, owners localOwners) (local, external []metav1.PartialObjectMetadata, err error) {
+	for _, item := range items {
+		isExternal := false
+		for _, ref := range item.OwnerReferences {
+			found, err := d.ownerIsLocal(ctx, clusterName, item.Namespace, ref, owners)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !found {
+				isExternal = true
+				break
+			}
+		}
+		if isExternal {
+			external = append(external, item)
+		} else {
+			local = append(local, item)
+		}
+	}
+	return local, external, nil
+}
+
+// ownerIsLocal returns true if the owner referenced by an object in the given namespace exists in the
+// logical cluster with the referenced UID. Like for the garbage collector, owners of namespaced objects
+// live in the same namespace or are cluster-scoped. The resource of the owner is guessed from its kind.
+func (d *logicalClusterResourcesDeleter) ownerIsLocal(ctx context.Context, clusterName logicalcluster.Name, namespace string, ref metav1.OwnerReference, owners localOwners) (bool, error) {
+	if found, ok := owners[ref.UID]; ok {
+		return found, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		// not resolvable in any logical cluster.
+		owners[ref.UID] = false
+		return false, nil
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gv.WithKind(ref.Kind))
+
+	namespaces := []string{metav1.NamespaceNone}
+	if namespace != metav1.NamespaceNone {
+		namespaces = []string{namespace, metav1.NamespaceNone}
+	}
+	found := false
+	for _, ns := range namespaces {
+		owner, err := d.resourceClient(clusterName, gvr).Namespace(ns).Get(ctx, ref.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		found = owner.UID == ref.UID
+		break
+	}
+	owners[ref.UID] = found
+	return found, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+const (
+	// maxFailureMessageLength bounds the length of a single error in the condition message.
+	maxFailureMessageLength = 256
+	// maxFailedResources bounds the number of failed resources listed in the condition message.
+	maxFailedResources = 10
+)
+
+// deletionFailures collects the failures of a deletion pass for the condition message.
+type deletionFailures struct {
+	discovery error
+	parsing   error
+	gvrs      map[schema.GroupVersionResource]error
+}
+
+// message returns a description of the failures, distinguishing discovery failures from the
+// failures of individual resources. Resources are sorted for stable updates.
+func (f *deletionFailures) message() string {
+	var messages []string
+	var partial *discovery.ErrGroupDiscoveryFailed
+	if errors.As(f.discovery, &partial) {
+		// the resources of the other group versions are still deleted.
+		groupVersions := failedGroupVersions(partial)
+		for i, gv := range groupVersions {
+			if i == maxFailedResources {
+				messages = append(messages, fmt.Sprintf("and discovery of %d more group versions failed", len(groupVersions)-maxFailedResources))
+				break
+			}
+			messages = append(messages, fmt.Sprintf("discovery of %s failed: %s", gv, truncateFailure(partial.Groups[gv])))
+		}
+	} else if f.discovery != nil {
+		messages = append(messages, fmt.Sprintf("discovery failed: %s", truncateFailure(f.discovery)))
+	}
+	if f.parsing != nil {
+		messages = append(messages, fmt.Sprintf("parsing discovered group versions failed: %s", truncateFailure(f.parsing)))
+	}
+
+	gvrs := make([]schema.GroupVersionResource, 0, len(f.gvrs))
+	for gvr := range f.gvrs {
+		gvrs = append(gvrs, gvr)
+	}
+	sort.Slice(gvrs, func(i, j int) bool {
+		return gvrs[i].String() < gvrs[j].String()
+	})
+	for i, gvr := range gvrs {
+		if i == maxFailedResources {
+			messages = append(messages, fmt.Sprintf("and %d more resources failed", len(gvrs)-maxFailedResources))
+			break
+		}
+		messages = append(messages, fmt.Sprintf("deletion of %s.%s failed: %s", gvr.Resource, gvr.Group, truncateFailure(f.gvrs[gvr])))
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// failedGroupVersions returns the group versions whose discovery failed, sorted.
+func failedGroupVersions(partial *discovery.ErrGroupDiscoveryFailed) []schema.GroupVersion {
+	groupVersions := make([]schema.GroupVersion, 0, len(partial.Groups))
+	for gv := range partial.Groups {
+		groupVersions = append(groupVersions, gv)
+	}
+	sort.Slice(groupVersions, func(i, j int) bool {
+		return groupVersions[i].String() < groupVersions[j].String()
+	})
+	return groupVersions
+}
+
+// markResourceDiscovery sets the WorkspaceResourceDiscoverySuccess condition from the error of the
+// discovery of the logical cluster, such that discovery problems can be told apart from deletion problems.
+func markResourceDiscovery(logicalCluster *corev1alpha1.LogicalCluster, err error) {
+	var partial *discovery.ErrGroupDiscoveryFailed
+	switch {
+	case err == nil:
+		conditions.MarkTrue(logicalCluster, tenancyv1alpha1.WorkspaceResourceDiscoverySuccess)
+	case errors.As(err, &partial):
+		groupVersions := failedGroupVersions(partial)
+		failed := make([]string, 0, len(groupVersions))
+		for _, gv := range groupVersions {
+			failed = append(failed, gv.String())
+		}
+		conditions.MarkFalse(logicalCluster, tenancyv1alpha1.WorkspaceResourceDiscoverySuccess, "PartialDiscovery", conditionsv1alpha1.ConditionSeverityWarning,
+			"Discovery failed for group versions %s", strings.Join(failed, ", "))
+	default:
+		conditions.MarkFalse(logicalCluster, tenancyv1alpha1.WorkspaceResourceDiscoverySuccess, "DiscoveryFailed", conditionsv1alpha1.ConditionSeverityError,
+			"Discovery failed: %s", truncateFailure(err))
+	}
+}
+
+func truncateFailure(err error) string {
+	msg := err.Error()
+	if len(msg) <= maxFailureMessageLength {
+		return msg
+	}
+	return msg[:maxFailureMessageLength] + "..."
+}
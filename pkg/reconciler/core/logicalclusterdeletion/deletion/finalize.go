@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// FinalizeWorkspace removes LogicalClusterDeletionFinalizer from the given LogicalCluster once the
+// WorkspaceContentDeleted condition is True, and returns an error explaining what blocks the finalizer
+// otherwise. The LogicalCluster is only changed in memory, persisting it is up to the caller.
+func (d *logicalClusterResourcesDeleter) FinalizeWorkspace(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+	if explanation := ExplainFinalizerBlock(logicalCluster); explanation != "" {
+		return errors.New(explanation)
+	}
+	if RemoveDeletionFinalizer(logicalCluster) {
+		klog.FromContext(ctx).V(4).Info("removed deletion finalizer", "finalizer", LogicalClusterDeletionFinalizer)
+	}
+	return nil
+}
+
+// RemoveDeletionFinalizer removes LogicalClusterDeletionFinalizer from the given LogicalCluster
+// regardless of its conditions. It returns true if the finalizer was present.
+func RemoveDeletionFinalizer(logicalCluster *corev1alpha1.LogicalCluster) bool {
+	for i := range logicalCluster.Finalizers {
+		if logicalCluster.Finalizers[i] == LogicalClusterDeletionFinalizer {
+			logicalCluster.Finalizers = append(logicalCluster.Finalizers[:i], logicalCluster.Finalizers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// notifyContentDeleted calls the OnContentDeleted callback if the WorkspaceContentDeleted condition of
+// the logical cluster became True during the pass that returned err. The condition is kept when the
+// callback fails, and its error is returned along with err.
+func (d *logicalClusterResourcesDeleter) notifyContentDeleted(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, err error) error {
+	if !conditions.IsTrue(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted) {
+		return err
+	}
+	if callbackErr := d.onContentDeleted(ctx, logicalCluster); callbackErr != nil {
+		return utilerrors.NewAggregate([]error{err, fmt.Errorf("content deleted callback failed: %w", callbackErr)})
+	}
+	return err
+}
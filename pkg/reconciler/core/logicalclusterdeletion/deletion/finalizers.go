@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// isAwaitingFinalizers returns true if item is terminating and waits for finalizers.
+func isAwaitingFinalizers(item *metav1.PartialObjectMetadata) bool {
+	return !item.DeletionTimestamp.IsZero() && len(item.Finalizers) > 0
+}
+
+// partitionAwaitingFinalizers splits items into those to delete and those already terminating and
+// waiting for finalizers, which another delete call would not speed up. All items are deleted unless
+// awaiting finalizers are classified.
+func (d *logicalClusterResourcesDeleter) partitionAwaitingFinalizers(items []metav1.PartialObjectMetadata) (fresh, awaiting []metav1.PartialObjectMetadata) {
+	if d.awaitingFinalizersGrace <= 0 {
+		return items, nil
+	}
+	for i := range items {
+		if isAwaitingFinalizers(&items[i]) {
+			awaiting = append(awaiting, items[i])
+		} else {
+			fresh = append(fresh, items[i])
+		}
+	}
+	return fresh, awaiting
+}
+
+// isAwaitingFinalizersPastGrace returns true if item has been waiting for finalizers for longer than
+// the grace period.
+func (d *logicalClusterResourcesDeleter) isAwaitingFinalizersPastGrace(item *metav1.PartialObjectMetadata) bool {
+	return d.awaitingFinalizersGrace > 0 && isAwaitingFinalizers(item) &&
+		item.DeletionTimestamp.Time.Before(d.clock.Now().Add(-d.awaitingFinalizersGrace))
+}
+
+// markAwaitingFinalizers sets the WorkspaceDeletionAwaitingFinalizers condition naming the finalizers
+// that instances terminating for longer than the grace period wait for. Otherwise, the condition is
+// removed.
+func (d *logicalClusterResourcesDeleter) markAwaitingFinalizers(logicalCluster *corev1alpha1.LogicalCluster, numAwaiting int, finalizersToNumAwaiting map[string]int) {
+	if d.awaitingFinalizersGrace <= 0 {
+		return
+	}
+	if numAwaiting == 0 {
+		conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers)
+		return
+	}
+	finalizers := make([]string, 0, len(finalizersToNumAwaiting))
+	for finalizer, n := range finalizersToNumAwaiting {
+		finalizers = append(finalizers, fmt.Sprintf("%s in %d resource instances", finalizer, n))
+	}
+	// sort for stable updates
+	sort.Strings(finalizers)
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsv1alpha1.ConditionSeverityWarning,
+		Reason:   "FinalizersPending",
+		Message:  fmt.Sprintf("%d resource instances are terminating for longer than %s, waiting for finalizers: %s", numAwaiting, d.awaitingFinalizersGrace, strings.Join(finalizers, ", ")),
+	})
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// forbiddenEstimate is the estimate in seconds before resources whose deletion was forbidden are retried.
+const forbiddenEstimate = int64(60)
+
+// groupResourceNames returns the names of the given resources as resource.group, sorted.
+func groupResourceNames(gvrs []schema.GroupVersionResource) []string {
+	sortGroupVersionResources(gvrs)
+	names := make([]string, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		names = append(names, fmt.Sprintf("%s.%s", gvr.Resource, gvr.Group))
+	}
+	return names
+}
+
+// markDeletionForbidden sets the WorkspaceDeletionForbidden condition naming the resources the deleter
+// was not permitted to delete, such that missing permissions are visible instead of stalling the deletion.
+// Without forbidden resources, the condition is removed.
+func markDeletionForbidden(logicalCluster *corev1alpha1.LogicalCluster, forbidden []schema.GroupVersionResource) {
+	if len(forbidden) == 0 {
+		conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceDeletionForbidden)
+		return
+	}
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceDeletionForbidden,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsv1alpha1.ConditionSeverityWarning,
+		Reason:   "Forbidden",
+		Message:  fmt.Sprintf("Not permitted to delete %s", strings.Join(groupResourceNames(forbidden), ", ")),
+	})
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// ForceFinalizeAnnotationKey confirms the irreversible escape hatches of a terminating LogicalCluster:
+// stripping the deletion finalizer although its content could not be deleted, and stripping the
+// finalizers of stuck objects in it. The value must echo the name of the logical cluster to confirm
+// the intent, as whatever is left behind is orphaned irreversibly.
+const ForceFinalizeAnnotationKey = "experimental.core.kcp.io/force-finalize"
+
+// ValidateForceFinalizeConfirmation returns an error unless the ForceFinalizeAnnotationKey annotation
+// of the LogicalCluster matches its logical cluster name.
+func ValidateForceFinalizeConfirmation(lc *corev1alpha1.LogicalCluster) error {
+	token, ok := lc.Annotations[ForceFinalizeAnnotationKey]
+	if !ok {
+		return fmt.Errorf("annotation %s is not set", ForceFinalizeAnnotationKey)
+	}
+	if expected := logicalcluster.From(lc).String(); token != expected {
+		return fmt.Errorf("annotation %s must be set to the logical cluster name %q to confirm, got %q", ForceFinalizeAnnotationKey, expected, token)
+	}
+	return nil
+}
+
+// removeFinalizersPatch clears all finalizers of an object.
+var removeFinalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+
+// stuckTracker counts the consecutive deletion passes in which instances of a resource remained
+// pending on finalizers, and records which logical clusters confirmed the removal of finalizers.
+type stuckTracker struct {
+	lock      sync.Mutex
+	clusters  map[logicalcluster.Name]map[schema.GroupVersionResource]int
+	confirmed map[logicalcluster.Name]bool
+}
+
+func newStuckTracker() *stuckTracker {
+	return &stuckTracker{
+		clusters:  map[logicalcluster.Name]map[schema.GroupVersionResource]int{},
+		confirmed: map[logicalcluster.Name]bool{},
+	}
+}
+
+// confirm records whether the removal of finalizers is confirmed for the current pass of the logical cluster.
+func (t *stuckTracker) confirm(clusterName logicalcluster.Name, confirmed bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !confirmed {
+		delete(t.confirmed, clusterName)
+		return
+	}
+	t.confirmed[clusterName] = true
+}
+
+// isConfirmed returns true if the removal of finalizers is confirmed for the logical cluster.
+func (t *stuckTracker) isConfirmed(clusterName logicalcluster.Name) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.confirmed[clusterName]
+}
+
+// observe records whether instances of gvr remained on finalizers in a pass and returns the number
+// of consecutive passes they did.
+func (t *stuckTracker) observe(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, stuck bool) int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !stuck {
+		delete(t.clusters[clusterName], gvr)
+		return 0
+	}
+	if t.clusters[clusterName] == nil {
+		t.clusters[clusterName] = map[schema.GroupVersionResource]int{}
+	}
+	t.clusters[clusterName][gvr]++
+	return t.clusters[clusterName][gvr]
+}
+
+// forget drops all state of the given logical cluster.
+func (t *stuckTracker) forget(clusterName logicalcluster.Name) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.clusters, clusterName)
+	delete(t.confirmed, clusterName)
+}
+
+// forceRemoveFinalizers strips the finalizers of the terminating items of gvr that belong to the
+// logical cluster, once they have been stuck for the configured number of passes and the removal is
+// confirmed by the ForceFinalizeAnnotationKey annotation. Whatever the finalizers were guarding is
+// skipped irreversibly.
+func (d *logicalClusterResourcesDeleter) forceRemoveFinalizers(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) error {
+	if d.stuck == nil {
+		return nil
+	}
+	passes := d.stuck.observe(clusterName, gvr, len(items) > 0)
+	if passes < d.forceRemoveFinalizersAfter {
+		return nil
+	}
+
+	logger := klog.FromContext(ctx).WithValues("operation", "forceRemoveFinalizers", "gvr", gvr, "passes", passes)
+	if !d.stuck.isConfirmed(clusterName) {
+		logger.V(2).Info("not removing finalizers of stuck objects without confirmation", "annotation", ForceFinalizeAnnotationKey)
+		return nil
+	}
+	for i := range items {
+		item := &items[i]
+		if item.DeletionTimestamp.IsZero() || len(item.Finalizers) == 0 || logicalcluster.From(item) != clusterName {
+			continue
+		}
+		logger.Info("DESTRUCTIVE: forcibly removing finalizers from stuck object", "namespace", item.Namespace, "name", item.Name, "finalizers", item.Finalizers)
+		if err := d.throttle(ctx); err != nil {
+			return err
+		}
+		if _, err := d.resourceClient(clusterName, gvr).Namespace(item.Namespace).Patch(ctx, item.Name, types.MergePatchType, removeFinalizersPatch, metav1.PatchOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
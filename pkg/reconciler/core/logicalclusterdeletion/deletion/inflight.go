@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// passResult is what a deletion pass changed on the LogicalCluster it worked on, which is applied to
+// the LogicalCluster of every caller joining the pass.
+type passResult struct {
+	annotations     map[string]string
+	conditions      conditionsv1alpha1.Conditions
+	deletion        *corev1alpha1.LogicalClusterDeletionStatus
+	resourceVersion string
+	report          *DeletionReport
+}
+
+// passResultOf returns the result of a pass that worked on logicalCluster.
+func passResultOf(logicalCluster *corev1alpha1.LogicalCluster, report *DeletionReport) passResult {
+	var annotations map[string]string
+	if logicalCluster.Annotations != nil {
+		annotations = make(map[string]string, len(logicalCluster.Annotations))
+		for k, v := range logicalCluster.Annotations {
+			annotations[k] = v
+		}
+	}
+	return passResult{
+		annotations:     annotations,
+		conditions:      logicalCluster.Status.Conditions.DeepCopy(),
+		deletion:        logicalCluster.Status.Deletion.DeepCopy(),
+		resourceVersion: logicalCluster.ResourceVersion,
+		report:          report,
+	}
+}
+
+// applyTo copies the result of the pass to the LogicalCluster of a caller.
+func (r passResult) applyTo(logicalCluster *corev1alpha1.LogicalCluster) {
+	logicalCluster.Annotations = nil
+	if r.annotations != nil {
+		logicalCluster.Annotations = make(map[string]string, len(r.annotations))
+		for k, v := range r.annotations {
+			logicalCluster.Annotations[k] = v
+		}
+	}
+	logicalCluster.Status.Conditions = r.conditions.DeepCopy()
+	logicalCluster.Status.Deletion = r.deletion.DeepCopy()
+	logicalCluster.ResourceVersion = r.resourceVersion
+}
+
+// inflightPasses counts the callers waiting for the deletion pass of each logical cluster, such that
+// a pass is only cancelled once none of its callers waits for it anymore.
+type inflightPasses struct {
+	lock   sync.Mutex
+	passes map[string]*inflightPass
+}
+
+type inflightPass struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiting int
+}
+
+func newInflightPasses() *inflightPasses {
+	return &inflightPasses{
+		passes: map[string]*inflightPass{},
+	}
+}
+
+// join registers a caller waiting for the pass of key. It returns the context for the pass, which
+// carries the values of the context of the first caller, and a func to call once the caller does not
+// wait anymore.
+func (p *inflightPasses) join(ctx context.Context, key string) (context.Context, func()) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	pass, ok := p.passes[key]
+	if !ok {
+		passCtx, cancel := context.WithCancel(detachedContext{ctx})
+		pass = &inflightPass{ctx: passCtx, cancel: cancel}
+		p.passes[key] = pass
+	}
+	pass.waiting++
+
+	return pass.ctx, func() {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+
+		pass.waiting--
+		if pass.waiting == 0 {
+			pass.cancel()
+			delete(p.passes, key)
+		}
+	}
+}
+
+// detachedContext carries the values of its parent, but neither its deadline nor its cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// OrphanedObject identifies an object that was deleted with orphaned dependents in migration mode.
+type OrphanedObject struct {
+	GVR       schema.GroupVersionResource
+	Namespace string
+	Name      string
+	UID       types.UID
+}
+
+// InventoryRecorder records the objects of a logical cluster that are deleted in migration mode.
+// Objects that survive a pass, e.g. because of finalizers, are recorded again in the next pass,
+// hence implementations must be idempotent. With WithWorkerCount, Record is called concurrently.
+type InventoryRecorder interface {
+	Record(ctx context.Context, clusterName logicalcluster.Name, objects []OrphanedObject) error
+}
+
+// recordInventory hands the items of gvr to the inventory recorder.
+func (d *logicalClusterResourcesDeleter) recordInventory(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	objects := make([]OrphanedObject, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, OrphanedObject{
+			GVR:       gvr,
+			Namespace: item.Namespace,
+			Name:      item.Name,
+			UID:       item.UID,
+		})
+	}
+	return d.inventory.Record(ctx, clusterName, objects)
+}
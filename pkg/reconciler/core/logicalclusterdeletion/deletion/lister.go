@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// staleCacheEstimate is the estimate in seconds returned when a live list finds instances of resources
+// that informer caches reported empty.
+const staleCacheEstimate = 1
+
+// countRemaining lists the items of gvr remaining after they were deleted. The items are served by the
+// informer cache of the resource, if any, and by a live list otherwise. It returns true if the items
+// were served by the cache.
+func (d *logicalClusterResourcesDeleter) countRemaining(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String) (*metav1.PartialObjectMetadataList, bool, bool, error) {
+	if list, ok, err := d.listFromCache(clusterName, gvr, verbs); ok || err != nil {
+		return list, true, true, err
+	}
+	list, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+	return list, listSupported, false, err
+}
+
+// listFromCache lists the items of gvr from its informer cache. It returns false if there is no cache
+// for the resource, or the cache cannot apply the list options, e.g. field selectors.
+func (d *logicalClusterResourcesDeleter) listFromCache(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String) (*metav1.PartialObjectMetadataList, bool, error) {
+	if d.listerFor == nil || !verbs.Has(string(operationList)) {
+		return nil, false, nil
+	}
+	opts := d.listOptions(gvr)
+	if opts.FieldSelector != "" {
+		return nil, false, nil
+	}
+	lister := d.listerFor(clusterName, gvr)
+	if lister == nil {
+		return nil, false, nil
+	}
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, true, err
+	}
+
+	var objs []runtime.Object
+	if namespace := d.namespaceOf(gvr); namespace != metav1.NamespaceAll {
+		objs, err = lister.ByNamespace(namespace).List(selector)
+	} else {
+		objs, err = lister.List(selector)
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	list := &metav1.PartialObjectMetadataList{Items: make([]metav1.PartialObjectMetadata, 0, len(objs))}
+	for _, obj := range objs {
+		item, err := partialObjectMetadataOf(obj)
+		if err != nil {
+			return nil, true, err
+		}
+		list.Items = append(list.Items, item)
+	}
+	return list, true, nil
+}
+
+// partialObjectMetadataOf returns a copy of the metadata of a cached object.
+func partialObjectMetadataOf(obj runtime.Object) (metav1.PartialObjectMetadata, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return metav1.PartialObjectMetadata{}, err
+	}
+	objectMeta := metav1.ObjectMeta{
+		Name:              accessor.GetName(),
+		Namespace:         accessor.GetNamespace(),
+		UID:               accessor.GetUID(),
+		ResourceVersion:   accessor.GetResourceVersion(),
+		CreationTimestamp: accessor.GetCreationTimestamp(),
+		DeletionTimestamp: accessor.GetDeletionTimestamp(),
+		Labels:            accessor.GetLabels(),
+		Annotations:       accessor.GetAnnotations(),
+		OwnerReferences:   accessor.GetOwnerReferences(),
+		Finalizers:        accessor.GetFinalizers(),
+	}
+	return metav1.PartialObjectMetadata{ObjectMeta: *objectMeta.DeepCopy()}, nil
+}
+
+// confirmEmpty lists the resources that informer caches reported empty live, as the caches might be
+// stale. It returns the number of instances found by resource.
+func (d *logicalClusterResourcesDeleter) confirmEmpty(ctx context.Context, clusterName logicalcluster.Name, cached []schema.GroupVersionResource, gvrs map[schema.GroupVersionResource]sets.String) (map[schema.GroupVersionResource]int, error) {
+	logger := klog.FromContext(ctx).WithValues("operation", "confirmEmpty")
+	logger.V(5).Info("running operation", "resources", len(cached))
+
+	remaining := map[schema.GroupVersionResource]int{}
+	for _, gvr := range cached {
+		list, listSupported, err := d.listCollection(ctx, clusterName, gvr, gvrs[gvr])
+		if err != nil {
+			return nil, err
+		}
+		if listSupported && len(list.Items) > 0 {
+			remaining[gvr] = len(list.Items)
+		}
+		d.releaseList(list)
+	}
+	return remaining, nil
+}
+
+// staleCacheMessage describes the instances found by confirmEmpty.
+func staleCacheMessage(remaining map[schema.GroupVersionResource]int) string {
+	resources := make([]string, 0, len(remaining))
+	for gvr, n := range remaining {
+		resources = append(resources, fmt.Sprintf("%s.%s has %d resource instances", gvr.Resource, gvr.Group, n))
+	}
+	sort.Strings(resources)
+	return fmt.Sprintf("Some resources reported empty by informer caches are remaining: %s", strings.Join(resources, ", "))
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+)
+
+// DeletionLock coordinates the content deletion of a logical cluster across shards, such that only one
+// deleter works on it at a time.
+type DeletionLock interface {
+	// Acquire returns once the lock for the logical cluster is held, or an error if it cannot be
+	// acquired, e.g. because another shard holds it. release is called when the deletion pass ends.
+	Acquire(ctx context.Context, clusterName logicalcluster.Name) (release func(), err error)
+}
+
+// DeletionLockedError is returned if the deletion lock of a logical cluster could not be acquired.
+// Nothing was deleted and the logical cluster is unchanged. The deletion should be retried later.
+type DeletionLockedError struct {
+	Err error
+}
+
+func (e *DeletionLockedError) Error() string {
+	return fmt.Sprintf("failed to acquire the deletion lock: %v", e.Err)
+}
+
+func (e *DeletionLockedError) Unwrap() error {
+	return e.Err
+}
+
+// acquireLock acquires the deletion lock of the logical cluster, if any. The returned release func
+// must be called at the end of the pass.
+func (d *logicalClusterResourcesDeleter) acquireLock(ctx context.Context, clusterName logicalcluster.Name) (func(), error) {
+	if d.lock == nil {
+		return func() {}, nil
+	}
+	release, err := d.lock.Acquire(ctx, clusterName)
+	if err != nil {
+		return nil, &DeletionLockedError{Err: err}
+	}
+	if release == nil {
+		release = func() {}
+	}
+	return release, nil
+}
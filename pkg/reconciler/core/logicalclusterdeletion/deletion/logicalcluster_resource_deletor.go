@@ -37,24 +37,38 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	kcpmetadata "github.com/kcp-dev/client-go/metadata"
 	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
 
-	rbac "k8s.io/api/rbac/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	"github.com/kcp-dev/kcp/pkg/apis/core"
 	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	"github.com/kcp-dev/kcp/pkg/projection"
 )
 
@@ -71,15 +85,53 @@ const (
 // - update deleteCollection to delete resources from all namespaces.
 type WorkspaceResourcesDeleterInterface interface {
 	Delete(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error
+	// DeleteWithReport is like Delete and in addition returns what the deletion pass did per resource.
+	DeleteWithReport(ctx context.Context, cluster *corev1alpha1.LogicalCluster) (*DeletionReport, error)
+	// EstimateDeletion returns the content Delete would delete, without deleting anything
+	// and without changing the conditions of the logical cluster.
+	EstimateDeletion(ctx context.Context, cluster *corev1alpha1.LogicalCluster) ([]DeletionEstimate, error)
+	// Preflight returns which of the verbs needed by Delete the deletion identity is permitted to use,
+	// without deleting anything and without changing the conditions of the logical cluster.
+	Preflight(ctx context.Context, cluster *corev1alpha1.LogicalCluster) (*PreflightReport, error)
+	// CancelDeletion stops the content deletion of the logical cluster if nothing was deleted yet.
+	CancelDeletion(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error
+	// WatchUntilDeleted blocks until the content of the logical cluster is gone or ctx is done, sending
+	// the remaining instances by resource on progress.
+	WatchUntilDeleted(ctx context.Context, cluster *corev1alpha1.LogicalCluster, progress chan<- DeletionProgress) error
+	// DeleteSelected deletes the content of the logical cluster matching the selector, without
+	// changing its conditions.
+	DeleteSelected(ctx context.Context, cluster *corev1alpha1.LogicalCluster, selector labels.Selector) error
+	// DeleteInNamespaces deletes the namespaced content of the logical cluster in the given namespaces,
+	// without changing its conditions.
+	DeleteInNamespaces(ctx context.Context, cluster *corev1alpha1.LogicalCluster, namespaces []string, skipClusterScoped bool) error
+	// DeleteWorkspaceRBAC deletes the RBAC objects labeled for the logical cluster, without changing its
+	// conditions.
+	DeleteWorkspaceRBAC(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error
+	// DeleteAll deletes the content of several logical clusters.
+	DeleteAll(ctx context.Context, clusters []logicalcluster.Name, lcFor func(logicalcluster.Name) *corev1alpha1.LogicalCluster) error
+	// FinalizeWorkspace removes the deletion finalizer once all content has been deleted.
+	FinalizeWorkspace(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error
 }
 
 // NewWorkspacedResourcesDeleter returns a new NamespacedResourcesDeleter.
 func NewWorkspacedResourcesDeleter(
 	metadataClusterClient kcpmetadata.ClusterInterface,
-	discoverResourcesFn func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error)) WorkspaceResourcesDeleterInterface {
+	discoverResourcesFn func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error),
+	opts ...Option) WorkspaceResourcesDeleterInterface {
 	d := &logicalClusterResourcesDeleter{
 		metadataClusterClient: metadataClusterClient,
 		discoverResourcesFn:   discoverResourcesFn,
+		propagationPolicy:     metav1.DeletePropagationBackground,
+		groupMigrations:       defaultGroupMigrations,
+		metrics:               defaultMetrics,
+		clock:                 clock.RealClock{},
+		listPageSize:          defaultListPageSize,
+		inflight:              &singleflight.Group{},
+		passes:                newInflightPasses(),
+		tracerProvider:        trace.NewNoopTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
 	return d
 }
@@ -90,8 +142,208 @@ var _ WorkspaceResourcesDeleterInterface = &logicalClusterResourcesDeleter{}
 type logicalClusterResourcesDeleter struct {
 	// Dynamic client to list and delete all resources in the logical cluster.
 	metadataClusterClient kcpmetadata.ClusterInterface
+	// metadataClientFor resolves the client serving a resource. Nil to use metadataClusterClient for all resources.
+	metadataClientFor func(gvr schema.GroupVersionResource) kcpmetadata.ClusterInterface
 
 	discoverResourcesFn func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error)
+	// explicitResources are the only resources deleted, instead of the discovered ones. Nil to use discovery.
+	explicitResources []schema.GroupVersionResource
+	// discoverGroupVersionFn discovers the resources of a single GroupVersion for the explicit resources.
+	discoverGroupVersionFn func(clusterName logicalcluster.Path, groupVersion string) (*metav1.APIResourceList, error)
+
+	// selfServiceAccount is the service account the deleter accesses logical clusters as. Nil if unknown.
+	selfServiceAccount *types.NamespacedName
+	// kubeClusterClient looks up the objects granting the self service account its access.
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+
+	// deletionOrderFn selects the order of list and delete-collection calls per resource.
+	deletionOrderFn func(gvr schema.GroupVersionResource) DeletionOrder
+
+	// settled tracks resources found empty in consecutive passes. Nil if disabled.
+	settled *settledTracker
+
+	// propagationPolicy is used for every delete and delete-collection call.
+	propagationPolicy metav1.DeletionPropagation
+	// inventory records objects before they are deleted in migration mode. Nil if disabled.
+	inventory InventoryRecorder
+
+	// preDeletePatches transition instances of immutable resources into a deletable state.
+	preDeletePatches map[schema.GroupVersionResource]PreDeletePatchFunc
+	// preDeleteHooks run external cleanup for instances of resources before they are deleted.
+	preDeleteHooks map[schema.GroupVersionResource]PreDeleteHookFunc
+
+	// namespaceLabelAggregator counts deleted objects by namespace labels. Nil if disabled.
+	namespaceLabelAggregator *NamespaceLabelAggregator
+
+	// deleteBatchInitial and deleteBatchMax bound the adaptive batch size of one by one deletions.
+	deleteBatchInitial, deleteBatchMax int
+
+	// manifest receives an entry for every deleted object. Nil if disabled.
+	manifest ManifestWriter
+
+	// groupMigrations maps legacy group resources to the group resource they have been migrated to.
+	groupMigrations map[schema.GroupResource]schema.GroupResource
+
+	// forceRemoveFinalizersAfter is the number of passes after which finalizers of stuck objects are
+	// removed. stuck counts these passes, nil if disabled.
+	forceRemoveFinalizersAfter int
+	stuck                      *stuckTracker
+
+	// deletionPolicyFn returns the deletion policy by workspace type. Nil to use StrictDeletion.
+	deletionPolicyFn func(workspaceType tenancyv1alpha1.WorkspaceTypeReference) DeletionPolicy
+
+	// rateLimiter gates every mutating call. Nil if unthrottled.
+	rateLimiter flowcontrol.RateLimiter
+
+	// listPageSize is the maximum number of items returned per list call. Zero disables pagination.
+	listPageSize int64
+	// listerFor returns the informer-backed lister counting the remaining instances of a resource. Nil,
+	// or a nil lister, to count them with live lists.
+	listerFor func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) cache.GenericLister
+
+	// maxAttempts is the number of incomplete deletion passes after which the deletion fails terminally.
+	// Zero for unlimited attempts.
+	maxAttempts int
+
+	// gracePeriod is how long to wait for remaining items to be finalized before listing them again.
+	// Zero to count remaining items right away.
+	gracePeriod time.Duration
+
+	// labelSelector restricts the deleted content. Empty when deleting all content.
+	labelSelector string
+	// listOptionsFor returns the options of list and delete-collection calls per resource. Nil for empty options.
+	listOptionsFor func(gvr schema.GroupVersionResource) metav1.ListOptions
+	// onContentDeleted is called when the content of a logical cluster is found deleted for the first time. Nil if disabled.
+	onContentDeleted func(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error
+
+	// lock coordinates the deletion of a logical cluster across shards. Nil if not needed.
+	lock DeletionLock
+
+	// isDeletable decides whether the content of a logical cluster can be deleted yet. Nil if always.
+	isDeletable func(logicalCluster *corev1alpha1.LogicalCluster) (bool, string)
+
+	// onDeleted is called with the number of instances that are gone by resource and namespace. Nil if disabled.
+	onDeleted func(gvr schema.GroupVersionResource, namespace string, count int)
+
+	// deletionSummary accumulates the deleted instances across passes for the final condition message.
+	deletionSummary bool
+
+	// bufferPool reuses the buffers of paginated lists. Nil if disabled.
+	bufferPool *ListBufferPool
+
+	// scope selects the resources processed by namespace scope. Empty for ClusterScopedOnly.
+	scope Scope
+
+	// previousReport returns the report of the previous pass of a logical cluster to detect recreated
+	// instances. Nil if disabled.
+	previousReport func(clusterName logicalcluster.Name) *DeletionReport
+	// haltContended halts the deletion of resources whose instances are recreated.
+	haltContended bool
+	// recreation records the UIDs of deleted objects to detect objects recreated under the same name. Nil if disabled.
+	recreation *recreationTracker
+
+	// checkpoint records the resources found empty on the logical cluster to skip them in later passes.
+	checkpoint bool
+
+	// awaitingFinalizersGrace is how long instances may wait for finalizers before they are reported. Zero
+	// if they are not classified.
+	awaitingFinalizersGrace time.Duration
+
+	// statusClient writes the deletion status of the LogicalCluster after every pass. Nil if disabled.
+	statusClient kcpclientset.ClusterInterface
+
+	// tracerProvider provides the tracer of the deletion spans unless the context carries a recording span.
+	tracerProvider trace.TracerProvider
+
+	// continueOnError starts later deletion phases even if resources of earlier phases failed to be deleted.
+	continueOnError bool
+
+	// maxDeletionsPerPass caps the delete and delete-collection calls of a deletion pass. Zero if unlimited.
+	maxDeletionsPerPass int
+
+	// minAge retains objects created less than minAge ago. Zero if all objects are deleted.
+	minAge time.Duration
+
+	// skipExternallyOwned leaves objects owned outside of the logical cluster to the garbage collector.
+	skipExternallyOwned bool
+
+	// namespacedResources are the namespaced resources whose content is deleted in namespace only.
+	// Nil when namespaced content goes away with its namespace.
+	namespacedResources map[schema.GroupVersionResource]bool
+	namespace           string
+
+	// deletionPriority orders the resources within a deletion phase. Nil if all are equal.
+	deletionPriority func(gvr schema.GroupVersionResource) int
+
+	// workerCount is the number of resources whose content is deleted concurrently.
+	workerCount int
+
+	// shouldDelete returns false for resources protected from deletion. Nil if all resources are deleted.
+	shouldDelete func(gvr schema.GroupVersionResource) bool
+	// verbOverrides replace the discovered verbs of resources. Nil if discovery is trusted.
+	verbOverrides map[schema.GroupVersionResource][]string
+	// shouldDeleteObject returns false for objects protected from deletion. Nil if all objects are deleted.
+	shouldDeleteObject func(gvr schema.GroupVersionResource, obj *metav1.PartialObjectMetadata) bool
+
+	// allowlist temporarily permits the deletion of resources excluded by default. Nil if disabled.
+	allowlist *Allowlist
+
+	metrics *Metrics
+
+	// eventRecorder records events about the deletion on the LogicalCluster. Nil if disabled.
+	eventRecorder events.EventRecorder
+
+	// timings accumulates the time spent per resource. Nil if disabled.
+	timings *ResourceTimings
+	// slowResourceThreshold is the duration of a resource's deletion above which it is logged as slow.
+	slowResourceThreshold time.Duration
+
+	// clock observes the progress of a deletion and waits, e.g. for the grace period.
+	clock clock.Clock
+
+	// inflight joins concurrent Delete calls for the same logical cluster.
+	inflight *singleflight.Group
+	// passes counts the callers waiting for the passes in flight.
+	passes *inflightPasses
+}
+
+// throttle waits for the rate limiter, if any, before a mutating call. It returns an error if ctx is
+// done before the call is permitted.
+func (d *logicalClusterResourcesDeleter) throttle(ctx context.Context) error {
+	if d.rateLimiter == nil {
+		return nil
+	}
+	return d.rateLimiter.Wait(ctx)
+}
+
+func (d *logicalClusterResourcesDeleter) deleteOptions() metav1.DeleteOptions {
+	policy := d.propagationPolicy
+	return metav1.DeleteOptions{PropagationPolicy: &policy}
+}
+
+// namespaceOf returns the namespace whose content of gvr is deleted.
+func (d *logicalClusterResourcesDeleter) namespaceOf(gvr schema.GroupVersionResource) string {
+	if d.namespacedResources[gvr] {
+		return d.namespace
+	}
+	return metav1.NamespaceAll
+}
+
+// listOptions returns the options of list and delete-collection calls for gvr. The label selector
+// of the deleter is combined with the one returned by listOptionsFor, if any.
+func (d *logicalClusterResourcesDeleter) listOptions(gvr schema.GroupVersionResource) metav1.ListOptions {
+	var opts metav1.ListOptions
+	if d.listOptionsFor != nil {
+		opts = d.listOptionsFor(gvr)
+	}
+	switch {
+	case d.labelSelector == "":
+	case opts.LabelSelector == "":
+		opts.LabelSelector = d.labelSelector
+	default:
+		opts.LabelSelector = d.labelSelector + "," + opts.LabelSelector
+	}
+	return opts
 }
 
 // Delete deletes all resources in the given logical cluster.
@@ -100,8 +352,57 @@ type logicalClusterResourcesDeleter struct {
 // Returns ResourcesRemainingError if it deleted some resources but needs
 // to wait for them to go away.
 // Caller is expected to keep calling this until it succeeds.
+//
+// Concurrent calls for the same logical cluster join the pass in flight instead of deleting
+// the content twice. The joining callers get the same result, annotations and status. With a
+// DeletionLock, a pass is only started once the lock is acquired, and DeletionLockedError is
+// returned otherwise.
 func (d *logicalClusterResourcesDeleter) Delete(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
-	logger := klog.FromContext(ctx)
+	_, err := d.DeleteWithReport(ctx, logicalCluster)
+	return err
+}
+
+// DeleteWithReport deletes all resources in the given logical cluster like Delete, and returns
+// what the deletion pass did per resource. The report is shared by joining callers.
+//
+// A pass works on a copy of the LogicalCluster, and its changes to the annotations and the status are
+// applied to the LogicalCluster of every caller. It is only cancelled once the contexts of all callers
+// are done. A caller whose context is done before returns without waiting for the pass.
+func (d *logicalClusterResourcesDeleter) DeleteWithReport(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) (*DeletionReport, error) {
+	if err := ctx.Err(); err != nil {
+		return &DeletionReport{}, d.interrupted(logicalCluster, err)
+	}
+
+	key := logicalcluster.From(logicalCluster).String()
+	passCtx, leave := d.passes.join(ctx, key)
+	defer leave()
+	passCluster := logicalCluster.DeepCopy()
+	results := d.inflight.DoChan(key, func() (interface{}, error) {
+		report := &DeletionReport{}
+		release, err := d.acquireLock(passCtx, logicalcluster.From(passCluster))
+		if err != nil {
+			return passResultOf(passCluster, report), err
+		}
+		defer release()
+		err = d.delete(passCtx, passCluster, report)
+		return passResultOf(passCluster, report), err
+	})
+
+	select {
+	case <-ctx.Done():
+		return &DeletionReport{}, d.interrupted(logicalCluster, ctx.Err())
+	case result := <-results:
+		pass := result.Val.(passResult)
+		pass.applyTo(logicalCluster)
+		return pass.report, result.Err
+	}
+}
+
+func (d *logicalClusterResourcesDeleter) delete(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, report *DeletionReport) (err error) {
+	ctx, logger := withLogicalClusterLogger(ctx, logicalCluster)
+	ctx, span := d.startContentDeletionSpan(ctx, logicalCluster)
+	defer func() { endSpan(span, err) }()
+	defer func() { d.forgetRecreatedOnEnd(logicalcluster.From(logicalCluster), err) }()
 
 	// the latest view of the logical cluster asserts that the logical cluster is no longer deleting..
 	if logicalCluster.DeletionTimestamp.IsZero() {
@@ -115,23 +416,126 @@ func (d *logicalClusterResourcesDeleter) Delete(ctx context.Context, logicalClus
 		return nil
 	}
 
+	if err := checkCancelled(logicalCluster); err != nil {
+		logger.V(2).Info("not deleting content", "reason", err.Error())
+		return err
+	}
+	if skipContentDeletion(logicalCluster) {
+		logger.V(2).Info("not deleting content", "reason", "skipped by annotation")
+		return nil
+	}
+	if err := d.checkDeletable(logicalCluster); err != nil {
+		logger.V(2).Info("not deleting content", "reason", err.Error())
+		return err
+	}
+	if err := d.attemptsExceeded(logicalCluster); err != nil {
+		logger.V(2).Info("not deleting content", "reason", err.Error())
+		return err
+	}
+	d.markContentDeletionStarted(logicalCluster)
+	if d.stuck != nil {
+		d.stuck.confirm(logicalcluster.From(logicalCluster), ValidateForceFinalizeConfirmation(logicalCluster) == nil)
+	}
+
+	if d.statusClient != nil {
+		defer d.writeDeletionStatus(ctx, logicalCluster, report)
+	}
+	if d.onContentDeleted != nil && !conditions.IsTrue(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted) {
+		defer func() {
+			err = d.notifyContentDeleted(ctx, logicalCluster, err)
+		}()
+	}
+
 	// there may still be content for us to remove
-	estimate, message, err := d.deleteAllContent(ctx, logicalCluster)
+	passCtx := withDeletionIssued(d.withDeletionBudget(ctx))
+	remaining, err := d.deleteAllContent(passCtx, logicalCluster, report)
+	d.markDeletionIssued(passCtx, logicalCluster)
+	d.markContention(logicalCluster, report)
 	if err != nil {
-		return err
+		logger.V(2).Info("content deletion failed", "reason", err.Error())
+		return d.countAttempt(logicalCluster, err)
 	}
+	logger.V(2).Info("content deletion pass finished", "remaining", remaining.numRemaining, "estimate", remaining.estimate)
 
-	if estimate > 0 {
-		return &ResourcesRemainingError{estimate, message}
+	if remaining.estimate > 0 {
+		if d.deletionPolicy(logicalCluster) == BestEffortDeletion {
+			d.abandonRemaining(ctx, logicalCluster, remaining)
+			return nil
+		}
+		err := NewResourcesRemainingError(remaining.estimate, remaining.message, remaining.numRemaining, retryAfter(remaining))
+		err.RemainingByResource = remaining.byResource
+		err.RemainingObjects = remaining.numRemaining - remaining.terminatingNamespaces
+		err.TerminatingNamespaces = remaining.terminatingNamespaces
+		err.UnavailableResources = remaining.unavailable
+		err.RemainingByNamespace = remaining.byNamespace
+		err.ExternallyOwned = remaining.externallyOwned
+		err.Retained = remaining.retained
+		err.ForbiddenResources = remaining.forbidden
+		return err
 	}
 
 	return nil
 }
 
+// withLogicalClusterLogger returns a context whose logger identifies the logical cluster and workspace
+// in every log line of the deleter, to correlate the logs of logical clusters terminating concurrently.
+func withLogicalClusterLogger(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) (context.Context, logr.Logger) {
+	logger := klog.FromContext(ctx).WithValues(
+		"logicalCluster", logicalcluster.From(logicalCluster).String(),
+		"workspace", logicalCluster.Annotations[core.LogicalClusterPathAnnotationKey],
+	)
+	return klog.NewContext(ctx, logger), logger
+}
+
+// resourceClient returns the metadata client for gvr in the given logical cluster.
+func (d *logicalClusterResourcesDeleter) resourceClient(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) metadata.Getter {
+	client := d.metadataClusterClient
+	if d.metadataClientFor != nil {
+		client = d.metadataClientFor(gvr)
+	}
+	return client.Cluster(clusterName.Path()).Resource(gvr)
+}
+
 // ResourcesRemainingError is used to inform the caller that all resources are not yet fully removed from the logical cluster.
 type ResourcesRemainingError struct {
 	Estimate int64
 	Message  string
+	// EstimatedRemaining is the number of resource instances remaining in the logical cluster.
+	EstimatedRemaining int
+	// RetryAfter is a hint when to check again. Zero if there is no hint beyond Estimate.
+	RetryAfter time.Duration
+	// RemainingByResource is the number of remaining instances by resource, if known.
+	RemainingByResource map[schema.GroupVersionResource]int
+	// RemainingObjects is the number of remaining instances that are not terminating namespaces.
+	RemainingObjects int
+	// TerminatingNamespaces is the number of remaining namespaces waiting to be finalized by the
+	// namespace controller. These are expected to go away without intervention.
+	TerminatingNamespaces int
+	// UnavailableResources are the resources whose server answered 503 Service Unavailable, e.g. because
+	// it is overloaded or the aggregated API server serving them is down. Their content is retried in a
+	// later pass.
+	UnavailableResources []schema.GroupVersionResource
+	// RemainingByNamespace is the number of remaining namespaced instances by namespace, if known.
+	RemainingByNamespace map[string]int
+	// ExternallyOwned is the number of remaining instances that are owned by objects outside of the
+	// logical cluster and were skipped. They are removed by the garbage collector once their owners are gone.
+	ExternallyOwned int
+	// Retained is the number of remaining instances that are younger than the minimum age and were
+	// intentionally not deleted.
+	Retained int
+	// ForbiddenResources are the resources the deleter was not permitted to delete. Their content is
+	// retried in a later pass, but needs the permissions of the deletion identity to be fixed.
+	ForbiddenResources []schema.GroupVersionResource
+}
+
+// NewResourcesRemainingError returns a ResourcesRemainingError.
+func NewResourcesRemainingError(estimate int64, message string, estimatedRemaining int, retryAfter time.Duration) *ResourcesRemainingError {
+	return &ResourcesRemainingError{
+		Estimate:           estimate,
+		Message:            message,
+		EstimatedRemaining: estimatedRemaining,
+		RetryAfter:         retryAfter,
+	}
 }
 
 func (e *ResourcesRemainingError) Error() string {
@@ -142,14 +546,37 @@ func (e *ResourcesRemainingError) Error() string {
 	return fmt.Sprintf("%s: %s", ret, e.Message)
 }
 
+// Details returns the breakdown of the remaining instances by resource and by namespace, e.g. to
+// print what is left and where. Error stays short and does not include it.
+func (e *ResourcesRemainingError) Details() string {
+	byResource := make([]string, 0, len(e.RemainingByResource))
+	for gvr, numRemaining := range e.RemainingByResource {
+		byResource = append(byResource, fmt.Sprintf("%s.%s has %d resource instances", gvr.Resource, gvr.Group, numRemaining))
+	}
+	byNamespace := make([]string, 0, len(e.RemainingByNamespace))
+	for namespace, numRemaining := range e.RemainingByNamespace {
+		byNamespace = append(byNamespace, fmt.Sprintf("namespace %s has %d resource instances", namespace, numRemaining))
+	}
+	// sort for stable output
+	sort.Strings(byResource)
+	sort.Strings(byNamespace)
+	return strings.Join(append(byResource, byNamespace...), ", ")
+}
+
 // operation is used for caching if an operation is supported on a dynamic client.
 type operation string
 
 const (
 	operationDeleteCollection operation = "deletecollection"
 	operationList             operation = "list"
+	operationWatch            operation = "watch"
+	// defaultListPageSize is the default maximum number of items returned per list call.
+	defaultListPageSize int64 = 500
 	// assume a default estimate for finalizers to complete when found on items pending deletion.
 	finalizerEstimateSeconds int64 = int64(15)
+	// maxRetryAfter bounds the retry hint of ResourcesRemainingError.
+	maxRetryAfter      = 5 * time.Minute
+	maxRetryAfterShift = 8
 )
 
 // deleteCollection is a helper function that will delete the collection of resources
@@ -162,20 +589,100 @@ func (d *logicalClusterResourcesDeleter) deleteCollection(ctx context.Context, c
 
 	if !verbs.Has(string(operationDeleteCollection)) {
 		logger.V(5).Info("operation ignored since not supported")
+		d.metrics.DeleteCollections.WithLabelValues(deleteCollectionUnsupported).Inc()
 		return false, nil
 	}
 
-	background := metav1.DeletePropagationBackground
-	opts := metav1.DeleteOptions{PropagationPolicy: &background}
-	if err := d.metadataClusterClient.Resource(gvr).Cluster(clusterName.Path()).DeleteCollection(
-		ctx, opts, metav1.ListOptions{}); err != nil {
+	if err := spendDeletionBudget(ctx); err != nil {
+		return true, err
+	}
+	if err := d.throttle(ctx); err != nil {
+		return true, err
+	}
+	if err := d.retryTransient(ctx, func() error {
+		return d.resourceClient(clusterName, gvr).Namespace(d.namespaceOf(gvr)).DeleteCollection(ctx, d.deleteOptions(), d.listOptions(gvr))
+	}); err != nil {
+		if isResourceGone(err) {
+			// e.g. the CRD of the resource was deleted earlier in the pass.
+			logger.V(5).Info("resource is gone", "reason", err.Error())
+			return true, nil
+		}
+		if errors.IsMethodNotSupported(err) {
+			// discovery may advertise deletecollection for resources whose storage rejects it.
+			logger.V(5).Info("operation ignored since not supported by the server")
+			d.metrics.DeleteCollections.WithLabelValues(deleteCollectionUnsupported).Inc()
+			return false, nil
+		}
+		d.metrics.DeleteCollections.WithLabelValues(deleteCollectionFailed).Inc()
+		if isCollectionTooLarge(err) && d.chunksByNamespace(gvr) {
+			logger.V(2).Info("deleteCollection timed out, falling back to deleting per namespace", "err", err)
+			return true, d.deleteCollectionPerNamespace(ctx, clusterName, gvr, verbs, err)
+		}
 		logger.V(5).Error(err, "unexpected deleteCollection error")
 		return true, err
 	}
 
+	logger.V(4).Info("deleted collection")
+	d.metrics.DeleteCollections.WithLabelValues(deleteCollectionSucceeded).Inc()
+	recordDeletionIssued(ctx)
 	return true, nil
 }
 
+// chunksByNamespace returns true if a delete-collection of gvr can be split into one per namespace, i.e.
+// if namespaced content is in scope and not deleted in a single namespace already.
+func (d *logicalClusterResourcesDeleter) chunksByNamespace(gvr schema.GroupVersionResource) bool {
+	if d.namespaceOf(gvr) != metav1.NamespaceAll {
+		return false
+	}
+	if d.namespacedResources != nil {
+		return d.namespacedResources[gvr]
+	}
+	return d.scope == AllScopes || d.scope == NamespacedOnly
+}
+
+// deleteCollectionPerNamespace issues one delete-collection per namespace holding items of the given
+// resource, splitting a collection the server failed to delete in one go into smaller chunks. The
+// original error is returned for cluster-scoped resources, which cannot be chunked this way.
+func (d *logicalClusterResourcesDeleter) deleteCollectionPerNamespace(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String, collectionErr error) error {
+	logger := klog.FromContext(ctx).WithValues("operation", "deleteCollectionPerNamespace", "gvr", gvr)
+
+	partialList, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+	if err != nil {
+		return err
+	}
+	if !listSupported {
+		return collectionErr
+	}
+
+	namespaces := sets.NewString()
+	for _, item := range partialList.Items {
+		if item.Namespace == metav1.NamespaceNone {
+			return collectionErr
+		}
+		namespaces.Insert(item.Namespace)
+	}
+
+	var errs []error
+	for _, ns := range namespaces.List() {
+		logger.V(5).Info("deleting collection in namespace", "namespace", ns)
+		if err := spendDeletionBudget(ctx); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := d.throttle(ctx); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := d.resourceClient(clusterName, gvr).Namespace(ns).DeleteCollection(
+			ctx, d.deleteOptions(), d.listOptions(gvr)); err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+		} else if err == nil {
+			recordDeletionIssued(ctx)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
 // listCollection will list the items in the specified logical cluster
 // it returns the following:
 //
@@ -191,41 +698,162 @@ func (d *logicalClusterResourcesDeleter) listCollection(ctx context.Context, clu
 		return nil, false, nil
 	}
 
-	partialList, err := d.metadataClusterClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	partialList, err := d.listPages(ctx, clusterName, gvr)
 	if err == nil {
 		return partialList, true, nil
 	}
 
-	// this is strange, but we need to special case for both MethodNotSupported and NotFound errors
-	// TODO: https://github.com/kubernetes/kubernetes/issues/22413
-	// we have a resource returned in the discovery API that supports no top-level verbs:
-	//  /apis/extensions/v1beta1/namespaces/default/replicationcontrollers
-	// when working with this resource type, we will get a literal not found error rather than expected method not supported
-	if errors.IsMethodNotSupported(err) || errors.IsNotFound(err) {
+	if errors.IsMethodNotSupported(err) {
 		logger.V(5).Info("operation ignored since not supported")
 		return nil, false, nil
 	}
 
+	// a resource that is not served anymore, e.g. because its CRD was deleted earlier in the pass, has
+	// no items left. This includes the literal not found error returned for resources in the discovery
+	// API that support no top-level verbs (https://github.com/kubernetes/kubernetes/issues/22413):
+	//  /apis/extensions/v1beta1/namespaces/default/replicationcontrollers
+	if isResourceGone(err) {
+		logger.V(5).Info("resource is gone", "reason", err.Error())
+		return &metav1.PartialObjectMetadataList{}, true, nil
+	}
+
 	return nil, true, err
 }
 
+// listPages lists all items of gvr, following the continue token across pages of at most
+// listPageSize items.
+func (d *logicalClusterResourcesDeleter) listPages(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource) (*metav1.PartialObjectMetadataList, error) {
+	opts := d.listOptions(gvr)
+	opts.Limit = d.listPageSize
+	var ret *metav1.PartialObjectMetadataList
+	pooled := false
+	for {
+		var page *metav1.PartialObjectMetadataList
+		err := d.retryTransient(ctx, func() (err error) {
+			page, err = d.resourceClient(clusterName, gvr).Namespace(d.namespaceOf(gvr)).List(ctx, opts)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case ret == nil:
+			ret = page
+		case d.bufferPool != nil:
+			// collect the pages in a pooled buffer.
+			if !pooled {
+				ret.Items, pooled = append(d.bufferPool.get(), ret.Items...), true
+			}
+			ret.Items = append(ret.Items, page.Items...)
+		default:
+			ret.Items = append(ret.Items, page.Items...)
+		}
+		if page.Continue == "" {
+			ret.Continue = ""
+			return ret, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
 // deleteEachItem is a helper function that will list the collection of resources and delete each item 1 by 1.
-func (d *logicalClusterResourcesDeleter) deleteEachItem(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String) error {
+func (d *logicalClusterResourcesDeleter) deleteEachItem(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String) (int, error) {
 	logger := klog.FromContext(ctx).WithValues("operation", "deleteEachItem", "gvr", gvr)
 	logger.V(5).Info("running operation")
 
 	unstructuredList, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
 	if err != nil {
-		return err
+		return -1, err
 	}
 	if !listSupported {
-		return nil
+		return -1, nil
+	}
+	found := len(unstructuredList.Items)
+
+	fresh, _ := d.partitionAwaitingFinalizers(unstructuredList.Items)
+	deleted, err := d.deleteItems(ctx, clusterName, gvr, fresh)
+	if d.manifest != nil {
+		if manifestErr := d.writeManifest(ctx, clusterName, gvr, deleted); manifestErr != nil {
+			return found, utilerrors.NewAggregate([]error{err, manifestErr})
+		}
+	}
+	if d.namespaceLabelAggregator != nil {
+		namespaceLabels := namespaceLabelCache{}
+		for _, item := range deleted {
+			if item.GetNamespace() != "" {
+				d.namespaceLabelAggregator.add(d.namespaceLabels(ctx, clusterName, namespaceLabels, item.GetNamespace()))
+			}
+		}
+	}
+	return found, err
+}
+
+// needsItemsBeforeDeletion returns true if some option needs to know the items of gvr before they are deleted.
+func (d *logicalClusterResourcesDeleter) needsItemsBeforeDeletion(gvr schema.GroupVersionResource) bool {
+	_, patch := d.preDeletePatches[gvr]
+	_, hook := d.preDeleteHooks[gvr]
+	return d.inventory != nil || d.manifest != nil || d.deletionSummary || d.onDeleted != nil || patch || hook
+}
+
+// beforeDeletion runs the steps that need to see the items of gvr before they are deleted.
+func (d *logicalClusterResourcesDeleter) beforeDeletion(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) error {
+	logger := klog.FromContext(ctx)
+
+	// in migration mode, record what is about to be deleted with orphaned dependents
+	if d.inventory != nil {
+		if err := d.recordInventory(ctx, clusterName, gvr, items); err != nil {
+			logger.V(5).Error(err, "unable to record inventory")
+			return err
+		}
 	}
 
-	for _, item := range unstructuredList.Items {
-		background := metav1.DeletePropagationBackground
-		opts := metav1.DeleteOptions{PropagationPolicy: &background}
-		if err = d.metadataClusterClient.Cluster(clusterName.Path()).Resource(gvr).Namespace(item.GetNamespace()).Delete(ctx, item.GetName(), opts); err != nil && !errors.IsNotFound(err) && !errors.IsMethodNotSupported(err) {
+	// clean up what the deletion would leak
+	if hook, ok := d.preDeleteHooks[gvr]; ok {
+		for i := range items {
+			item := &items[i]
+			if !item.DeletionTimestamp.IsZero() {
+				continue
+			}
+			if err := hook(ctx, gvr, item); err != nil {
+				logger.V(5).Error(err, "pre-delete hook failed", "namespace", item.Namespace, "name", item.Name)
+				return fmt.Errorf("pre-delete hook failed for %s %s/%s: %w", gvr, item.Namespace, item.Name, err)
+			}
+		}
+	}
+
+	// make protected objects deletable
+	if patchFn, ok := d.preDeletePatches[gvr]; ok {
+		if err := d.patchBeforeDeletion(ctx, clusterName, gvr, items, patchFn); err != nil {
+			logger.V(5).Error(err, "unable to patch items before deletion")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchBeforeDeletion patches each item with patchFn.
+func (d *logicalClusterResourcesDeleter) patchBeforeDeletion(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata, patchFn PreDeletePatchFunc) error {
+	logger := klog.FromContext(ctx).WithValues("operation", "patchBeforeDeletion", "gvr", gvr)
+	logger.V(5).Info("running operation")
+
+	for i := range items {
+		item := &items[i]
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		patchType, patch, err := patchFn(item)
+		if err != nil {
+			return fmt.Errorf("failed to compute patch for %s %s/%s: %w", gvr, item.Namespace, item.Name, err)
+		}
+		if patch == nil {
+			continue
+		}
+		logger.V(4).Info("patching item before deletion", "namespace", item.Namespace, "name", item.Name)
+		if err := d.throttle(ctx); err != nil {
+			return err
+		}
+		if _, err := d.resourceClient(clusterName, gvr).Namespace(item.Namespace).Patch(ctx, item.Name, patchType, patch, metav1.PatchOptions{}); err != nil && !errors.IsNotFound(err) {
 			return err
 		}
 	}
@@ -238,8 +866,31 @@ type gvrDeletionMetadata struct {
 	finalizerEstimateSeconds int64
 	// numRemaining is how many instances of the gvr remain
 	numRemaining int
+	// numTerminating is how many of the remaining instances are already being deleted
+	numTerminating int
+	// numFound is how many instances were listed before deleting them, -1 if they were not listed
+	numFound int
+	// deleteCollectionIssued is true if a delete-collection call was sent
+	deleteCollectionIssued bool
 	// finalizersToNumRemaining maps finalizers to how many resources are stuck on them
 	finalizersToNumRemaining map[string]int
+	// namespacesToNumRemaining is how many namespaced instances remain by namespace
+	namespacesToNumRemaining map[string]int
+	// numRetained is how many of the remaining instances are younger than the minimum age
+	numRetained int
+	// numExternallyOwned is how many of the remaining instances are owned outside of the logical cluster
+	numExternallyOwned int
+	// numVetoed is how many instances were not deleted because they were vetoed. They do not count as remaining.
+	numVetoed int
+	// numAwaitingFinalizers is how many of the remaining instances are terminating for longer than the
+	// grace period, waiting for finalizers
+	numAwaitingFinalizers int
+	// finalizersToNumAwaiting maps finalizers to how many of the instances awaiting finalizers wait for them
+	finalizersToNumAwaiting map[string]int
+	// recreated are the namespace/name of the instances recreated since a delete was issued for them
+	recreated []string
+	// countedFromCache is true if the remaining instances were counted from an informer cache
+	countedFromCache bool
 }
 
 // deleteAllContentForGroupVersionResource will use the dynamic client to delete each resource identified in gvr.
@@ -250,10 +901,22 @@ func (d *logicalClusterResourcesDeleter) deleteAllContentForGroupVersionResource
 	clusterName logicalcluster.Name,
 	gvr schema.GroupVersionResource,
 	verbs sets.String,
-	clusterDeletedAt metav1.Time) (gvrDeletionMetadata, error) {
+	clusterDeletedAt metav1.Time) (metadata gvrDeletionMetadata, err error) {
 	logger := klog.FromContext(ctx).WithValues("operation", "deleteAllContentForGroupVersionResource", "gvr", gvr)
 	logger.V(5).Info("running operation")
 
+	// record what was done for the deletion report, whichever way we return.
+	numFound, deleteCollectionIssued, numVetoed := -1, false, 0
+	var recreated []string
+	countedFromCache := false
+	defer func() {
+		metadata.numFound = numFound
+		metadata.deleteCollectionIssued = deleteCollectionIssued
+		metadata.numVetoed = numVetoed
+		metadata.recreated = recreated
+		metadata.countedFromCache = countedFromCache
+	}()
+
 	// estimate how long it will take for the resource to be deleted (needed for objects that support graceful delete)
 	estimate, err := d.estimateGracefulTermination(ctx, gvr, clusterName, clusterDeletedAt)
 	if err != nil {
@@ -262,43 +925,174 @@ func (d *logicalClusterResourcesDeleter) deleteAllContentForGroupVersionResource
 	}
 	logger.V(5).Info("created estimate", "estimate", estimate)
 
-	// first try to delete the entire collection
-	deleteCollectionSupported, err := d.deleteCollection(ctx, clusterName, gvr, verbs)
-	if err != nil {
-		return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
-	}
-
-	// delete collection was not supported, so we list and delete each item...
-	if !deleteCollectionSupported {
-		err = d.deleteEachItem(ctx, clusterName, gvr, verbs)
+	// listed are the items before they were deleted, if listed.
+	var listed *metav1.PartialObjectMetadataList
+	owners := localOwners{}
+	if d.skipExternallyOwned || d.minAge > 0 || d.shouldDeleteObject != nil {
+		// delete-collection would also delete externally owned, retained or vetoed items, so they are deleted one by one.
+		listed, err = d.deleteEligible(ctx, clusterName, gvr, verbs, owners)
+		if listed != nil {
+			numFound = len(listed.Items)
+			recreated = append(recreated, d.observeRecreated(clusterName, gvr, listed.Items)...)
+		}
 		if err != nil {
 			return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
 		}
+	} else {
+		// when listing first, there is nothing to do for empty collections. Some options also need
+		// to know the items before they are deleted.
+		// with a deletion budget, empty resources must not spend it.
+		// items awaiting finalizers are not deleted again.
+		// recreated items are recognized by the UIDs of the items deleted.
+		listFirst := d.deletionOrder(gvr) == ListThenDelete || d.maxDeletionsPerPass > 0 || d.awaitingFinalizersGrace > 0 || d.recreation != nil
+		onlyAwaitingFinalizers := false
+		if needsItems := d.needsItemsBeforeDeletion(gvr); listFirst || needsItems {
+			logger.V(5).Info("checking for items before deleting")
+			unstructuredList, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+			if err != nil {
+				return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
+			}
+			if listSupported {
+				numFound = len(unstructuredList.Items)
+				listed = unstructuredList
+				recreated = append(recreated, d.observeRecreated(clusterName, gvr, unstructuredList.Items)...)
+			}
+			if listSupported && listFirst && len(unstructuredList.Items) == 0 {
+				return gvrDeletionMetadata{finalizerEstimateSeconds: 0, numRemaining: 0}, nil
+			}
+			if listSupported {
+				fresh, _ := d.partitionAwaitingFinalizers(unstructuredList.Items)
+				onlyAwaitingFinalizers = len(fresh) == 0
+			}
+			if listSupported && needsItems && !onlyAwaitingFinalizers {
+				if err := d.beforeDeletion(ctx, clusterName, gvr, unstructuredList.Items); err != nil {
+					return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
+				}
+			}
+		}
+
+		if onlyAwaitingFinalizers {
+			logger.V(5).Info("skipping deletion of items awaiting finalizers")
+		} else {
+			// first try to delete the entire collection
+			deleteCollectionIssued = verbs.Has(string(operationDeleteCollection))
+			deleteCollectionSupported, err := d.deleteCollection(ctx, clusterName, gvr, verbs)
+			if err != nil {
+				return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
+			}
+			// the listed items are written to the manifest once their collection is deleted. Objects
+			// deleted one by one are written when they are deleted.
+			if deleteCollectionSupported && d.manifest != nil && listed != nil {
+				if err := d.writeManifest(ctx, clusterName, gvr, listed.Items); err != nil {
+					logger.V(5).Error(err, "unable to write manifest")
+					return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
+				}
+			}
+
+			// delete collection was not supported, so we list and delete each item...
+			if !deleteCollectionSupported {
+				found, err := d.deleteEachItem(ctx, clusterName, gvr, verbs)
+				if numFound < 0 {
+					numFound = found
+				}
+				if err != nil {
+					return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
+				}
+			}
+		}
 	}
 
 	// verify there are no more remaining items
 	// it is not an error condition for there to be remaining items if local estimate is non-zero
 	logger.V(5).Info("checking for no more items")
-	unstructuredList, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+	unstructuredList, listSupported, countedFromCache, err := d.countRemaining(ctx, clusterName, gvr, verbs)
 	if err != nil {
 		logger.V(5).Error(err, "error verifying no items in logical cluster")
 		return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
 	}
+	// the remaining items are only counted, they do not outlive this call.
+	defer func() {
+		d.releaseList(unstructuredList)
+	}()
+	if listSupported && len(unstructuredList.Items) > 0 && d.gracePeriod > 0 {
+		logger.V(5).Info("waiting for remaining items to be finalized", "remaining", len(unstructuredList.Items), "gracePeriod", d.gracePeriod)
+		if err := d.waitFor(ctx, d.gracePeriod); err != nil {
+			return gvrDeletionMetadata{finalizerEstimateSeconds: estimate, numRemaining: len(unstructuredList.Items)}, err
+		}
+		d.releaseList(unstructuredList)
+		unstructuredList, listSupported, countedFromCache, err = d.countRemaining(ctx, clusterName, gvr, verbs)
+		if err != nil {
+			logger.V(5).Error(err, "error verifying no items in logical cluster after the grace period")
+			return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, err
+		}
+	}
 	if !listSupported {
 		return gvrDeletionMetadata{finalizerEstimateSeconds: estimate}, nil
 	}
+	if d.onDeleted != nil && listed != nil {
+		d.reportDeleted(gvr, listed.Items, unstructuredList.Items)
+	}
+	// remaining items are deleted again by the next pass.
+	recreated = append(recreated, d.observeRecreated(clusterName, gvr, unstructuredList.Items)...)
+	if len(recreated) > 0 {
+		logger.V(2).Info("objects recreated during deletion", "objects", recreated)
+	}
+	if d.shouldDeleteObject != nil {
+		// vetoed items are intentionally left behind and do not count as remaining.
+		var vetoed []metav1.PartialObjectMetadata
+		unstructuredList.Items, vetoed = d.partitionVetoed(gvr, unstructuredList.Items)
+		numVetoed = len(vetoed)
+	}
 	logger.V(5).Info("items remaining", "remaining", len(unstructuredList.Items))
+	if err := d.forceRemoveFinalizers(ctx, clusterName, gvr, unstructuredList.Items); err != nil {
+		logger.V(5).Error(err, "unable to remove finalizers of stuck items")
+		return gvrDeletionMetadata{finalizerEstimateSeconds: estimate, numRemaining: len(unstructuredList.Items)}, err
+	}
 	if len(unstructuredList.Items) == 0 {
 		// we're done
 		return gvrDeletionMetadata{finalizerEstimateSeconds: 0, numRemaining: 0}, nil
 	}
+	numExternallyOwned := 0
+	eligible := unstructuredList.Items
+	if d.skipExternallyOwned {
+		local, external, err := d.partitionExternallyOwned(ctx, clusterName, unstructuredList.Items, owners)
+		if err != nil {
+			return gvrDeletionMetadata{finalizerEstimateSeconds: estimate, numRemaining: len(unstructuredList.Items)}, err
+		}
+		numExternallyOwned = len(external)
+		eligible = local
+	}
+	numRetained := 0
+	if d.minAge > 0 {
+		_, retained, retainedEstimate := d.partitionRetained(eligible)
+		numRetained = len(retained)
+		// retained items are only deleted once they are old enough.
+		if retainedEstimate > estimate {
+			estimate = retainedEstimate
+		}
+	}
 
 	// use the list to find the finalizers
 	finalizersToNumRemaining := map[string]int{}
-	for _, item := range unstructuredList.Items {
+	namespacesToNumRemaining := map[string]int{}
+	numTerminating := 0
+	numAwaitingFinalizers, finalizersToNumAwaiting := 0, map[string]int{}
+	for i, item := range unstructuredList.Items {
 		for _, finalizer := range item.GetFinalizers() {
 			finalizersToNumRemaining[finalizer]++
 		}
+		if d.isAwaitingFinalizersPastGrace(&unstructuredList.Items[i]) {
+			numAwaitingFinalizers++
+			for _, finalizer := range item.GetFinalizers() {
+				finalizersToNumAwaiting[finalizer]++
+			}
+		}
+		if item.GetNamespace() != metav1.NamespaceNone {
+			namespacesToNumRemaining[item.GetNamespace()]++
+		}
+		if item.GetDeletionTimestamp() != nil {
+			numTerminating++
+		}
 	}
 
 	if estimate != int64(0) {
@@ -306,7 +1100,13 @@ func (d *logicalClusterResourcesDeleter) deleteAllContentForGroupVersionResource
 		return gvrDeletionMetadata{
 			finalizerEstimateSeconds: estimate,
 			numRemaining:             len(unstructuredList.Items),
+			numTerminating:           numTerminating,
 			finalizersToNumRemaining: finalizersToNumRemaining,
+			namespacesToNumRemaining: namespacesToNumRemaining,
+			numExternallyOwned:       numExternallyOwned,
+			numRetained:              numRetained,
+			numAwaitingFinalizers:    numAwaitingFinalizers,
+			finalizersToNumAwaiting:  finalizersToNumAwaiting,
 		}, nil
 	}
 
@@ -316,7 +1116,28 @@ func (d *logicalClusterResourcesDeleter) deleteAllContentForGroupVersionResource
 		return gvrDeletionMetadata{
 			finalizerEstimateSeconds: finalizerEstimateSeconds,
 			numRemaining:             len(unstructuredList.Items),
+			numTerminating:           numTerminating,
 			finalizersToNumRemaining: finalizersToNumRemaining,
+			namespacesToNumRemaining: namespacesToNumRemaining,
+			numExternallyOwned:       numExternallyOwned,
+			numRetained:              numRetained,
+			numAwaitingFinalizers:    numAwaitingFinalizers,
+			finalizersToNumAwaiting:  finalizersToNumAwaiting,
+		}, nil
+	}
+
+	// externally owned items are expected to remain until the garbage collector removes them.
+	if numExternallyOwned > 0 {
+		logger.V(5).Info("items remaining with external owners", "externallyOwned", numExternallyOwned)
+		return gvrDeletionMetadata{
+			finalizerEstimateSeconds: finalizerEstimateSeconds,
+			numRemaining:             len(unstructuredList.Items),
+			numTerminating:           numTerminating,
+			namespacesToNumRemaining: namespacesToNumRemaining,
+			numExternallyOwned:       numExternallyOwned,
+			numRetained:              numRetained,
+			numAwaitingFinalizers:    numAwaitingFinalizers,
+			finalizersToNumAwaiting:  finalizersToNumAwaiting,
 		}, nil
 	}
 
@@ -324,6 +1145,10 @@ func (d *logicalClusterResourcesDeleter) deleteAllContentForGroupVersionResource
 	return gvrDeletionMetadata{
 		finalizerEstimateSeconds: estimate,
 		numRemaining:             len(unstructuredList.Items),
+		numTerminating:           numTerminating,
+		namespacesToNumRemaining: namespacesToNumRemaining,
+		numAwaitingFinalizers:    numAwaitingFinalizers,
+		finalizersToNumAwaiting:  finalizersToNumAwaiting,
 	}, fmt.Errorf("unexpected items still remain in logical cluster: %s for gvr: %v", clusterName, gvr)
 }
 
@@ -334,10 +1159,48 @@ type allGVRDeletionMetadata struct {
 	finalizersToNumRemaining map[string]int
 }
 
+// contentRemaining describes the content left in a logical cluster after a deletion pass.
+type contentRemaining struct {
+	// estimate is an estimate in seconds of the time remaining before the remaining resources are deleted.
+	// If estimate > 0, not all resources are guaranteed to be gone.
+	estimate int64
+	message  string
+	// numRemaining is how many instances remain across all resources.
+	numRemaining int
+	// byResource is how many instances remain by resource.
+	byResource map[schema.GroupVersionResource]int
+	// byNamespace is how many namespaced instances remain by namespace.
+	byNamespace map[string]int
+	// terminatingNamespaces is how many of the remaining instances are namespaces waiting to be finalized.
+	terminatingNamespaces int
+	// unavailable are the resources whose API was temporarily unavailable, sorted.
+	unavailable []schema.GroupVersionResource
+	// forbidden are the resources the deleter was not permitted to delete, sorted.
+	forbidden []schema.GroupVersionResource
+	// gvrsPendingFinalizers is how many resources have remaining instances waiting for finalizers.
+	gvrsPendingFinalizers int
+	// externallyOwned is how many of the remaining instances are owned outside of the logical cluster.
+	externallyOwned int
+	// retained is how many of the remaining instances are younger than the minimum age.
+	retained int
+}
+
+// remainingError returns a ResourcesRemainingError with the given message and the breakdown of the
+// remaining instances.
+func (r contentRemaining) remainingError(message string) *ResourcesRemainingError {
+	err := NewResourcesRemainingError(r.estimate, message, r.numRemaining, 0)
+	err.RemainingByResource = r.byResource
+	err.RemainingByNamespace = r.byNamespace
+	err.ExternallyOwned = r.externallyOwned
+	err.Retained = r.retained
+	return err
+}
+
 // deleteAllContent will use the dynamic client to delete each resource identified in groupVersionResources.
-// It returns an estimate of the time remaining before the remaining resources are deleted.
-// If estimate > 0, not all resources are guaranteed to be gone.
-func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, ws *corev1alpha1.LogicalCluster) (int64, string, error) {
+// It returns what remains before all resources are deleted.
+func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, ws *corev1alpha1.LogicalCluster, report *DeletionReport) (contentRemaining, error) {
+	defer report.sort()
+
 	logger := klog.FromContext(ctx).WithValues("operation", "deleteAllContent")
 	logger.V(5).Info("running operation")
 
@@ -347,80 +1210,212 @@ func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, w
 
 	// discover resources first
 	var deletionContentSuccessReason string
-	resources, err := d.discoverResourcesFn(logicalcluster.From(ws).Path())
+	failures := deletionFailures{gvrs: map[schema.GroupVersionResource]error{}}
+	resources, err := d.discoverResources(logicalcluster.From(ws))
+	if isLogicalClusterGone(err) {
+		// nothing is served for the logical cluster anymore, e.g. because its shard was decommissioned.
+		// There is no content left that we could delete, so don't loop on errors.
+		logger.V(2).Info("logical cluster is gone, considering content deleted", "reason", err.Error())
+		setDeletionConditions(ws, corev1.ConditionTrue, "LogicalClusterGone", conditionsv1alpha1.ConditionSeverityNone, "The logical cluster is not served anymore")
+		return contentRemaining{}, nil
+	}
+	markResourceDiscovery(ws, err)
 	if err != nil {
 		// discovery errors are not fatal.  We often have some set of resources we can operate against even if we don't have a complete list
+		err = &DiscoveryFailedError{Err: err}
 		errs = append(errs, err)
+		failures.discovery = err
 		deletionContentSuccessReason = "DiscoveryFailed"
 	}
 
-	deletableResources := discovery.FilteredBy(and{
-		discovery.SupportsAllVerbs{Verbs: []string{"delete"}},
-
-		// LogicalCluster is the trigger for the whole deletion. Don't block on it.
-		isNotGroupResource{group: core.GroupName, resource: "logicalclusters"},
-
-		// Keep the logical cluster accessible for users in case they have to debug.
-		isNotGroupResource{group: rbac.GroupName, resource: "clusterroles"},
-		isNotGroupResource{group: rbac.GroupName, resource: "clusterrolebindings"},
-
-		// Don't try to delete projected resources - these are virtual projections and we shouldn't try to delete them.
-		// The projections will disappear when the real underlying data are deleted.
-		isNotVirtualResource{},
-		// no need to delete namespace scoped resource since it will be handled by namespace deletion anyway. This
-		// can avoid redundant list/delete requests.
-		isNotNamespaceScoped{},
-	}, resources)
-	groupVersionResources, err := groupVersionResources(deletableResources)
+	groupVersionResources, err := d.candidateGroupVersionResources(resources, contentDeletionVerbs)
 	if err != nil {
 		// discovery errors are not fatal.  We often have some set of resources we can operate against even if we don't have a complete list
 		errs = append(errs, err)
+		failures.parsing = err
 		deletionContentSuccessReason = "GroupVersionParsingFailed"
 	}
+	self, err := d.selfDependencies(ctx, logicalcluster.From(ws))
+	if err != nil {
+		// without knowing them, the objects granting the deleter its access could be deleted too early.
+		err = fmt.Errorf("failed to look up the objects the deleter depends on: %w", err)
+		setDeletionConditions(ws, corev1.ConditionFalse, "SelfDependencyLookupFailed", conditionsv1alpha1.ConditionSeverityWarning, truncateFailure(err))
+		return contentRemaining{}, err
+	}
+	groupVersionResources, protected := d.partitionProtected(groupVersionResources)
+	groupVersionResources, protected = partitionHalted(ws, groupVersionResources, protected)
+	groupVersionResources, numCheckpointed := d.partitionCheckpointed(ws, groupVersionResources)
 
 	numRemainingTotals := allGVRDeletionMetadata{
 		gvrToNumRemaining:        map[schema.GroupVersionResource]int{},
 		finalizersToNumRemaining: map[string]int{},
 	}
 	deleteContentErrs := []error{}
-	for gvr, verbs := range groupVersionResources {
-		gvrDeletionMetadata, err := d.deleteAllContentForGroupVersionResource(ctx, logicalcluster.From(ws), gvr, verbs, clusterDeletedAt)
-		if err != nil {
-			// If there is an error, hold on to it but proceed with all the remaining
-			// groupVersionResources.
-			deleteContentErrs = append(deleteContentErrs, err)
-		}
-		if gvrDeletionMetadata.finalizerEstimateSeconds > estimate {
-			estimate = gvrDeletionMetadata.finalizerEstimateSeconds
-		}
-		if gvrDeletionMetadata.numRemaining > 0 {
-			numRemainingTotals.gvrToNumRemaining[gvr] = gvrDeletionMetadata.numRemaining
-			for finalizer, numRemaining := range gvrDeletionMetadata.finalizersToNumRemaining {
-				if numRemaining == 0 {
-					continue
+	byNamespace := map[string]int{}
+	gvrsPendingFinalizers := 0
+	terminatingNamespaces := 0
+	externallyOwned := 0
+	retained := 0
+	var unavailable, forbidden, overBudget []schema.GroupVersionResource
+	phasesDeferred := false
+	deleted := map[string]int{}
+	var emptied, cached []schema.GroupVersionResource
+	numAwaitingFinalizers, finalizersToNumAwaiting := 0, map[string]int{}
+	numSucceeded := 0
+	for i, phase := range groupByDeletionPhase(groupVersionResources, self) {
+		failed := len(deleteContentErrs) > 0 && !d.continueOnError
+		if len(numRemainingTotals.gvrToNumRemaining) > 0 || failed || len(unavailable) > 0 || len(overBudget) > 0 {
+			// later phases wait for the earlier ones to complete.
+			logger.V(5).Info("deferring deletion phase", "phase", i, "resources", len(phase))
+			phasesDeferred = true
+			break
+		}
+		if d.deletionPriority != nil {
+			sortByDeletionPriority(phase, d.deletionPriority)
+		}
+		if err := ctx.Err(); err != nil {
+			return contentRemaining{estimate: estimate}, d.interrupted(ws, err)
+		}
+		results := d.deleteAllContentForPhase(ctx, logicalcluster.From(ws), phase, groupVersionResources, clusterDeletedAt)
+		if err := ctx.Err(); err != nil {
+			// results of an interrupted phase are incomplete.
+			return contentRemaining{estimate: estimate}, d.interrupted(ws, err)
+		}
+		for _, result := range results {
+			report.add(result)
+			gvr, gvrDeletionMetadata := result.gvr, result.metadata
+			if n := deletedInstances(result); n > 0 {
+				deleted[gvr.GroupResource().String()] += n
+			}
+			if result.err == nil {
+				numSucceeded++
+			}
+			if result.err == nil && result.metadata.countedFromCache && gvrDeletionMetadata.numRemaining == 0 {
+				cached = append(cached, gvr)
+			}
+			if result.err == nil && !result.settled && len(result.deferredBy) == 0 && gvrDeletionMetadata.numRemaining == 0 && groupVersionResources[gvr].Has(string(operationList)) {
+				// only resources verified to be empty by a list call are checkpointed.
+				emptied = append(emptied, gvr)
+			}
+			if isDeletionBudgetExhausted(result.err) {
+				// the pass has issued as many deletion calls as it may. The resource is deleted in the next pass.
+				overBudget = append(overBudget, gvr)
+			} else if isServiceUnavailable(result.err) {
+				// e.g. the server is overloaded or the aggregated API server serving the resource is down. This
+				// is not a failure of the deletion itself, so the resource is retried in a later pass.
+				unavailable = append(unavailable, gvr)
+				d.event(ws, corev1.EventTypeNormal, eventReasonDeletingContent, "Waiting for the API of %s.%s to become available: %v", gvr.Resource, gvr.Group, truncateFailure(result.err))
+			} else if errors.IsForbidden(result.err) && !isQuotaInterference([]error{result.err}) {
+				// the deletion identity lacks permissions. The resource is skipped instead of blocking the
+				// other resources, and retried in a later pass in case the permissions are granted.
+				forbidden = append(forbidden, gvr)
+				d.event(ws, corev1.EventTypeWarning, eventReasonDeletionFailed, "Not permitted to delete %s.%s: %v", gvr.Resource, gvr.Group, truncateFailure(result.err))
+			} else if result.err != nil {
+				// If there is an error, hold on to it but proceed with all the remaining
+				// groupVersionResources.
+				deleteContentErrs = append(deleteContentErrs, result.err)
+				failures.gvrs[gvr] = result.err
+				d.event(ws, corev1.EventTypeWarning, eventReasonDeletionFailed, "Failed to delete %s.%s: %v", gvr.Resource, gvr.Group, truncateFailure(result.err))
+			} else if gvrDeletionMetadata.numRemaining > 0 {
+				d.event(ws, corev1.EventTypeNormal, eventReasonDeletingContent, "Waiting for %d instances of %s.%s to be deleted", gvrDeletionMetadata.numRemaining, gvr.Resource, gvr.Group)
+			}
+			if gvrDeletionMetadata.finalizerEstimateSeconds > estimate {
+				estimate = gvrDeletionMetadata.finalizerEstimateSeconds
+			}
+			d.metrics.observeRemaining(logicalcluster.From(ws), gvr, gvrDeletionMetadata.numRemaining)
+			if gvrDeletionMetadata.numRemaining > 0 {
+				numRemainingTotals.gvrToNumRemaining[gvr] = gvrDeletionMetadata.numRemaining
+				for namespace, numRemaining := range gvrDeletionMetadata.namespacesToNumRemaining {
+					byNamespace[namespace] += numRemaining
+				}
+				if gvr.GroupResource() == namespacesGVR.GroupResource() {
+					terminatingNamespaces += gvrDeletionMetadata.numTerminating
+				}
+				externallyOwned += gvrDeletionMetadata.numExternallyOwned
+				retained += gvrDeletionMetadata.numRetained
+				numAwaitingFinalizers += gvrDeletionMetadata.numAwaitingFinalizers
+				for finalizer, n := range gvrDeletionMetadata.finalizersToNumAwaiting {
+					finalizersToNumAwaiting[finalizer] += n
+				}
+				pendingFinalizers := false
+				for finalizer, numRemaining := range gvrDeletionMetadata.finalizersToNumRemaining {
+					if numRemaining == 0 {
+						continue
+					}
+					numRemainingTotals.finalizersToNumRemaining[finalizer] += numRemaining
+					pendingFinalizers = true
+				}
+				if pendingFinalizers {
+					gvrsPendingFinalizers++
 				}
-				numRemainingTotals.finalizersToNumRemaining[finalizer] += numRemaining
 			}
 		}
 	}
 
+	if d.deletionSummary {
+		addToDeletionSummary(ws, deleted)
+	}
+	d.addToDeletionCheckpoint(ws, emptied)
+	d.markAwaitingFinalizers(ws, numAwaitingFinalizers, finalizersToNumAwaiting)
+
 	if len(deleteContentErrs) > 0 {
 		errs = append(errs, deleteContentErrs...)
 		deletionContentSuccessReason = "ContentDeletionFailed"
+		if isQuotaInterference(deleteContentErrs) {
+			deletionContentSuccessReason = "WaitingOnQuota"
+		}
 	}
 
 	var contentRemainingMessages []string
 	if len(numRemainingTotals.gvrToNumRemaining) != 0 {
 		remainingResources := []string{}
 		for gvr, numRemaining := range numRemainingTotals.gvrToNumRemaining {
-			if numRemaining == 0 {
+			if gvr.GroupResource() == namespacesGVR.GroupResource() {
+				// terminating namespaces are reported separately below.
+				numRemaining -= terminatingNamespaces
+			}
+			if numRemaining <= 0 {
 				continue
 			}
 			remainingResources = append(remainingResources, fmt.Sprintf("%s.%s has %d resource instances", gvr.Resource, gvr.Group, numRemaining))
 		}
 		// sort for stable updates
 		sort.Strings(remainingResources)
-		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Some resources are remaining: %s", strings.Join(remainingResources, ", ")))
+		if len(remainingResources) > 0 {
+			contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Some resources are remaining: %s", strings.Join(remainingResources, ", ")))
+		}
+	}
+	if len(overBudget) > 0 {
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Deferring the deletion of %s to the next pass to stay within %d deletion calls per pass", strings.Join(groupResourceNames(overBudget), ", "), d.maxDeletionsPerPass))
+		if estimate < deletionBudgetEstimate {
+			estimate = deletionBudgetEstimate
+		}
+	}
+	if len(unavailable) > 0 {
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Some APIs are temporarily unavailable: %s", strings.Join(groupResourceNames(unavailable), ", ")))
+		if estimate < unavailableAPIEstimate {
+			estimate = unavailableAPIEstimate
+		}
+	}
+	if len(forbidden) > 0 || !phasesDeferred {
+		// resources of deferred phases have not been attempted, so they might still be forbidden.
+		markDeletionForbidden(ws, forbidden)
+	}
+	if len(forbidden) > 0 {
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Not permitted to delete some resources: %s", strings.Join(groupResourceNames(forbidden), ", ")))
+		if estimate < forbiddenEstimate {
+			estimate = forbiddenEstimate
+		}
+	}
+	if terminatingNamespaces > 0 {
+		// namespaces are finalized by the namespace controller once their content is gone. This is expected and resolves on its own.
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Waiting for %d terminating namespaces to be finalized", terminatingNamespaces))
+	}
+	if externallyOwned > 0 {
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Waiting for the garbage collector to remove %d resource instances owned outside of the logical cluster", externallyOwned))
+	}
+	if retained > 0 {
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Retaining %d resource instances younger than %s", retained, d.minAge))
 	}
 	if len(numRemainingTotals.finalizersToNumRemaining) != 0 {
 		remainingByFinalizer := []string{}
@@ -434,33 +1429,170 @@ func (d *logicalClusterResourcesDeleter) deleteAllContent(ctx context.Context, w
 		sort.Strings(remainingByFinalizer)
 		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("Some content in the logical cluster has finalizers remaining: %s", strings.Join(remainingByFinalizer, ", ")))
 	}
+	numRemaining := 0
+	for _, n := range numRemainingTotals.gvrToNumRemaining {
+		numRemaining += n
+	}
+	if eta, ok := estimateCompletion(ws, numRemaining, d.clock.Now()); ok && len(contentRemainingMessages) > 0 {
+		contentRemainingMessages = append(contentRemainingMessages, fmt.Sprintf("estimated %s remaining", eta.Round(time.Second)))
+	}
 	if len(contentRemainingMessages) > 0 {
 		message := strings.Join(contentRemainingMessages, "; ")
-		conditions.MarkFalse(
-			ws,
-			tenancyv1alpha1.WorkspaceContentDeleted,
-			"SomeResourcesRemain",
-			conditionsv1alpha1.ConditionSeverityInfo,
-			message,
-		)
+		if len(forbidden) > 0 {
+			// missing permissions do not resolve on their own.
+			setDeletionConditions(ws, corev1.ConditionFalse, "DeletionForbidden", conditionsv1alpha1.ConditionSeverityWarning, message)
+		} else {
+			setDeletionConditions(ws, corev1.ConditionFalse, "SomeResourcesRemain", conditionsv1alpha1.ConditionSeverityInfo, message)
+		}
 		logger.V(4).Error(utilerrors.NewAggregate(errs), "resource remaining")
-		return estimate, message, utilerrors.NewAggregate(errs)
+		return contentRemaining{
+			estimate:              estimate,
+			message:               message,
+			numRemaining:          numRemaining,
+			byResource:            numRemainingTotals.gvrToNumRemaining,
+			byNamespace:           byNamespace,
+			terminatingNamespaces: terminatingNamespaces,
+			unavailable:           unavailable,
+			forbidden:             forbidden,
+			gvrsPendingFinalizers: gvrsPendingFinalizers,
+			externallyOwned:       externallyOwned,
+			retained:              retained,
+		}, utilerrors.NewAggregate(errs)
 	}
 
 	if len(errs) > 0 {
-		conditions.MarkFalse(
-			ws,
-			tenancyv1alpha1.WorkspaceContentDeleted,
-			deletionContentSuccessReason,
-			conditionsv1alpha1.ConditionSeverityError,
-			utilerrors.NewAggregate(errs).Error(),
-		)
+		if d.continueOnError && len(deleteContentErrs) > 0 && numSucceeded > 0 {
+			// the resources that did not fail were deleted nonetheless.
+			message := fmt.Sprintf("Deleted the content of %d resources, but %s", numSucceeded, failures.message())
+			setDeletionConditions(ws, corev1.ConditionFalse, "ContentDeletionPartiallyFailed", conditionsv1alpha1.ConditionSeverityError, message)
+			logger.Error(utilerrors.NewAggregate(errs), "content deletion partially failed", "succeeded", numSucceeded)
+			return contentRemaining{estimate: estimate, message: "ContentDeletionPartiallyFailed"}, utilerrors.NewAggregate(errs)
+		}
+		setDeletionConditions(ws, corev1.ConditionFalse, deletionContentSuccessReason, conditionsv1alpha1.ConditionSeverityError, failures.message())
 		logger.Error(utilerrors.NewAggregate(errs), "content deletion failed", "message", deletionContentSuccessReason)
-		return estimate, deletionContentSuccessReason, utilerrors.NewAggregate(errs)
+		return contentRemaining{estimate: estimate, message: deletionContentSuccessReason}, utilerrors.NewAggregate(errs)
+	}
+
+	if numCheckpointed > 0 {
+		// instances of resources emptied by earlier passes might have been recreated since. They are all
+		// verified by another pass before the content deletion completes.
+		delete(ws.Annotations, DeletionCheckpointAnnotationKey)
+		message := fmt.Sprintf("Verifying %d resources emptied by earlier passes", numCheckpointed)
+		setDeletionConditions(ws, corev1.ConditionFalse, "SomeResourcesRemain", conditionsv1alpha1.ConditionSeverityInfo, message)
+		return contentRemaining{estimate: checkpointVerifyEstimate, message: message}, nil
+	}
+
+	if len(cached) > 0 {
+		// informer caches might be stale. Before the content deletion completes, the resources they
+		// reported empty are confirmed by a live list.
+		stale, err := d.confirmEmpty(ctx, logicalcluster.From(ws), cached, groupVersionResources)
+		if err != nil {
+			setDeletionConditions(ws, corev1.ConditionFalse, "ContentDeletionFailed", conditionsv1alpha1.ConditionSeverityError, truncateFailure(err))
+			return contentRemaining{estimate: estimate, message: "ContentDeletionFailed"}, err
+		}
+		if len(stale) > 0 {
+			numStale := 0
+			for _, n := range stale {
+				numStale += n
+			}
+			message := staleCacheMessage(stale)
+			setDeletionConditions(ws, corev1.ConditionFalse, "SomeResourcesRemain", conditionsv1alpha1.ConditionSeverityInfo, message)
+			return contentRemaining{estimate: staleCacheEstimate, message: message, numRemaining: numStale, byResource: stale}, nil
+		}
+	}
+
+	protectedRemaining, err := d.protectedRemaining(ctx, logicalcluster.From(ws), protected)
+	if err == nil && len(protectedRemaining) > 0 {
+		err = fmt.Errorf("protected resources remain: %s", protectedRemaining)
+	}
+	if err != nil {
+		setDeletionConditions(ws, corev1.ConditionFalse, "ProtectedResourcesRemaining", conditionsv1alpha1.ConditionSeverityWarning, truncateFailure(err))
+		logger.V(2).Info("content deletion blocked by protected resources", "reason", err.Error())
+		return contentRemaining{estimate: estimate, message: "ProtectedResourcesRemaining"}, err
+	}
+
+	if d.settled != nil {
+		d.settled.forget(logicalcluster.From(ws))
+	}
+	if d.stuck != nil {
+		d.stuck.forget(logicalcluster.From(ws))
+	}
+	if d.recreation != nil {
+		d.recreation.forget(logicalcluster.From(ws))
+	}
+	forgetEstimatedCompletion(ws)
+	delete(ws.Annotations, DeletionCheckpointAnnotationKey)
+	if d.scope == NamespacedOnly {
+		// the cluster-scoped content is left to a later pass of another scope.
+		setDeletionConditions(ws, corev1.ConditionFalse, "ClusterScopedContentRemaining", conditionsv1alpha1.ConditionSeverityInfo, "Namespaced content has been deleted, cluster-scoped content is not in scope")
+		return contentRemaining{estimate: estimate}, nil
+	}
+	d.event(ws, corev1.EventTypeNormal, eventReasonContentDeleted, "All content of the logical cluster has been deleted")
+	var message string
+	if d.deletionSummary {
+		message = completeDeletionSummary(ws)
 	}
+	setDeletionConditions(ws, corev1.ConditionTrue, "ContentDeleted", conditionsv1alpha1.ConditionSeverityNone, message)
+	return contentRemaining{estimate: estimate}, nil
+}
+
+// interrupted marks the content deletion of the logical cluster as unknown, because the context was
+// cancelled or its deadline exceeded before the pass completed, and returns the wrapped context error.
+func (d *logicalClusterResourcesDeleter) interrupted(ws *corev1alpha1.LogicalCluster, err error) error {
+	setDeletionConditions(ws, corev1.ConditionUnknown, "DeletionInterrupted", conditionsv1alpha1.ConditionSeverityNone, fmt.Sprintf("content deletion was interrupted: %v", err))
+	return fmt.Errorf("content deletion of logical cluster %s interrupted: %w", logicalcluster.From(ws), err)
+}
 
-	conditions.MarkTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted)
-	return estimate, "", nil
+// deletableGroupVersionResources filters the discovered resources down to those whose content
+// is deleted, and returns their verbs by GroupVersionResource.
+func (d *logicalClusterResourcesDeleter) deletableGroupVersionResources(resources []*metav1.APIResourceList) (map[schema.GroupVersionResource]sets.String, error) {
+	groupVersionResources, err := d.candidateGroupVersionResources(resources, contentDeletionVerbs)
+	deletable, _ := d.partitionProtected(groupVersionResources)
+	return deletable, err
+}
+
+// contentDeletionVerbs are the verbs a resource must support for its content to be deleted. Content
+// of resources without deletecollection is deleted one by one.
+var contentDeletionVerbs = []string{"delete"}
+
+// candidateGroupVersionResources filters the discovered resources down to those that support all of
+// verbs and whose content would be deleted if not protected, and returns their verbs by GroupVersionResource.
+func (d *logicalClusterResourcesDeleter) candidateGroupVersionResources(resources []*metav1.APIResourceList, verbs []string) (map[schema.GroupVersionResource]sets.String, error) {
+	resources = d.applyVerbOverrides(resources)
+	deletableResources := discovery.FilteredBy(d.isDeletableResource(d.clock.Now(), verbs), resources)
+	groupVersionResources, err := groupVersionResources(deletableResources)
+	groupVersionResources = resolveVersions(groupVersionResources, preferredVersions(resources))
+	return resolveGroupMigrations(groupVersionResources, d.groupMigrations), err
+}
+
+// isDeletableResource returns the predicate selecting the resources deleted with a logical cluster
+// that support all of verbs.
+func (d *logicalClusterResourcesDeleter) isDeletableResource(now time.Time, verbs []string) discovery.ResourcePredicate {
+	ret := and{
+		discovery.SupportsAllVerbs{Verbs: verbs},
+		isNotSubresource{},
+
+		// LogicalCluster is the trigger for the whole deletion. Don't block on it.
+		isNotGroupResource{group: core.GroupName, resource: "logicalclusters"},
+
+		d.isNotExcludedResource(now),
+
+		// Don't try to delete projected resources - these are virtual projections and we shouldn't try to delete them.
+		// The projections will disappear when the real underlying data are deleted.
+		isNotVirtualResource{},
+	}
+	if d.namespacedResources == nil {
+		switch d.scope {
+		case AllScopes:
+		case NamespacedOnly:
+			ret = append(ret, isNamespaceScoped{})
+		default:
+			// no need to delete namespace scoped resource since it will be handled by namespace deletion anyway. This
+			// can avoid redundant list/delete requests.
+			ret = append(ret, isNotNamespaceScoped{})
+		}
+	}
+	return ret
 }
 
 // estimateGracefulTermination will estimate the graceful termination required for the specific entity in the logical cluster.
@@ -472,6 +1604,56 @@ func (d *logicalClusterResourcesDeleter) estimateGracefulTermination(ctx context
 	return estimate, nil
 }
 
+// waitFor waits for the given duration, or returns the error of ctx once it is done.
+func (d *logicalClusterResourcesDeleter) waitFor(ctx context.Context, duration time.Duration) error {
+	timer := d.clock.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// isCollectionTooLarge returns true if the server failed to delete a collection because of its size.
+func isCollectionTooLarge(err error) bool {
+	return errors.IsTimeout(err) || errors.IsServerTimeout(err) || errors.IsRequestEntityTooLargeError(err)
+}
+
+// retryAfter returns a hint when to check the remaining content again. It backs off exponentially
+// with the number of resources waiting for finalizers, such that many terminating logical clusters
+// blocked on slow finalizers don't requeue at the same pace.
+func retryAfter(remaining contentRemaining) time.Duration {
+	if remaining.gvrsPendingFinalizers == 0 {
+		return 0
+	}
+	shift := remaining.gvrsPendingFinalizers - 1
+	if shift > maxRetryAfterShift {
+		shift = maxRetryAfterShift
+	}
+	ret := time.Duration(remaining.estimate) * time.Second << shift
+	if ret > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return ret
+}
+
+// unavailableAPIEstimate is the estimate in seconds before an unavailable API is retried.
+const unavailableAPIEstimate = int64(15)
+
+// isServiceUnavailable returns true if the server answered 503 Service Unavailable for a resource. This
+// covers every resource, not only those of groups served by an APIService: the proxy of an aggregated
+// API server reports this way that the server is down, and any server does when it is overloaded.
+func isServiceUnavailable(err error) bool {
+	return err != nil && errors.IsServiceUnavailable(err)
+}
+
+// isResourceGone returns true if the resource is no longer served, e.g. because its CRD was deleted.
+func isResourceGone(err error) bool {
+	return meta.IsNoMatchError(err) || errors.IsNotFound(err)
+}
+
 // GroupVersionResources converts APIResourceLists to the GroupVersionResources with verbs as value.
 func groupVersionResources(rls []*metav1.APIResourceList) (map[schema.GroupVersionResource]sets.String, error) {
 	gvrs := map[schema.GroupVersionResource]sets.String{}
@@ -513,6 +1695,13 @@ func (vr isNotVirtualResource) Match(groupVersion string, r *metav1.APIResource)
 	return !projection.Includes(gvr)
 }
 
+type isNotSubresource struct{}
+
+// Match checks if the resource is not a subresource. Subresources are deleted with their parent.
+func (n isNotSubresource) Match(groupVersion string, r *metav1.APIResource) bool {
+	return !strings.Contains(r.Name, "/")
+}
+
 type isNotNamespaceScoped struct{}
 
 // Match checks if the resource is a cluster scoped resource.
@@ -520,6 +1709,13 @@ func (n isNotNamespaceScoped) Match(groupVersion string, r *metav1.APIResource)
 	return !r.Namespaced
 }
 
+type isNamespaceScoped struct{}
+
+// Match checks if the resource is a namespace scoped resource.
+func (n isNamespaceScoped) Match(groupVersion string, r *metav1.APIResource) bool {
+	return r.Namespaced
+}
+
 type and []discovery.ResourcePredicate
 
 func (a and) Match(groupVersion string, r *metav1.APIResource) bool {
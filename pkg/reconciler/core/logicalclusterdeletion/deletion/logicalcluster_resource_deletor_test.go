@@ -17,23 +17,59 @@ limitations under the License.
 package deletion
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr/funcr"
+	"github.com/google/go-cmp/cmp"
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	kcpmetadata "github.com/kcp-dev/client-go/metadata"
 	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	kcpfakemetadata "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/metadata/fake"
 	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/flowcontrol"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/testutil"
+	"k8s.io/klog/v2"
+	clocktesting "k8s.io/utils/clock/testing"
 
+	"github.com/kcp-dev/kcp/pkg/apis/core"
 	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
 	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
 )
 
 var scheme *runtime.Scheme
@@ -77,6 +113,47 @@ func TestWorkspaceTerminating(t *testing.T) {
 				{
 					Type:   tenancyv1alpha1.WorkspaceContentDeleted,
 					Status: v1.ConditionFalse,
+					Reason: "DiscoveryFailed",
+				},
+				{
+					Type:   tenancyv1alpha1.WorkspaceResourceDiscoverySuccess,
+					Status: v1.ConditionFalse,
+					Reason: "DiscoveryFailed",
+				},
+			},
+		},
+		{
+			name:                    "logical cluster is gone",
+			existingObject:          []runtime.Object{},
+			metadataClientActionSet: []metaAction{},
+			gvrError:                fmt.Errorf("shard decommissioned: %w", ErrLogicalClusterGone),
+			expectConditions: conditionsv1alpha1.Conditions{
+				{
+					Type:   tenancyv1alpha1.WorkspaceContentDeleted,
+					Status: v1.ConditionTrue,
+					Reason: "LogicalClusterGone",
+				},
+			},
+		},
+		{
+			name:           "discovery not found does not mean the logical cluster is gone",
+			existingObject: []runtime.Object{},
+			metadataClientActionSet: []metaAction{
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+			},
+			gvrError:            errors.NewNotFound(schema.GroupResource{}, ""),
+			expectErrorOnDelete: errors.NewNotFound(schema.GroupResource{}, ""),
+			expectConditions: conditionsv1alpha1.Conditions{
+				{
+					Type:   tenancyv1alpha1.WorkspaceContentDeleted,
+					Status: v1.ConditionFalse,
+					Reason: "DiscoveryFailed",
+				},
+				{
+					Type:   tenancyv1alpha1.WorkspaceResourceDiscoverySuccess,
+					Status: v1.ConditionFalse,
+					Reason: "DiscoveryFailed",
 				},
 			},
 		},
@@ -94,6 +171,11 @@ func TestWorkspaceTerminating(t *testing.T) {
 				{
 					Type:   tenancyv1alpha1.WorkspaceContentDeleted,
 					Status: v1.ConditionTrue,
+					Reason: "ContentDeleted",
+				},
+				{
+					Type:   tenancyv1alpha1.WorkspaceResourceDiscoverySuccess,
+					Status: v1.ConditionTrue,
 				},
 			},
 		},
@@ -107,11 +189,12 @@ func TestWorkspaceTerminating(t *testing.T) {
 				{"customresourcedefinitions", "delete-collection"},
 				{"customresourcedefinitions", "list"},
 			},
-			expectErrorOnDelete: &ResourcesRemainingError{5, "Some resources are remaining: customresourcedefinitions.apiextensions.k8s.io has 2 resource instances"},
+			expectErrorOnDelete: &ResourcesRemainingError{Estimate: 5, Message: "Some resources are remaining: customresourcedefinitions.apiextensions.k8s.io has 2 resource instances"},
 			expectConditions: conditionsv1alpha1.Conditions{
 				{
 					Type:   tenancyv1alpha1.WorkspaceContentDeleted,
 					Status: v1.ConditionFalse,
+					Reason: "SomeResourcesRemain",
 				},
 			},
 		},
@@ -138,6 +221,9 @@ func TestWorkspaceTerminating(t *testing.T) {
 				if cond.Status != expCondition.Status {
 					t.Errorf("expect condition status %q, got %q for type %s", expCondition.Status, cond.Status, cond.Type)
 				}
+				if cond.Reason != expCondition.Reason {
+					t.Errorf("expect condition reason %q, got %q for type %s", expCondition.Reason, cond.Reason, cond.Type)
+				}
 			}
 
 			if len(mockMetadataClient.Actions()) != len(tt.metadataClientActionSet) {
@@ -153,79 +239,4827 @@ func TestWorkspaceTerminating(t *testing.T) {
 	}
 }
 
-type metaAction struct {
-	resource string
-	verb     string
-}
-
-type metaActionSet []metaAction
+func TestSetDeletionConditions(t *testing.T) {
+	ws := newTerminatingLogicalCluster()
+	conditions.MarkTrue(ws, tenancyv1alpha1.WorkspaceDeletionStalled)
 
-func (m metaActionSet) match(action kcptesting.Action) bool {
-	for _, a := range m {
-		if action.Matches(a.verb, a.resource) {
-			return true
-		}
+	setDeletionConditions(ws, v1.ConditionFalse, "SomeResourcesRemain", conditionsv1alpha1.ConditionSeverityInfo, "Some resources are remaining")
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionStalled) {
+		t.Error("expected the logical cluster to stay stalled while content remains")
+	}
+	if reason := conditions.GetReason(ws, tenancyv1alpha1.WorkspaceContentDeleted); reason != "SomeResourcesRemain" {
+		t.Errorf("expected reason SomeResourcesRemain, got %q", reason)
 	}
 
-	return false
+	setDeletionConditions(ws, v1.ConditionTrue, "ContentDeleted", conditionsv1alpha1.ConditionSeverityNone, "")
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Error("expected the content to be deleted")
+	}
+	if c := conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionStalled); c != nil {
+		t.Errorf("expected no WorkspaceDeletionStalled condition once the content is deleted, got %v", c)
+	}
 }
 
-func newPartialObject(apiversion, kind, name, namespace string) *metav1.PartialObjectMetadata {
-	return &metav1.PartialObjectMetadata{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: apiversion,
-			Kind:       kind,
+func TestWorkspaceTerminatingDeletionOrder(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+	tests := []struct {
+		name                    string
+		order                   DeletionOrder
+		existingObject          []runtime.Object
+		metadataClientActionSet metaActionSet
+	}{
+		{
+			name:  "delete then verify, empty",
+			order: DeleteThenVerify,
+			metadataClientActionSet: []metaAction{
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+			},
 		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:        name,
-			Namespace:   namespace,
-			Annotations: map[string]string{logicalcluster.AnnotationKey: "root"},
+		{
+			name:  "list then delete, empty",
+			order: ListThenDelete,
+			metadataClientActionSet: []metaAction{
+				{"customresourcedefinitions", "list"},
+			},
+		},
+		{
+			name:  "list then delete, not empty",
+			order: ListThenDelete,
+			existingObject: []runtime.Object{
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+			},
+			metadataClientActionSet: []metaAction{
+				{"customresourcedefinitions", "list"},
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+			},
 		},
 	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := newTerminatingLogicalCluster()
+			fn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, tt.existingObject...)
+			order := tt.order
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn, WithDeletionOrder(func(gvr schema.GroupVersionResource) DeletionOrder {
+				if gvr == crds {
+					return order
+				}
+				return ""
+			}))
+
+			_ = d.Delete(context.TODO(), ws)
+			tt.metadataClientActionSet.expectInOrder(t, mockMetadataClient.Actions())
+		})
+	}
 }
 
-// testResources returns a mocked up set of resources across different api groups for testing namespace controller.
-func testResources() []*metav1.APIResourceList {
-	results := []*metav1.APIResourceList{
-		{
-			GroupVersion: "v1",
-			APIResources: []metav1.APIResource{
-				{
-					Name:       "secrets",
-					Namespaced: true,
-					Kind:       "Secret",
-					Verbs:      []string{"get", "list", "delete", "deletecollection", "create", "update"},
-				},
-				{
-					Name:       "nodelete",
-					Namespaced: true,
-					Kind:       "NoDelete",
-					Verbs:      []string{"get", "list", "create", "update"},
-				},
+func TestWorkspaceTerminatingSettledResources(t *testing.T) {
+	ws := newTerminatingLogicalCluster()
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{
+				Name:       "widgets",
+				Namespaced: false,
+				Kind:       "Widget",
+				Verbs:      []string{"get", "list", "delete", "deletecollection"},
 			},
 		},
+	})
+	fn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}
+	// widgets never drain, so the logical cluster is deleted in multiple passes.
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, newPartialObject("example.com/v1", "Widget", "w1", ""))
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn, WithSettledAfter(1, 0))
+
+	for pass, expected := range []metaActionSet{
 		{
-			GroupVersion: "apiextensions.k8s.io/v1",
-			APIResources: []metav1.APIResource{
-				{
-					Name:       "customresourcedefinitions",
-					Namespaced: false,
-					Kind:       "CustomResourceDefinition",
-					Verbs:      []string{"get", "list", "delete", "deletecollection", "create", "update"},
-				},
-			},
+			{"widgets", "delete-collection"},
+			{"widgets", "list"},
+			// the CRDs are listed to check whether the remaining widgets are custom resources.
+			{"customresourcedefinitions", "list"},
+			{"customresourcedefinitions", "delete-collection"},
+			{"customresourcedefinitions", "list"},
+		},
+		{
+			{"widgets", "delete-collection"},
+			{"widgets", "list"},
+			{"customresourcedefinitions", "list"},
 		},
+	} {
+		mockMetadataClient.ClearActions()
+		if err := d.Delete(context.TODO(), ws); err == nil {
+			t.Fatalf("pass %d: expected remaining resources", pass)
+		}
+		if len(mockMetadataClient.Actions()) != len(expected) {
+			t.Fatalf("pass %d: mismatched actions, expect %d actions, got %d actions: %v", pass, len(expected), len(mockMetadataClient.Actions()), mockMetadataClient.Actions())
+		}
+		for _, action := range mockMetadataClient.Actions() {
+			if !expected.match(action) {
+				t.Errorf("pass %d: unexpected action %v", pass, action)
+			}
+		}
 	}
-	return results
 }
 
-// matchError returns true if errors match, false if they don't, compares by error message only for convenience which should be sufficient for these tests.
-func matchErrors(e1, e2 error) bool {
-	if e1 == nil && e2 == nil {
-		return true
+type fakeInventoryRecorder struct {
+	objects []OrphanedObject
+}
+
+func (r *fakeInventoryRecorder) Record(ctx context.Context, clusterName logicalcluster.Name, objects []OrphanedObject) error {
+	r.objects = append(r.objects, objects...)
+	return nil
+}
+
+func TestWorkspaceTerminatingScope(t *testing.T) {
+	tests := []struct {
+		name              string
+		opts              []Option
+		deleteCollections []string
+		contentDeleted    bool
+	}{
+		{name: "default", deleteCollections: []string{"customresourcedefinitions"}, contentDeleted: true},
+		{name: "cluster-scoped only", opts: []Option{WithScope(ClusterScopedOnly)}, deleteCollections: []string{"customresourcedefinitions"}, contentDeleted: true},
+		{name: "namespaced only", opts: []Option{WithScope(NamespacedOnly)}, deleteCollections: []string{"secrets"}},
+		{name: "all scopes", opts: []Option{WithScope(AllScopes)}, deleteCollections: []string{"customresourcedefinitions", "secrets"}, contentDeleted: true},
 	}
-	if e1 != nil && e2 != nil {
-		return e1.Error() == e2.Error()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, tt.opts...)
+
+			ws := newTerminatingLogicalCluster()
+			if err := d.Delete(context.TODO(), ws); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var deleteCollections []string
+			for _, action := range mockMetadataClient.Actions() {
+				if action.GetVerb() == "delete-collection" {
+					deleteCollections = append(deleteCollections, action.GetResource().Resource)
+				}
+			}
+			sort.Strings(deleteCollections)
+			if diff := cmp.Diff(tt.deleteCollections, deleteCollections); diff != "" {
+				t.Errorf("unexpected delete-collection calls (-want +got):\n%s", diff)
+			}
+			if got := conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted); got != tt.contentDeleted {
+				t.Errorf("expected content deleted %v, got %v", tt.contentDeleted, got)
+			}
+		})
 	}
-	return false
+}
+
+func TestWorkspaceTerminatingMigrationInventory(t *testing.T) {
+	ws := newTerminatingLogicalCluster()
+	fn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}
+	crd := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", "")
+	crd.UID = "uid-crd1"
+	fakeClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, crd)
+	mockMetadataClient := &deleteOptionsRecorder{ClusterInterface: fakeClient}
+	recorder := &fakeInventoryRecorder{}
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn, WithMigrationInventory(recorder))
+
+	_ = d.Delete(context.TODO(), ws)
+
+	metaActionSet{
+		{"customresourcedefinitions", "list"},
+		{"customresourcedefinitions", "delete-collection"},
+		{"customresourcedefinitions", "list"},
+	}.expectInOrder(t, fakeClient.Actions())
+
+	expected := []OrphanedObject{{
+		GVR:  schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+		Name: "crd1",
+		UID:  "uid-crd1",
+	}}
+	if diff := cmp.Diff(expected, recorder.objects); diff != "" {
+		t.Errorf("unexpected inventory: %s", diff)
+	}
+	for _, opts := range mockMetadataClient.options {
+		if opts.PropagationPolicy == nil || *opts.PropagationPolicy != metav1.DeletePropagationOrphan {
+			t.Errorf("expected orphan propagation, got %v", opts.PropagationPolicy)
+		}
+	}
+	if len(mockMetadataClient.options) != 1 {
+		t.Errorf("expected 1 delete call, got %d", len(mockMetadataClient.options))
+	}
+}
+
+func TestWorkspaceTerminatingPropagationPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected metav1.DeletionPropagation
+	}{
+		{name: "default", expected: metav1.DeletePropagationBackground},
+		{name: "foreground", opts: []Option{WithPropagationPolicy(metav1.DeletePropagationForeground)}, expected: metav1.DeletePropagationForeground},
+		{name: "orphan", opts: []Option{WithPropagationPolicy(metav1.DeletePropagationOrphan)}, expected: metav1.DeletePropagationOrphan},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+			)
+			// fall back to deleting one by one, such that both delete calls are issued.
+			fakeClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.NewMethodNotSupported(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, "deletecollection")
+			})
+			mockMetadataClient := &deleteOptionsRecorder{ClusterInterface: fakeClient}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, tt.opts...)
+
+			if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			metaActionSet{
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+				{"customresourcedefinitions", "delete"},
+				{"customresourcedefinitions", "list"},
+			}.expectInOrder(t, fakeClient.Actions())
+			// the fake client does not keep the options on its actions.
+			if len(mockMetadataClient.options) != 2 {
+				t.Fatalf("expected 2 delete calls, got %d", len(mockMetadataClient.options))
+			}
+			for _, opts := range mockMetadataClient.options {
+				if opts.PropagationPolicy == nil || *opts.PropagationPolicy != tt.expected {
+					t.Errorf("expected %s propagation, got %v", tt.expected, opts.PropagationPolicy)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingPreDeletePatch(t *testing.T) {
+	ws := newTerminatingLogicalCluster()
+	fn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""))
+
+	// the collection refuses deletion unless it has been patched before.
+	patched := false
+	mockMetadataClient.PrependReactor("patch", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		patched = true
+		return true, nil, nil
+	})
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if !patched {
+			return true, nil, errors.NewForbidden(crds.GroupResource(), "crd1", fmt.Errorf("deletion protection is enabled"))
+		}
+		return true, nil, nil
+	})
+
+	var patchedNames []string
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn, WithPreDeletePatches(map[schema.GroupVersionResource]PreDeletePatchFunc{
+		crds: func(obj *metav1.PartialObjectMetadata) (types.PatchType, []byte, error) {
+			patchedNames = append(patchedNames, obj.Name)
+			return types.MergePatchType, []byte(`{"spec":{"deletionProtection":false}}`), nil
+		},
+	}))
+
+	err := d.Delete(context.TODO(), ws)
+	var remaining *ResourcesRemainingError
+	if !goerrors.As(err, &remaining) {
+		t.Fatalf("expected remaining resources after deletion, got %v", err)
+	}
+
+	metaActionSet{
+		{"customresourcedefinitions", "list"},
+		{"customresourcedefinitions", "patch"},
+		{"customresourcedefinitions", "delete-collection"},
+		{"customresourcedefinitions", "list"},
+	}.expectInOrder(t, mockMetadataClient.Actions())
+	if diff := cmp.Diff([]string{"crd1"}, patchedNames); diff != "" {
+		t.Errorf("unexpected patched objects: %s", diff)
+	}
+}
+
+func TestDeleteEachItemNamespaceLabelAggregation(t *testing.T) {
+	labeled := func(obj *metav1.PartialObjectMetadata, labels map[string]string) *metav1.PartialObjectMetadata {
+		obj.Labels = labels
+		return obj
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		labeled(newPartialObject("v1", "Namespace", "ns1", ""), map[string]string{"team": "a"}),
+		labeled(newPartialObject("v1", "Namespace", "ns2", ""), map[string]string{"team": "b"}),
+		newPartialObject("v1", "Namespace", "ns3", ""),
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("v1", "Secret", "s2", "ns1"),
+		newPartialObject("v1", "Secret", "s3", "ns2"),
+		newPartialObject("v1", "Secret", "s4", "ns3"),
+	)
+	aggregator := NewNamespaceLabelAggregator("team")
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, nil, WithNamespaceLabelAggregator(aggregator)).(*logicalClusterResourcesDeleter)
+
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	if _, err := d.deleteEachItem(context.TODO(), logicalcluster.Name("root"), secrets, sets.NewString("list", "delete")); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(map[string]int{"a": 2, "b": 1, "": 1}, aggregator.Counts("team")); diff != "" {
+		t.Errorf("unexpected counts: %s", diff)
+	}
+}
+
+func TestDeleteItemsAdaptiveBatch(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	var items []metav1.PartialObjectMetadata
+	for i := 0; i < 10; i++ {
+		items = append(items, *newPartialObject("example.com/v1", "Widget", fmt.Sprintf("w%d", i), ""))
+	}
+
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	var lock sync.Mutex
+	throttled := false
+	mockMetadataClient.PrependReactor("delete", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		// throttle once when the batch size has grown
+		if action.(kcptesting.DeleteAction).GetName() == "w5" && !throttled {
+			throttled = true
+			return true, nil, errors.NewTooManyRequests("slow down", 1)
+		}
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, nil, WithAdaptiveDeleteBatch(1, 4)).(*logicalClusterResourcesDeleter)
+
+	deleted, err := d.deleteItems(context.TODO(), logicalcluster.Name("root"), widgets, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != len(items) {
+		t.Errorf("expected %d deleted items, got %d", len(items), len(deleted))
+	}
+	if !throttled {
+		t.Errorf("expected the server to throttle")
+	}
+	// 10 deletions plus the throttled attempt
+	if got := len(mockMetadataClient.Actions()); got != 11 {
+		t.Errorf("expected 11 delete actions, got %d", got)
+	}
+}
+
+func BenchmarkDeleteItems(b *testing.B) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	var items []metav1.PartialObjectMetadata
+	for i := 0; i < 100; i++ {
+		items = append(items, *newPartialObject("example.com/v1", "Widget", fmt.Sprintf("w%d", i), ""))
+	}
+
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "serial"},
+		{name: "adaptive", opts: []Option{WithAdaptiveDeleteBatch(4, 32)}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			fakeClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			fakeClient.PrependReactor("delete", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, nil
+			})
+			// simulate the server round-trip outside of the fake, which serializes all calls
+			mockMetadataClient := &deleteOptionsRecorder{ClusterInterface: fakeClient, latency: 100 * time.Microsecond}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, nil, bm.opts...).(*logicalClusterResourcesDeleter)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := d.deleteItems(context.TODO(), logicalcluster.Name("root"), widgets, items); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupByDeletionPhase(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	serviceAccounts := schema.GroupVersionResource{Version: "v1", Resource: "serviceaccounts"}
+	roleBindings := schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "rolebindings"}
+	quotas := schema.GroupVersionResource{Version: "v1", Resource: "resourcequotas"}
+
+	phases := groupByDeletionPhase(map[schema.GroupVersionResource]sets.String{
+		crds:            sets.NewString("delete"),
+		secrets:         sets.NewString("delete"),
+		serviceAccounts: sets.NewString("delete"),
+		roleBindings:    sets.NewString("delete"),
+		quotas:          sets.NewString("delete"),
+	}, map[schema.GroupResource]bool{
+		secrets.GroupResource():         true,
+		serviceAccounts.GroupResource(): true,
+		roleBindings.GroupResource():    true,
+	})
+	if len(phases) != 3 {
+		t.Fatalf("expected 3 phases, got %v", phases)
+	}
+	if diff := cmp.Diff([]schema.GroupVersionResource{quotas}, phases[0]); diff != "" {
+		t.Errorf("expected quotas to be removed before other content: %s", diff)
+	}
+	if diff := cmp.Diff([]schema.GroupVersionResource{crds}, phases[1]); diff != "" {
+		t.Errorf("unexpected default phase: %s", diff)
+	}
+	// sorted by group, then resource.
+	if diff := cmp.Diff([]schema.GroupVersionResource{secrets, serviceAccounts, roleBindings}, phases[len(phases)-1]); diff != "" {
+		t.Errorf("expected the resources of the deleter itself in the final phase: %s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingSelfDependenciesLast(t *testing.T) {
+	resources := NewResourceListBuilder().
+		Add("", "v1", "configmaps", "ConfigMap", true, "get", "list", "delete", "deletecollection").
+		Add("", "v1", "namespaces", "Namespace", false, "get", "list", "delete", "deletecollection").
+		Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection").
+		Add("", "v1", "serviceaccounts", "ServiceAccount", true, "get", "list", "delete", "deletecollection").
+		Add("rbac.authorization.k8s.io", "v1", "rolebindings", "RoleBinding", true, "get", "list", "delete", "deletecollection").
+		Add("storage.k8s.io", "v1", "storageclasses", "StorageClass", false, "get", "list", "delete", "deletecollection").
+		Build()
+	deleter := types.NamespacedName{Namespace: "kube-system", Name: "deleter"}
+	clusterAnnotations := map[string]string{logicalcluster.AnnotationKey: "root"}
+	kubeClient := kcpfakekubeclient.NewSimpleClientset(
+		&v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: deleter.Namespace, Name: deleter.Name, Annotations: clusterAnnotations}},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: deleter.Namespace, Name: "deleter-token", Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "root",
+				v1.ServiceAccountNameKey:     deleter.Name,
+			}},
+			Type: v1.SecretTypeServiceAccountToken,
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: deleter.Namespace, Name: "deleter", Annotations: clusterAnnotations},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: deleter.Namespace, Name: deleter.Name}},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "admin"},
+		},
+	)
+
+	for name, tt := range map[string]struct {
+		opts     []Option
+		expected []string
+	}{
+		"without a self identity": {
+			expected: []string{"configmaps", "secrets", "serviceaccounts", "rolebindings", "storageclasses", "namespaces"},
+		},
+		"with a self identity": {
+			opts: []Option{WithSelfIdentity(deleter, kubeClient)},
+			// the service account, its token secret and its role binding are deleted after all other content.
+			expected: []string{"configmaps", "storageclasses", "secrets", "serviceaccounts", "rolebindings", "namespaces"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return resources, nil
+			}, append([]Option{WithScope(AllScopes)}, tt.opts...)...)
+			if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var deleteCollections []string
+			for _, action := range mockMetadataClient.Actions() {
+				if action.GetVerb() == "delete-collection" {
+					deleteCollections = append(deleteCollections, action.GetResource().Resource)
+				}
+			}
+			if diff := cmp.Diff(tt.expected, deleteCollections); diff != "" {
+				t.Errorf("unexpected deletion order (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingNamespacesLast(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Namespace", "ns1", ""),
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+	)
+	mockMetadataClient.PrependReactor("delete-collection", "secrets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, "ns1", "s1")
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return NewResourceListBuilder().
+			Add("", "v1", "namespaces", "Namespace", false, "get", "list", "delete", "deletecollection").
+			Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection").
+			Build(), nil
+	}, WithScope(AllScopes))
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError for the namespace, got %v", err)
+	}
+	var deleteCollections []string
+	for _, action := range mockMetadataClient.Actions() {
+		if action.GetVerb() == "delete-collection" {
+			deleteCollections = append(deleteCollections, action.GetResource().Resource)
+		}
+	}
+	if diff := cmp.Diff([]string{"secrets", "namespaces"}, deleteCollections); diff != "" {
+		t.Errorf("expected namespaces to be deleted after their content (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingManifestFailedDeletion(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewBadRequest("rejected")
+	})
+	var buf bytes.Buffer
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithManifest(NewJSONManifestWriter(&buf)))
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err == nil {
+		t.Fatal("expected the failed delete-collection to fail the pass")
+	}
+	if buf.Len() > 0 {
+		t.Errorf("expected no manifest entries for objects that were not deleted, got %s", buf.String())
+	}
+}
+
+func TestWorkspaceTerminatingExplicitResources(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	mockMetadataClient.PrependReactor("delete-collection", "secrets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, "ns1", "s1")
+	})
+	var discovered []string
+	discoverGroupVersion := func(clusterName logicalcluster.Path, groupVersion string) (*metav1.APIResourceList, error) {
+		discovered = append(discovered, groupVersion)
+		for _, list := range testResources() {
+			if list.GroupVersion == groupVersion {
+				return list, nil
+			}
+		}
+		return nil, errors.NewNotFound(schema.GroupResource{}, groupVersion)
+	}
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		t.Fatalf("unexpected discovery of %s", clusterName)
+		return testResources(), nil
+	}, WithScope(NamespacedOnly), WithExplicitResources([]schema.GroupVersionResource{secrets}, discoverGroupVersion))
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var deleteCollections []string
+	for _, action := range mockMetadataClient.Actions() {
+		if action.GetResource().Resource == "customresourcedefinitions" {
+			t.Errorf("expected customresourcedefinitions to be untouched, got %s", action.GetVerb())
+		}
+		if action.GetVerb() == "delete-collection" {
+			deleteCollections = append(deleteCollections, action.GetResource().Resource)
+		}
+	}
+	if diff := cmp.Diff([]string{"secrets"}, deleteCollections); diff != "" {
+		t.Errorf("expected only the secrets to be deleted as namespaced (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"v1"}, discovered); diff != "" {
+		t.Errorf("expected only the GroupVersion of the secrets to be discovered (-want +got):\n%s", diff)
+	}
+}
+
+func TestExplicitResourceLists(t *testing.T) {
+	d := NewWorkspacedResourcesDeleter(kcpfakemetadata.NewSimpleMetadataClient(scheme), nil, WithExplicitResources([]schema.GroupVersionResource{
+		{Version: "v1", Resource: "secrets"},
+		{Version: "v1", Resource: "unserved"},
+		{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+		{Group: "unserved.example.com", Version: "v1", Resource: "widgets"},
+	}, func(clusterName logicalcluster.Path, groupVersion string) (*metav1.APIResourceList, error) {
+		for _, list := range testResources() {
+			if list.GroupVersion == groupVersion {
+				return list, nil
+			}
+		}
+		return nil, errors.NewNotFound(schema.GroupResource{}, groupVersion)
+	})).(*logicalClusterResourcesDeleter)
+
+	lists, err := d.explicitResourceLists("root")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the secrets are namespaced without any instances, and keep their discovered verbs.
+	expected := NewResourceListBuilder().
+		Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection", "create", "update").
+		Add("apiextensions.k8s.io", "v1", "customresourcedefinitions", "CustomResourceDefinition", false, "get", "list", "delete", "deletecollection", "create", "update").
+		Build()
+	if diff := cmp.Diff(expected, lists); diff != "" {
+		t.Errorf("unexpected resource lists (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingQuotaInterference(t *testing.T) {
+	crds := schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+	tests := map[string]struct {
+		// err is shaped like the errors of admission.NewForbidden and the RBAC authorizer.
+		err        error
+		wantReason string
+	}{
+		"resource quota": {
+			err:        errors.NewForbidden(crds, "", goerrors.New("exceeded quota: compute, requested: count/customresourcedefinitions.apiextensions.k8s.io=1, used: count/customresourcedefinitions.apiextensions.k8s.io=10, limited: count/customresourcedefinitions.apiextensions.k8s.io=10")),
+			wantReason: "WaitingOnQuota",
+		},
+		"limit range": {
+			err:        errors.NewForbidden(crds, "", utilerrors.NewAggregate([]error{goerrors.New("maximum cpu usage per Container is 1, but limit is 2")})),
+			wantReason: "WaitingOnQuota",
+		},
+		"rbac forbidden on quotas": {
+			err:        errors.NewForbidden(schema.GroupResource{Resource: "resourcequotas"}, "", goerrors.New(`User "deleter" cannot deletecollection resource "resourcequotas" in API group "" in the namespace "default"`)),
+			wantReason: "DeletionForbidden",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ws := newTerminatingLogicalCluster()
+			fn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, tt.err
+			})
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn)
+
+			if err := d.Delete(context.TODO(), ws); err == nil {
+				t.Fatal("expected an error")
+			}
+			cond := conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted)
+			if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != tt.wantReason {
+				t.Errorf("expected condition reason %s, got %v", tt.wantReason, cond)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingManifest(t *testing.T) {
+	ws := newTerminatingLogicalCluster()
+	fn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}
+	crd1 := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", "")
+	crd1.UID = "uid-crd1"
+	crd2 := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", "")
+	crd2.UID = "uid-crd2"
+	// already terminating objects have been written to the manifest before.
+	crd2.DeletionTimestamp = ws.DeletionTimestamp
+	crd2.Finalizers = []string{"example.com/finalizer"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, crd1, crd2)
+
+	var buf bytes.Buffer
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, fn, WithManifest(NewJSONManifestWriter(&buf)))
+	_ = d.Delete(context.TODO(), ws)
+
+	metaActionSet{
+		{"customresourcedefinitions", "list"},
+		{"customresourcedefinitions", "delete-collection"},
+		{"customresourcedefinitions", "list"},
+	}.expectInOrder(t, mockMetadataClient.Actions())
+
+	var entries []ManifestEntry
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var entry ManifestEntry
+		if err := decoder.Decode(&entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry.DeletedAt.IsZero() {
+			t.Errorf("expected deletion timestamp on %v", entry)
+		}
+		entry.DeletedAt = metav1.Time{}
+		entries = append(entries, entry)
+	}
+	expected := []ManifestEntry{{
+		Cluster:  "root",
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+		Name:     "crd1",
+		UID:      "uid-crd1",
+	}}
+	if diff := cmp.Diff(expected, entries); diff != "" {
+		t.Errorf("unexpected manifest: %s", diff)
+	}
+}
+
+func TestResolveGroupMigrations(t *testing.T) {
+	legacyDeployments := schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "deployments"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	legacyIngresses := schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "ingresses"}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+	tests := []struct {
+		name     string
+		gvrs     []schema.GroupVersionResource
+		expected []string
+	}{
+		{
+			name:     "both groups served",
+			gvrs:     []schema.GroupVersionResource{legacyDeployments, deployments, crds},
+			expected: []string{deployments.String(), crds.String()},
+		},
+		{
+			name:     "only the legacy group served",
+			gvrs:     []schema.GroupVersionResource{legacyIngresses, crds},
+			expected: []string{legacyIngresses.String(), crds.String()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gvrs := map[schema.GroupVersionResource]sets.String{}
+			for _, gvr := range tt.gvrs {
+				gvrs[gvr] = sets.NewString("list", "delete", "deletecollection")
+			}
+			got := sets.NewString()
+			for gvr := range resolveGroupMigrations(gvrs, defaultGroupMigrations) {
+				got.Insert(gvr.String())
+			}
+			if expected := sets.NewString(tt.expected...); !got.Equal(expected) {
+				t.Errorf("expected %v, got %v", expected.List(), got.List())
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingMultipleVersions(t *testing.T) {
+	widgets := func(version string) *metav1.APIResourceList {
+		return &metav1.APIResourceList{
+			GroupVersion: "example.com/" + version,
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+		}
+	}
+	gadgets := &metav1.APIResourceList{
+		GroupVersion: "example.com/v1alpha1",
+		APIResources: []metav1.APIResource{{Name: "gadgets", Kind: "Gadget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	}
+
+	tests := []struct {
+		name     string
+		lists    []*metav1.APIResourceList
+		expected string
+	}{
+		{
+			name:     "preferred version listed first",
+			lists:    []*metav1.APIResourceList{widgets("v1beta1"), widgets("v1")},
+			expected: "v1beta1",
+		},
+		{
+			name:     "highest version if the preferred version does not serve the resource",
+			lists:    []*metav1.APIResourceList{gadgets, widgets("v1beta1"), widgets("v1"), widgets("v1alpha2")},
+			expected: "v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := append(testResources(), tt.lists...)
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return resources, nil
+			})
+
+			if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+				t.Fatalf("expected no remaining content, got %v", err)
+			}
+			var versions []string
+			for _, action := range mockMetadataClient.Actions() {
+				if action.Matches("delete-collection", "widgets") {
+					versions = append(versions, action.GetResource().Version)
+				}
+			}
+			if diff := cmp.Diff([]string{tt.expected}, versions); diff != "" {
+				t.Errorf("unexpected versions of widgets deleted (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeletableResources(t *testing.T) {
+	widgets := func(verbs ...string) []*metav1.APIResourceList {
+		return []*metav1.APIResourceList{{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: verbs}},
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		resources []*metav1.APIResourceList
+		expected  []schema.GroupVersionResource
+	}{
+		{
+			name: "mocked resources",
+			resources: append(testResources(),
+				&metav1.APIResourceList{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "namespaces", Kind: "Namespace", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+						{Name: "namespaces/finalize", Kind: "Namespace", Verbs: []string{"update", "delete"}},
+					},
+				},
+				&metav1.APIResourceList{
+					GroupVersion: "rbac.authorization.k8s.io/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "clusterroles", Kind: "ClusterRole", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+					},
+				},
+				&metav1.APIResourceList{
+					GroupVersion: "core.kcp.io/v1alpha1",
+					APIResources: []metav1.APIResource{
+						{Name: "logicalclusters", Kind: "LogicalCluster", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+					},
+				},
+				&metav1.APIResourceList{GroupVersion: "a/b/c"},
+			),
+			expected: []schema.GroupVersionResource{
+				{Version: "v1", Resource: "namespaces"},
+				{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+			},
+		},
+		{
+			name:      "list and deletecollection",
+			resources: widgets("list", "deletecollection"),
+			expected:  []schema.GroupVersionResource{{Group: "example.com", Version: "v1", Resource: "widgets"}},
+		},
+		{
+			name:      "delete without deletecollection",
+			resources: widgets("get", "list", "delete"),
+			expected:  []schema.GroupVersionResource{},
+		},
+		{
+			name:      "deletecollection without list",
+			resources: widgets("get", "delete", "deletecollection"),
+			expected:  []schema.GroupVersionResource{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.expected, DeletableResources(tt.resources)); diff != "" {
+				t.Errorf("unexpected deletable resources (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingSubresources(t *testing.T) {
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "apiextensions.k8s.io/v1",
+		APIResources: []metav1.APIResource{
+			// real discovery advertises subresources next to their parent.
+			{Name: "customresourcedefinitions/status", Kind: "CustomResourceDefinition", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+		},
+	})
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, action := range mockMetadataClient.Actions() {
+		if strings.Contains(action.GetResource().Resource, "/") {
+			t.Errorf("unexpected %s of subresource %s", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+	metaActionSet{
+		{"customresourcedefinitions", "delete-collection"},
+		{"customresourcedefinitions", "list"},
+	}.expectInOrder(t, mockMetadataClient.Actions())
+}
+
+func TestDeleteCollectionPerNamespaceFallback(t *testing.T) {
+	configMaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	items := map[string][]string{"ns1": {"a", "b"}, "ns2": {"c"}}
+
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "ConfigMap", "a", "ns1"),
+		newPartialObject("v1", "ConfigMap", "b", "ns1"),
+		newPartialObject("v1", "ConfigMap", "c", "ns2"),
+	)
+	var namespaces []string
+	mockMetadataClient.PrependReactor("delete-collection", "configmaps", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		namespaces = append(namespaces, action.GetNamespace())
+		if action.GetNamespace() == metav1.NamespaceAll {
+			return true, nil, errors.NewTimeoutError("too many configmaps", 0)
+		}
+		for _, name := range items[action.GetNamespace()] {
+			if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(configMaps, action.GetNamespace(), name); err != nil {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return NewResourceListBuilder().
+			Add("", "v1", "configmaps", "ConfigMap", true, "get", "list", "delete", "deletecollection").
+			Build(), nil
+	}, WithScope(NamespacedOnly))
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatalf("expected the configmaps to be deleted per namespace, got %v", err)
+	}
+	if diff := cmp.Diff([]string{metav1.NamespaceAll, "ns1", "ns2"}, namespaces); diff != "" {
+		t.Errorf("unexpected delete-collection namespaces (-want +got):\n%s", diff)
+	}
+}
+
+func TestDeleteCollectionTooLargeClusterScoped(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewTimeoutError("too many customresourcedefinitions", 0)
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err == nil || !strings.Contains(err.Error(), "too many customresourcedefinitions") {
+		t.Fatalf("expected the timeout of the delete-collection, got %v", err)
+	}
+	// cluster-scoped content is not listed to be chunked by namespace.
+	metaActionSet{
+		{"customresourcedefinitions", "delete-collection"},
+	}.expectInOrder(t, mockMetadataClient.Actions())
+}
+
+func TestEstimateCompletion(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	ws := newTerminatingLogicalCluster()
+
+	// first pass has nothing to compare against.
+	if _, ok := estimateCompletion(ws, 1000, start); ok {
+		t.Fatal("expected no estimate after the first pass")
+	}
+
+	// no progress yet.
+	if _, ok := estimateCompletion(ws, 1000, start.Add(10*time.Second)); ok {
+		t.Fatal("expected no estimate without progress")
+	}
+	if ws.Status.Deletion.EstimatedCompletion != nil {
+		t.Fatalf("expected no estimated completion without progress, got %v", ws.Status.Deletion.EstimatedCompletion)
+	}
+
+	// 100 objects every 10s from now on.
+	var eta time.Duration
+	remaining := 1000
+	for i := 2; i <= 6; i++ {
+		remaining -= 100
+		var ok bool
+		eta, ok = estimateCompletion(ws, remaining, start.Add(time.Duration(i)*10*time.Second))
+		if !ok {
+			t.Fatalf("expected an estimate in pass %d", i)
+		}
+	}
+	// the smoothed rate converges towards 10 objects/s, i.e. 50s for the 500 remaining objects.
+	if eta < 50*time.Second || eta > 55*time.Second {
+		t.Errorf("expected an estimate of about 50s, got %s", eta)
+	}
+	if expected := metav1.NewTime(start.Add(60 * time.Second).Add(eta)); !ws.Status.Deletion.EstimatedCompletion.Equal(&expected) {
+		t.Errorf("expected the estimated completion %v, got %v", expected, ws.Status.Deletion.EstimatedCompletion)
+	}
+
+	forgetEstimatedCompletion(ws)
+	if ws.Status.Deletion.EstimatedCompletion != nil {
+		t.Errorf("expected no estimated completion after forgetting it, got %v", ws.Status.Deletion.EstimatedCompletion)
+	}
+}
+
+func TestWorkspaceTerminatingETA(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	}}
+	var objects []runtime.Object
+	for i := 0; i < 4; i++ {
+		objects = append(objects, newPartialObject("example.com/v1", "Widget", fmt.Sprintf("w%d", i), ""))
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithClock(fakeClock))
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected remaining resources")
+	}
+	if msg := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted); strings.Contains(msg, "estimated") {
+		t.Errorf("expected no estimate after the first pass, got %q", msg)
+	}
+
+	// two of the widgets went away within a minute.
+	for _, name := range []string{"w0", "w1"} {
+		if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected remaining resources")
+	}
+	if msg := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted); !strings.HasSuffix(msg, "estimated 1m0s remaining") {
+		t.Errorf("expected an estimate of 1m0s, got %q", msg)
+	}
+}
+
+func TestWorkspaceTerminatingETASurvivesRequeue(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	}}
+	var objects []runtime.Object
+	for i := 0; i < 8; i++ {
+		objects = append(objects, newPartialObject("example.com/v1", "Widget", fmt.Sprintf("w%d", i), ""))
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakeClock(start)
+	newDeleter := func() WorkspaceResourcesDeleterInterface {
+		return NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+			return resources, nil
+		}, WithClock(fakeClock))
+	}
+	deleteWidgets := func(names ...string) {
+		for _, name := range names {
+			if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "", name); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ws := newTerminatingLogicalCluster()
+	d := newDeleter()
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected remaining resources")
+	}
+	deleteWidgets("w0", "w1")
+	fakeClock.SetTime(start.Add(time.Minute))
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected remaining resources")
+	}
+	// 2 widgets per minute leaves 3 minutes for the remaining 6.
+	if expected := metav1.NewTime(start.Add(4 * time.Minute)); !ws.Status.Deletion.EstimatedCompletion.Equal(&expected) {
+		t.Fatalf("expected the estimated completion %v, got %v", expected, ws.Status.Deletion.EstimatedCompletion)
+	}
+
+	// the controller restarts, and requeues the LogicalCluster as read from the server.
+	data, err := json.Marshal(ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws = &corev1alpha1.LogicalCluster{}
+	if err := json.Unmarshal(data, ws); err != nil {
+		t.Fatal(err)
+	}
+	d = newDeleter()
+
+	deleteWidgets("w2", "w3")
+	fakeClock.SetTime(start.Add(2 * time.Minute))
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected remaining resources")
+	}
+	if msg := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted); !strings.HasSuffix(msg, "estimated 2m0s remaining") {
+		t.Errorf("expected an estimate of 2m0s after the requeue, got %q", msg)
+	}
+	if expected := metav1.NewTime(start.Add(4 * time.Minute)); !ws.Status.Deletion.EstimatedCompletion.Equal(&expected) {
+		t.Errorf("expected the estimated completion %v after the requeue, got %v", expected, ws.Status.Deletion.EstimatedCompletion)
+	}
+}
+
+func TestWorkspaceTerminatingAllowlistExpiry(t *testing.T) {
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "rbac.authorization.k8s.io/v1",
+		APIResources: []metav1.APIResource{{Name: "clusterroles", Kind: "ClusterRole", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	}}
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		now             time.Time
+		expectedActions metaActionSet
+	}{
+		{
+			name: "before expiry",
+			now:  now,
+			expectedActions: []metaAction{
+				{"clusterroles", "delete-collection"},
+				{"clusterroles", "list"},
+			},
+		},
+		{
+			name: "after expiry",
+			now:  now.Add(2 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return resources, nil
+			},
+				WithClock(clocktesting.NewFakeClock(tt.now)),
+				WithAllowlist(Allowlist{
+					Resources: []schema.GroupResource{{Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}},
+					ExpiresAt: now.Add(time.Hour),
+				}),
+			)
+
+			if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+				t.Fatal(err)
+			}
+			tt.expectedActions.expectInOrder(t, mockMetadataClient.Actions())
+		})
+	}
+}
+
+func TestExplainFinalizerBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(lc *corev1alpha1.LogicalCluster)
+		expected string
+	}{
+		{
+			name:     "not deleting",
+			mutate:   func(lc *corev1alpha1.LogicalCluster) { lc.DeletionTimestamp = nil },
+			expected: "cannot remove finalizer: the logical cluster is not being deleted",
+		},
+		{
+			name:   "finalizer already removed",
+			mutate: func(lc *corev1alpha1.LogicalCluster) { lc.Finalizers = nil },
+		},
+		{
+			name:     "not started",
+			mutate:   func(lc *corev1alpha1.LogicalCluster) {},
+			expected: "cannot remove finalizer: content deletion has not started yet",
+		},
+		{
+			name: "content deleted",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkTrue(lc, tenancyv1alpha1.WorkspaceContentDeleted)
+			},
+		},
+		{
+			name: "resources remaining",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkFalse(lc, tenancyv1alpha1.WorkspaceContentDeleted, "SomeResourcesRemain", conditionsv1alpha1.ConditionSeverityInfo,
+					"Some content in the logical cluster has finalizers remaining: bar.example.com in 12 resource instances")
+			},
+			expected: "cannot remove finalizer: waiting for content to go away: Some content in the logical cluster has finalizers remaining: bar.example.com in 12 resource instances",
+		},
+		{
+			name: "quota",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkFalse(lc, tenancyv1alpha1.WorkspaceContentDeleted, "WaitingOnQuota", conditionsv1alpha1.ConditionSeverityError, "exceeded quota")
+			},
+			expected: "cannot remove finalizer: content deletion is blocked by a quota: exceeded quota",
+		},
+		{
+			name: "unknown reason without message",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkFalse(lc, tenancyv1alpha1.WorkspaceContentDeleted, "Unexpected", conditionsv1alpha1.ConditionSeverityError, "")
+			},
+			expected: "cannot remove finalizer: content deletion is blocked (Unexpected)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := newTerminatingLogicalCluster()
+			tt.mutate(lc)
+			if got := ExplainFinalizerBlock(lc); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeletionStatusOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutate   func(lc *corev1alpha1.LogicalCluster)
+		expected DeletionStatus
+	}{
+		{
+			name: "not deleting",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				lc.DeletionTimestamp = nil
+			},
+			expected: DeletionNotStarted,
+		},
+		{
+			name:     "no condition yet",
+			mutate:   func(lc *corev1alpha1.LogicalCluster) {},
+			expected: DeletionNotStarted,
+		},
+		{
+			name: "resources remaining",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkFalse(lc, tenancyv1alpha1.WorkspaceContentDeleted, "SomeResourcesRemain", conditionsv1alpha1.ConditionSeverityInfo, "")
+			},
+			expected: DeletionInProgress,
+		},
+		{
+			name: "interrupted",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkUnknown(lc, tenancyv1alpha1.WorkspaceContentDeleted, "DeletionInterrupted", "")
+			},
+			expected: DeletionInProgress,
+		},
+		{
+			name: "discovery failed",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkFalse(lc, tenancyv1alpha1.WorkspaceContentDeleted, "DiscoveryFailed", conditionsv1alpha1.ConditionSeverityError, "test error")
+			},
+			expected: DeletionFailed,
+		},
+		{
+			name: "protected resources remaining",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkFalse(lc, tenancyv1alpha1.WorkspaceContentDeleted, "ProtectedResourcesRemaining", conditionsv1alpha1.ConditionSeverityWarning, "")
+			},
+			expected: DeletionFailed,
+		},
+		{
+			name: "content deleted",
+			mutate: func(lc *corev1alpha1.LogicalCluster) {
+				conditions.MarkTrue(lc, tenancyv1alpha1.WorkspaceContentDeleted)
+			},
+			expected: DeletionCompleted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := newTerminatingLogicalCluster()
+			tt.mutate(lc)
+			if got := DeletionStatusOf(lc); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingWorkerCount(t *testing.T) {
+	var apiResources []metav1.APIResource
+	var objects []runtime.Object
+	for i := 0; i < 8; i++ {
+		resource := fmt.Sprintf("widget%ds", i)
+		apiResources = append(apiResources, metav1.APIResource{Name: resource, Kind: fmt.Sprintf("Widget%d", i), Verbs: []string{"get", "list", "delete", "deletecollection"}})
+		// every other resource keeps i instances
+		if i%2 == 1 {
+			for j := 0; j < i; j++ {
+				objects = append(objects, newPartialObject("example.com/v1", fmt.Sprintf("Widget%d", i), fmt.Sprintf("w%d", j), ""))
+			}
+		}
+	}
+	resources := []*metav1.APIResourceList{{GroupVersion: "example.com/v1", APIResources: apiResources}}
+
+	run := func(opts ...Option) (*corev1alpha1.LogicalCluster, error, int) {
+		fakeClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+		fakeClient.PrependReactor("delete-collection", "widget6s", func(action kcptesting.Action) (bool, runtime.Object, error) {
+			return true, nil, goerrors.New("widget6s failed")
+		})
+		d := NewWorkspacedResourcesDeleter(fakeClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+			return resources, nil
+		}, opts...)
+		ws := newTerminatingLogicalCluster()
+		err := d.Delete(context.TODO(), ws)
+		return ws, err, len(fakeClient.Actions())
+	}
+
+	serialWS, serialErr, serialActions := run()
+	parallelWS, parallelErr, parallelActions := run(WithWorkerCount(4))
+
+	if serialErr == nil || parallelErr == nil {
+		t.Fatalf("expected errors, got %v and %v", serialErr, parallelErr)
+	}
+	if serialErr.Error() != parallelErr.Error() {
+		t.Errorf("expected the same error as with a single worker:\n%v\ngot:\n%v", serialErr, parallelErr)
+	}
+	if !strings.Contains(parallelErr.Error(), "widget6s failed") {
+		t.Errorf("expected the failure of widget6s to be aggregated, got %v", parallelErr)
+	}
+	if serialActions != parallelActions {
+		t.Errorf("expected %d actions, got %d", serialActions, parallelActions)
+	}
+	expected := conditions.Get(serialWS, tenancyv1alpha1.WorkspaceContentDeleted)
+	got := conditions.Get(parallelWS, tenancyv1alpha1.WorkspaceContentDeleted)
+	if diff := cmp.Diff(expected.Message, got.Message); diff != "" {
+		t.Errorf("unexpected condition message (-serial +parallel):\n%s", diff)
+	}
+	for _, resource := range []string{"widget1s", "widget3s", "widget5s", "widget7s"} {
+		if !strings.Contains(got.Message, resource) {
+			t.Errorf("expected %s to be reported as remaining, got %q", resource, got.Message)
+		}
+	}
+}
+
+func TestWorkspaceTerminatingMetrics(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+			{Name: "gadgets", Kind: "Gadget", Verbs: []string{"get", "list", "delete"}},
+		},
+	}}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("example.com/v1", "Widget", "w1", ""),
+		newPartialObject("example.com/v1", "Widget", "w2", ""),
+	)
+	registry := compbasemetrics.NewKubeRegistry()
+	m := NewMetrics()
+	m.Register(registry)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithMetrics(m))
+
+	remaining := func() float64 {
+		t.Helper()
+		value, err := testutil.GetGaugeMetricValue(m.RemainingResources.WithLabelValues("root", widgets.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	counted := func(outcome string) float64 {
+		t.Helper()
+		value, err := testutil.GetCounterMetricValue(m.DeleteCollections.WithLabelValues(outcome))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected remaining resources")
+	}
+	if got := remaining(); got != 2 {
+		t.Errorf("expected 2 remaining widgets, got %v", got)
+	}
+	if got := counted(deleteCollectionSucceeded); got != 1 {
+		t.Errorf("expected 1 successful delete-collection, got %v", got)
+	}
+	if got := counted(deleteCollectionUnsupported); got != 1 {
+		t.Errorf("expected 1 unsupported delete-collection, got %v", got)
+	}
+
+	for _, name := range []string{"w1", "w2"} {
+		if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "", name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatal(err)
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Fatal("expected content to be deleted")
+	}
+	if got := remaining(); got != 0 {
+		t.Errorf("expected the remaining widgets to be reset, got %v", got)
+	}
+}
+
+func TestEstimateDeletion(t *testing.T) {
+	ws := newTerminatingLogicalCluster()
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", ""),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	estimates, err := d.EstimateDeletion(context.TODO(), ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DeletionEstimate{
+		{GVR: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, Count: 2},
+	}
+	if diff := cmp.Diff(expected, estimates); diff != "" {
+		t.Errorf("unexpected estimates (-want +got):\n%s", diff)
+	}
+	for _, action := range mockMetadataClient.Actions() {
+		if !action.Matches("list", action.GetResource().Resource) {
+			t.Errorf("expected only list actions, got %v", action)
+		}
+	}
+	if len(ws.Status.Conditions) != 0 {
+		t.Errorf("expected no conditions to be set, got %v", ws.Status.Conditions)
+	}
+}
+
+func TestWorkspaceTerminatingDeletionPriority(t *testing.T) {
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+			{Name: "gadgets", Kind: "Gadget", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+			{Name: "gizmos", Kind: "Gizmo", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+		},
+	}}
+	priorities := map[string]int{"gizmos": 10, "widgets": 5, "gadgets": 1}
+
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithDeletionPriority(func(gvr schema.GroupVersionResource) int {
+		return priorities[gvr.Resource]
+	}))
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatal(err)
+	}
+	metaActionSet{
+		{"gizmos", "delete-collection"},
+		{"gizmos", "list"},
+		{"widgets", "delete-collection"},
+		{"widgets", "list"},
+		{"gadgets", "delete-collection"},
+		{"gadgets", "list"},
+	}.expectInOrder(t, mockMetadataClient.Actions())
+}
+
+func TestWorkspaceTerminatingFailureMessage(t *testing.T) {
+	longError := strings.Repeat("x", 300)
+
+	tests := []struct {
+		name            string
+		discoveryErr    error
+		reactorErr      error
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "discovery failed",
+			discoveryErr:    goerrors.New("test error"),
+			expectedReason:  "DiscoveryFailed",
+			expectedMessage: "discovery failed: test error",
+		},
+		{
+			name: "partial discovery failed",
+			discoveryErr: &discovery.ErrGroupDiscoveryFailed{Groups: map[schema.GroupVersion]error{
+				{Group: "metrics.k8s.io", Version: "v1beta1"}: goerrors.New("service unavailable"),
+				{Group: "example.com", Version: "v1"}:         goerrors.New("timeout"),
+			}},
+			expectedReason:  "DiscoveryFailed",
+			expectedMessage: "discovery of example.com/v1 failed: timeout; discovery of metrics.k8s.io/v1beta1 failed: service unavailable",
+		},
+		{
+			name:            "deletion failed",
+			reactorErr:      goerrors.New("boom"),
+			expectedReason:  "ContentDeletionFailed",
+			expectedMessage: "deletion of customresourcedefinitions.apiextensions.k8s.io failed: boom",
+		},
+		{
+			name:            "discovery and deletion failed",
+			discoveryErr:    goerrors.New("test error"),
+			reactorErr:      goerrors.New("boom"),
+			expectedReason:  "ContentDeletionFailed",
+			expectedMessage: "discovery failed: test error; deletion of customresourcedefinitions.apiextensions.k8s.io failed: boom",
+		},
+		{
+			name:            "long error is truncated",
+			reactorErr:      goerrors.New(longError),
+			expectedReason:  "ContentDeletionFailed",
+			expectedMessage: "deletion of customresourcedefinitions.apiextensions.k8s.io failed: " + longError[:256] + "...",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			if tt.reactorErr != nil {
+				mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.reactorErr
+				})
+			}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), tt.discoveryErr
+			})
+
+			ws := newTerminatingLogicalCluster()
+			if err := d.Delete(context.TODO(), ws); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := conditions.GetReason(ws, tenancyv1alpha1.WorkspaceContentDeleted); got != tt.expectedReason {
+				t.Errorf("expected reason %q, got %q", tt.expectedReason, got)
+			}
+			if got := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted); got != tt.expectedMessage {
+				t.Errorf("expected message %q, got %q", tt.expectedMessage, got)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingPartialDiscovery(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(
+			schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, "", "crd1")
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), &discovery.ErrGroupDiscoveryFailed{Groups: map[schema.GroupVersion]error{
+			{Group: "example.com", Version: "v1"}: goerrors.New("timeout"),
+		}}
+	})
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.Delete(context.TODO(), ws); err == nil {
+		t.Fatal("expected an error while a group version cannot be discovered")
+	}
+	metaActionSet{
+		{"customresourcedefinitions", "delete-collection"},
+		{"customresourcedefinitions", "list"},
+	}.expectInOrder(t, mockMetadataClient.Actions())
+	if _, err := mockMetadataClient.Cluster(logicalcluster.NewPath("root")).Resource(schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}).Get(context.TODO(), "crd1", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected discoverable content to be deleted, got %v", err)
+	}
+	if conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Error("expected content deletion to be incomplete")
+	}
+	discovered := conditions.Get(ws, tenancyv1alpha1.WorkspaceResourceDiscoverySuccess)
+	if discovered == nil || discovered.Status != v1.ConditionFalse || discovered.Reason != "PartialDiscovery" {
+		t.Fatalf("expected partial discovery, got %v", discovered)
+	}
+	if expected := "Discovery failed for group versions example.com/v1"; discovered.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, discovered.Message)
+	}
+}
+
+func TestWorkspaceTerminatingContextCancelled(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ws := newTerminatingLogicalCluster()
+	err := d.Delete(ctx, ws)
+	if !goerrors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Error("expected content deletion not to succeed")
+	}
+	if !conditions.IsUnknown(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected content deletion to be unknown, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+	if actions := mockMetadataClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no actions, got %v", actions)
+	}
+}
+
+func TestCachedDiscovery(t *testing.T) {
+	var lock sync.Mutex
+	calls := map[logicalcluster.Path]int{}
+	failing := false
+	discover := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		calls[clusterName]++
+		if failing {
+			return nil, goerrors.New("discovery failed")
+		}
+		return testResources(), nil
+	}
+	called := func(clusterName logicalcluster.Path) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls[clusterName]
+	}
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Now())
+	cached := NewCachedDiscovery(discover, time.Minute)
+	cached.clock = fakeClock
+	root, other := logicalcluster.NewPath("root"), logicalcluster.NewPath("root:other")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cached.Discover(root); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if _, err := cached.Discover(other); err != nil {
+		t.Fatal(err)
+	}
+	if got := called(other); got != 1 {
+		t.Errorf("expected other logical clusters to be discovered separately, got %d calls", got)
+	}
+	before := called(root)
+
+	fakeClock.SetTime(fakeClock.Now().Add(30 * time.Second))
+	if _, err := cached.Discover(root); err != nil {
+		t.Fatal(err)
+	}
+	if got := called(root); got != before {
+		t.Errorf("expected the cached result within the TTL, got %d calls", got)
+	}
+
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	if _, err := cached.Discover(root); err != nil {
+		t.Fatal(err)
+	}
+	if got := called(root); got != before+1 {
+		t.Errorf("expected discovery after the TTL, got %d calls", got-before)
+	}
+
+	cached.Invalidate(root)
+	lock.Lock()
+	failing = true
+	lock.Unlock()
+	if _, err := cached.Discover(root); err == nil {
+		t.Error("expected discovery after invalidation to fail")
+	}
+	if _, err := cached.Discover(root); err == nil {
+		t.Error("expected failed discovery not to be cached")
+	}
+	if got := called(root); got != before+3 {
+		t.Errorf("expected 2 more discoveries, got %d", got-before-1)
+	}
+}
+
+func TestCachedDiscoveryInvalidateDuringDiscover(t *testing.T) {
+	root := logicalcluster.NewPath("root")
+	calls := 0
+	var cached *CachedDiscovery
+	cached = NewCachedDiscovery(func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		calls++
+		if calls == 1 {
+			// a CRD changes while the stale resources are being discovered.
+			cached.Invalidate(clusterName)
+		}
+		return testResources(), nil
+	}, time.Minute)
+
+	if _, err := cached.Discover(root); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.Discover(root); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the result of a discovery running during an invalidation not to be cached, got %d calls", calls)
+	}
+	if _, err := cached.Discover(root); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the result of the later discovery to be cached, got %d calls", calls)
+	}
+
+	cached.Forget(root)
+	if len(cached.entries) != 0 {
+		t.Errorf("expected no cached logical clusters after forgetting, got %d", len(cached.entries))
+	}
+}
+
+func TestWorkspaceTerminatingCRDRemoved(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	})
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com", ""),
+		newPartialObject("example.com/v1", "Widget", "w1", ""),
+		newPartialObject("example.com/v1", "Widget", "w2", ""),
+	)
+	// the CRD is removed concurrently, e.g. by its owner, while its instances are deleted.
+	crdGone := false
+	mockMetadataClient.PrependReactor("*", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if !crdGone {
+			crdGone = true
+			if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", "widgets.example.com"); err != nil {
+				return true, nil, err
+			}
+		}
+		return true, nil, &meta.NoResourceMatchError{PartialResource: action.GetResource()}
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected no remaining content, got %v", err)
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected content to be deleted, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+}
+
+func TestWorkspaceTerminatingCustomResourcesBeforeCRDs(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	})
+
+	t.Run("instances are deleted first", func(t *testing.T) {
+		mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+			newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com", ""),
+			newPartialObject("example.com/v1", "Widget", "w1", ""),
+		)
+		mockMetadataClient.PrependReactor("delete-collection", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+			return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "", "w1")
+		})
+		mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+			return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", "widgets.example.com")
+		})
+		// a higher priority must not move the CRDs before their instances.
+		d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+			return resources, nil
+		}, WithDeletionPriority(func(gvr schema.GroupVersionResource) int {
+			if gvr == crds {
+				return 1
+			}
+			return 0
+		}))
+
+		if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+			t.Fatal(err)
+		}
+		metaActionSet{
+			{"widgets", "delete-collection"},
+			{"widgets", "list"},
+			{"customresourcedefinitions", "delete-collection"},
+			{"customresourcedefinitions", "list"},
+		}.expectInOrder(t, mockMetadataClient.Actions())
+	})
+
+	t.Run("CRD deletion is deferred while instances remain", func(t *testing.T) {
+		now := metav1.Now()
+		stuck := newPartialObject("example.com/v1", "Widget", "w1", "")
+		stuck.DeletionTimestamp = &now
+		stuck.Finalizers = []string{"example.com/cleanup"}
+		mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+			newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "widgets.example.com", ""),
+			stuck,
+		)
+		mockMetadataClient.PrependReactor("delete-collection", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+			return true, nil, nil
+		})
+		d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+			return resources, nil
+		})
+
+		report, err := d.DeleteWithReport(context.TODO(), newTerminatingLogicalCluster())
+		var remaining *ResourcesRemainingError
+		if !goerrors.As(err, &remaining) {
+			t.Fatalf("expected content to remain, got %v", err)
+		}
+		for _, action := range mockMetadataClient.Actions() {
+			if action.Matches("delete-collection", "customresourcedefinitions") {
+				t.Fatalf("expected the CRD deletion to be deferred, got %v", action)
+			}
+		}
+		expected := []ResourceReport{
+			{GVR: crds, Found: -1, DeferredBy: []schema.GroupVersionResource{widgets}},
+			{GVR: widgets, Found: -1, DeleteCollectionIssued: true, Remaining: 1},
+		}
+		if diff := cmp.Diff(expected, report.Resources); diff != "" {
+			t.Fatalf("unexpected report (-want +got):\n%s", diff)
+		}
+		if got, expected := report.Resources[0].String(), "customresourcedefinitions.apiextensions.k8s.io: deferred until instances of widgets.example.com are gone"; got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	})
+}
+
+func TestWorkspaceTerminatingEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		existingObject []runtime.Object
+		reactorErr     error
+		expectedEvents []string
+	}{
+		{
+			name: "content deleted",
+			existingObject: []runtime.Object{
+				newPartialObject("v1", "Secret", "s1", "ns1"),
+			},
+			expectedEvents: []string{
+				"Normal ContentDeleted All content of the logical cluster has been deleted",
+			},
+		},
+		{
+			name: "content remaining",
+			existingObject: []runtime.Object{
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", ""),
+			},
+			expectedEvents: []string{
+				"Normal DeletingContent Waiting for 2 instances of customresourcedefinitions.apiextensions.k8s.io to be deleted",
+			},
+		},
+		{
+			name:       "deletion failed",
+			reactorErr: goerrors.New("boom"),
+			expectedEvents: []string{
+				"Warning DeletionFailed Failed to delete customresourcedefinitions.apiextensions.k8s.io: boom",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, tt.existingObject...)
+			if tt.reactorErr != nil {
+				mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.reactorErr
+				})
+			}
+			recorder := events.NewFakeRecorder(10)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, WithEventRecorder(recorder))
+
+			_ = d.Delete(context.TODO(), newTerminatingLogicalCluster())
+			close(recorder.Events)
+
+			var got []string
+			for event := range recorder.Events {
+				got = append(got, event)
+			}
+			if diff := cmp.Diff(tt.expectedEvents, got); diff != "" {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestResourcesRemainingErrorRetryAfter(t *testing.T) {
+	objects := []runtime.Object{}
+	var apiResources []metav1.APIResource
+	for i := 0; i < 3; i++ {
+		apiResources = append(apiResources, metav1.APIResource{Name: fmt.Sprintf("widget%ds", i), Kind: fmt.Sprintf("Widget%d", i), Verbs: []string{"get", "list", "delete", "deletecollection"}})
+		obj := newPartialObject("example.com/v1", fmt.Sprintf("Widget%d", i), "w", "")
+		if i > 0 {
+			obj.Finalizers = []string{"example.com/finalizer"}
+		}
+		objects = append(objects, obj)
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return []*metav1.APIResourceList{{GroupVersion: "example.com/v1", APIResources: apiResources}}, nil
+	})
+
+	err := d.Delete(context.TODO(), newTerminatingLogicalCluster())
+	var remaining *ResourcesRemainingError
+	if !goerrors.As(err, &remaining) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if remaining.EstimatedRemaining != 3 {
+		t.Errorf("expected 3 remaining instances, got %d", remaining.EstimatedRemaining)
+	}
+	// two resources wait for finalizers: 5s estimate doubled once.
+	if remaining.RetryAfter != 10*time.Second {
+		t.Errorf("expected a retry after 10s, got %s", remaining.RetryAfter)
+	}
+	if !strings.HasPrefix(remaining.Error(), "some content remains in the logical cluster, estimate 5 seconds before it is removed: ") {
+		t.Errorf("unexpected error message %q", remaining.Error())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		remaining contentRemaining
+		expected  time.Duration
+	}{
+		{name: "no finalizers", remaining: contentRemaining{estimate: 5}},
+		{name: "one resource", remaining: contentRemaining{estimate: 15, gvrsPendingFinalizers: 1}, expected: 15 * time.Second},
+		{name: "three resources", remaining: contentRemaining{estimate: 15, gvrsPendingFinalizers: 3}, expected: 60 * time.Second},
+		{name: "capped", remaining: contentRemaining{estimate: 15, gvrsPendingFinalizers: 100}, expected: maxRetryAfter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.remaining); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDeleteSelected(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := []*metav1.APIResourceList{{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	}}
+	ephemeral := newPartialObject("example.com/v1", "Widget", "ephemeral", "")
+	ephemeral.Labels = map[string]string{"lifecycle": "ephemeral"}
+	kept := newPartialObject("example.com/v1", "Widget", "kept", "")
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, ephemeral, kept)
+
+	var selectors []string
+	mockMetadataClient.PrependReactor("delete-collection", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		selector := action.(kcptesting.DeleteCollectionAction).GetListRestrictions().Labels
+		selectors = append(selectors, selector.String())
+		if selector.Matches(labels.Set(ephemeral.Labels)) {
+			return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "", "ephemeral")
+		}
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.DeleteSelected(context.TODO(), ws, labels.SelectorFromSet(labels.Set{"lifecycle": "ephemeral"})); err != nil {
+		t.Fatalf("expected no remaining selected content, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"lifecycle=ephemeral"}, selectors); diff != "" {
+		t.Errorf("unexpected delete-collection label selectors (-want +got):\n%s", diff)
+	}
+	if _, err := mockMetadataClient.Cluster(logicalcluster.NewPath("root")).Resource(widgets).Get(context.TODO(), "kept", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected unselected widget to remain, got %v", err)
+	}
+	if c := conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted); c != nil {
+		t.Errorf("expected no WorkspaceContentDeleted condition, got %v", c)
+	}
+}
+
+func TestDeleteSelectedNotTerminating(t *testing.T) {
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	resources := NewResourceListBuilder().
+		Add("", "v1", "configmaps", "ConfigMap", true, "get", "list", "delete", "deletecollection").
+		Build()
+	ephemeral := newPartialObject("v1", "ConfigMap", "ephemeral", "ns1")
+	ephemeral.Labels = map[string]string{"lifecycle": "ephemeral"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, ephemeral)
+	mockMetadataClient.PrependReactor("delete-collection", "configmaps", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(configmaps, "ns1", "ephemeral")
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	ws.DeletionTimestamp = nil
+	if err := d.DeleteSelected(context.TODO(), ws, labels.SelectorFromSet(labels.Set{"lifecycle": "ephemeral"})); err != nil {
+		t.Fatalf("expected no remaining selected content, got %v", err)
+	}
+	if _, err := mockMetadataClient.Cluster(logicalcluster.NewPath("root")).Resource(configmaps).Namespace("ns1").Get(context.TODO(), "ephemeral", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the selected namespaced configmap to be deleted, got %v", err)
+	}
+}
+
+func TestWorkspaceTerminatingListOptions(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	var fieldSelectors []string
+	mockMetadataClient.PrependReactor("delete-collection", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		fieldSelectors = append(fieldSelectors, action.GetResource().Resource+":"+action.(kcptesting.DeleteCollectionAction).GetListRestrictions().Fields.String())
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(AllScopes), WithListOptions(func(gvr schema.GroupVersionResource) metav1.ListOptions {
+		if gvr == secrets {
+			return metav1.ListOptions{FieldSelector: "type=kubernetes.io/tls"}
+		}
+		return metav1.ListOptions{}
+	}))
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatalf("expected no remaining content, got %v", err)
+	}
+	sort.Strings(fieldSelectors)
+	if diff := cmp.Diff([]string{"customresourcedefinitions:", "secrets:type=kubernetes.io/tls"}, fieldSelectors); diff != "" {
+		t.Errorf("unexpected delete-collection field selectors (-want +got):\n%s", diff)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	fakeClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	fakeClient.PrependReactor("delete-collection", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource() == crds {
+			return true, nil, errors.NewForbidden(crds.GroupResource(), "", goerrors.New("RBAC: access denied"))
+		}
+		return true, nil, nil
+	})
+	mockMetadataClient := &deleteOptionsRecorder{ClusterInterface: fakeClient}
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(AllScopes))
+
+	ws := newTerminatingLogicalCluster()
+	report, err := d.Preflight(context.TODO(), ws)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &PreflightReport{Resources: []PreflightResource{
+		{GVR: secrets, Permitted: []string{"deletecollection", "list"}},
+		{GVR: crds, Permitted: []string{"list"}, Denied: []string{"deletecollection"}},
+	}}
+	if diff := cmp.Diff(expected, report); diff != "" {
+		t.Errorf("unexpected preflight report (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]schema.GroupVersionResource{crds}, report.Denied()); diff != "" {
+		t.Errorf("unexpected denied resources (-want +got):\n%s", diff)
+	}
+	if len(mockMetadataClient.options) != 2 {
+		t.Errorf("expected 2 delete-collections, got %d", len(mockMetadataClient.options))
+	}
+	for _, opts := range mockMetadataClient.options {
+		if diff := cmp.Diff([]string{metav1.DryRunAll}, opts.DryRun); diff != "" {
+			t.Errorf("expected a dry-run delete-collection: %s", diff)
+		}
+	}
+	for _, action := range fakeClient.Actions() {
+		if action.GetVerb() == "delete" {
+			t.Errorf("unexpected delete of %s", action.GetResource())
+		}
+	}
+	if len(ws.Status.Conditions) != 0 {
+		t.Errorf("expected no conditions, got %v", ws.Status.Conditions)
+	}
+}
+
+func TestDeleteInNamespaces(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+
+	tests := []struct {
+		name              string
+		skipClusterScoped bool
+		expected          []string
+	}{
+		{
+			name:     "with cluster-scoped content",
+			expected: []string{"secrets/ns1", "customresourcedefinitions/"},
+		},
+		{
+			name:              "without cluster-scoped content",
+			skipClusterScoped: true,
+			expected:          []string{"secrets/ns1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := testResources()
+			resources[0].APIResources = append(resources[0].APIResources, metav1.APIResource{
+				Name: "namespaces", Kind: "Namespace", Verbs: []string{"get", "list", "delete", "deletecollection"},
+			})
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("v1", "Secret", "a", "ns1"),
+				newPartialObject("v1", "Secret", "b", "ns2"),
+				newPartialObject("v1", "Namespace", "ns1", ""),
+			)
+			mockMetadataClient.PrependReactor("delete-collection", "secrets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, action.GetNamespace(), "a")
+			})
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return resources, nil
+			})
+
+			ws := newTerminatingLogicalCluster()
+			if err := d.DeleteInNamespaces(context.TODO(), ws, []string{"ns1"}, tt.skipClusterScoped); err != nil {
+				t.Fatalf("expected no remaining content, got %v", err)
+			}
+			var deleteCollections []string
+			for _, action := range mockMetadataClient.Actions() {
+				if action.GetVerb() == "delete-collection" {
+					deleteCollections = append(deleteCollections, action.GetResource().Resource+"/"+action.GetNamespace())
+				}
+			}
+			if diff := cmp.Diff(tt.expected, deleteCollections); diff != "" {
+				t.Errorf("unexpected delete-collection calls (-want +got):\n%s", diff)
+			}
+			if _, err := mockMetadataClient.Cluster(logicalcluster.NewPath("root")).Resource(secrets).Namespace("ns2").Get(context.TODO(), "b", metav1.GetOptions{}); err != nil {
+				t.Errorf("expected the secret in ns2 to remain, got %v", err)
+			}
+			if c := conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted); c != nil {
+				t.Errorf("expected no WorkspaceContentDeleted condition, got %v", c)
+			}
+		})
+	}
+}
+
+func TestFinalizeWorkspace(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	if err := d.FinalizeWorkspace(context.TODO(), ws); err == nil {
+		t.Errorf("expected finalization to be refused before content deletion started")
+	}
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if err := d.FinalizeWorkspace(context.TODO(), ws); err == nil {
+		t.Errorf("expected finalization to be refused while resources remain")
+	}
+	if diff := cmp.Diff([]string{LogicalClusterDeletionFinalizer}, ws.Finalizers); diff != "" {
+		t.Errorf("unexpected finalizers (-want +got):\n%s", diff)
+	}
+
+	if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", "crd1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected no remaining content, got %v", err)
+	}
+	if err := d.FinalizeWorkspace(context.TODO(), ws); err != nil {
+		t.Fatalf("expected finalization to succeed, got %v", err)
+	}
+	if len(ws.Finalizers) != 0 {
+		t.Errorf("expected the deletion finalizer to be removed, got %v", ws.Finalizers)
+	}
+}
+
+func TestWorkspaceTerminatingOnContentDeleted(t *testing.T) {
+	tests := []struct {
+		name        string
+		callbackErr error
+	}{
+		{name: "succeeds"},
+		{name: "fails", callbackErr: goerrors.New("quota release failed")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			calls := 0
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, WithOnContentDeleted(func(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+				calls++
+				return tt.callbackErr
+			}))
+
+			ws := newTerminatingLogicalCluster()
+			err := d.Delete(context.TODO(), ws)
+			if tt.callbackErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.callbackErr != nil && !goerrors.Is(err, tt.callbackErr) {
+				t.Fatalf("expected the callback error, got %v", err)
+			}
+			if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+				t.Fatalf("expected %s to be True", tenancyv1alpha1.WorkspaceContentDeleted)
+			}
+
+			if err := d.Delete(context.TODO(), ws); err != nil {
+				t.Fatalf("unexpected error in the second pass: %v", err)
+			}
+			if calls != 1 {
+				t.Errorf("expected the callback to be called once, got %d", calls)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingContentDeletionStarted(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	started := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakeClock(started)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithClock(fakeClock))
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if expected := "2022-10-01T12:00:00Z"; ws.Annotations[ContentDeletionStartedAnnotationKey] != expected {
+		t.Errorf("expected start %q, got %q", expected, ws.Annotations[ContentDeletionStartedAnnotationKey])
+	}
+	if _, ok := ContentDeletionDuration(ws); ok {
+		t.Errorf("expected no duration before the content is deleted")
+	}
+
+	fakeClock.Step(time.Minute)
+	if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", "crd1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected no remaining content, got %v", err)
+	}
+	if expected := "2022-10-01T12:00:00Z"; ws.Annotations[ContentDeletionStartedAnnotationKey] != expected {
+		t.Errorf("expected start %q to be kept, got %q", expected, ws.Annotations[ContentDeletionStartedAnnotationKey])
+	}
+
+	// the condition is stamped with the real time, so pin it for a stable duration.
+	for i := range ws.Status.Conditions {
+		if ws.Status.Conditions[i].Type == tenancyv1alpha1.WorkspaceContentDeleted {
+			ws.Status.Conditions[i].LastTransitionTime = metav1.NewTime(started.Add(90 * time.Second))
+		}
+	}
+	if duration, ok := ContentDeletionDuration(ws); !ok || duration != 90*time.Second {
+		t.Errorf("expected a duration of 90s, got %v (%v)", duration, ok)
+	}
+}
+
+func TestWorkspaceTerminatingDeletionSummary(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", ""),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd3", ""),
+	)
+	// crd3 is only deleted in the second pass.
+	deletable := []string{"crd1", "crd2"}
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		for _, name := range deletable {
+			if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", name); err != nil && !errors.IsNotFound(err) {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithDeletionSummary())
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if expected := `{"customresourcedefinitions.apiextensions.k8s.io":2}`; ws.Annotations[DeletionSummaryAnnotationKey] != expected {
+		t.Errorf("expected summary %s, got %s", expected, ws.Annotations[DeletionSummaryAnnotationKey])
+	}
+
+	deletable = []string{"crd3"}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected no remaining content, got %v", err)
+	}
+	if expected := "Deleted 3 resource instances of 1 resources"; conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted) != expected {
+		t.Errorf("expected message %q, got %q", expected, conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+	if _, found := ws.Annotations[DeletionSummaryAnnotationKey]; found {
+		t.Errorf("expected the summary annotation to be removed")
+	}
+}
+
+func TestWorkspaceTerminatingOnDeleted(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("v1", "Secret", "s2", "ns1"),
+		newPartialObject("v1", "Secret", "s3", "ns2"),
+	)
+	// s2 waits for a finalizer in the first pass.
+	deletable := [][2]string{{"ns1", "s1"}, {"ns2", "s3"}}
+	mockMetadataClient.PrependReactor("delete-collection", "secrets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		for _, item := range deletable {
+			if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, item[0], item[1]); err != nil && !errors.IsNotFound(err) {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	var deleted []string
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(AllScopes), WithOnDeleted(func(gvr schema.GroupVersionResource, namespace string, count int) {
+		deleted = append(deleted, fmt.Sprintf("%s/%s=%d", gvr.Resource, namespace, count))
+	}))
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"secrets/ns1=1", "secrets/ns2=1"}, deleted); diff != "" {
+		t.Errorf("unexpected deleted counts: %s", diff)
+	}
+
+	deleted = nil
+	deletable = [][2]string{{"ns1", "s2"}}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected no remaining content, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"secrets/ns1=1"}, deleted); diff != "" {
+		t.Errorf("unexpected deleted counts: %s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingNotReadyForDeletion(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	discoveries := 0
+	ready := false
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		discoveries++
+		return testResources(), nil
+	}, WithIsDeletable(func(logicalCluster *corev1alpha1.LogicalCluster) (bool, string) {
+		if !ready {
+			return false, "logical cluster is initializing"
+		}
+		return true, ""
+	}))
+
+	ws := newTerminatingLogicalCluster()
+	var notReadyErr *NotReadyForDeletionError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &notReadyErr) {
+		t.Fatalf("expected NotReadyForDeletionError, got %v", err)
+	}
+	if discoveries != 0 {
+		t.Errorf("expected no discovery, got %d", discoveries)
+	}
+	if actions := mockMetadataClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no calls, got %v", actions)
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceNotReadyForDeletion) {
+		t.Fatalf("expected WorkspaceNotReadyForDeletion, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceNotReadyForDeletion))
+	}
+	if expected := "logical cluster is initializing"; conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceNotReadyForDeletion) != expected {
+		t.Errorf("expected message %q, got %q", expected, conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceNotReadyForDeletion))
+	}
+	if conditions.Has(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected no WorkspaceContentDeleted condition, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+
+	ready = true
+	if err := d.Delete(context.TODO(), ws); goerrors.As(err, &notReadyErr) {
+		t.Fatalf("expected content deletion to start, got %v", err)
+	}
+	if discoveries != 1 {
+		t.Errorf("expected discovery, got %d", discoveries)
+	}
+	if conditions.Has(ws, tenancyv1alpha1.WorkspaceNotReadyForDeletion) {
+		t.Errorf("expected WorkspaceNotReadyForDeletion to be removed")
+	}
+}
+
+type fakeDeletionLock struct {
+	err      error
+	acquired []logicalcluster.Name
+	released int
+}
+
+func (l *fakeDeletionLock) Acquire(ctx context.Context, clusterName logicalcluster.Name) (func(), error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	l.acquired = append(l.acquired, clusterName)
+	return func() { l.released++ }, nil
+}
+
+func TestWorkspaceTerminatingDeletionLock(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	discoveries := 0
+	lock := &fakeDeletionLock{err: goerrors.New("held by shard beta")}
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		discoveries++
+		return testResources(), nil
+	}, WithDeletionLock(lock))
+
+	ws := newTerminatingLogicalCluster()
+	original := ws.DeepCopy()
+	var lockedErr *DeletionLockedError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &lockedErr) {
+		t.Fatalf("expected DeletionLockedError, got %v", err)
+	}
+	if discoveries != 0 {
+		t.Errorf("expected no discovery, got %d", discoveries)
+	}
+	if actions := mockMetadataClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no calls, got %v", actions)
+	}
+	if diff := cmp.Diff(original, ws); diff != "" {
+		t.Errorf("expected the logical cluster to be unchanged: %s", diff)
+	}
+
+	lock.err = nil
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if diff := cmp.Diff([]logicalcluster.Name{logicalcluster.From(ws)}, lock.acquired); diff != "" {
+		t.Errorf("unexpected acquisitions: %s", diff)
+	}
+	if lock.released != 1 {
+		t.Errorf("expected the lock to be released once, got %d", lock.released)
+	}
+}
+
+func TestReapOrphanNamespaces(t *testing.T) {
+	namespace := func(name, ownerUID string) *metav1.PartialObjectMetadata {
+		ns := newPartialObject("v1", "Namespace", name, "")
+		ns.UID = types.UID(name + "-uid")
+		if ownerUID != "" {
+			ns.Labels = map[string]string{OwnerLogicalClusterUIDLabelKey: ownerUID}
+		}
+		return ns
+	}
+	tests := []struct {
+		name           string
+		logicalCluster bool
+		expected       []string
+	}{
+		{name: "logical cluster exists", logicalCluster: true, expected: []string{"orphan"}},
+		{name: "logical cluster is gone", expected: []string{"orphan", "valid"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{
+				namespace("orphan", "dead-uid"),
+				namespace("valid", "alive-uid"),
+				namespace("unlabeled", ""),
+			}
+			if tt.logicalCluster {
+				lc := newPartialObject("core.kcp.io/v1alpha1", "LogicalCluster", corev1alpha1.LogicalClusterName, "")
+				lc.UID = "alive-uid"
+				objects = append(objects, lc)
+			}
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+
+			reaped, err := ReapOrphanNamespaces(context.TODO(), mockMetadataClient, logicalcluster.Name("root"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.expected, reaped); diff != "" {
+				t.Errorf("unexpected reaped namespaces: %s", diff)
+			}
+			var deleted []string
+			for _, action := range mockMetadataClient.Actions() {
+				if action.GetVerb() == "delete" {
+					deleted = append(deleted, action.(kcptesting.DeleteAction).GetName())
+				}
+			}
+			if diff := cmp.Diff(tt.expected, deleted); diff != "" {
+				t.Errorf("unexpected deleted namespaces: %s", diff)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingMinAge(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	secret := func(name string, age time.Duration) *metav1.PartialObjectMetadata {
+		s := newPartialObject("v1", "Secret", name, "ns1")
+		s.CreationTimestamp = metav1.NewTime(fakeClock.Now().Add(-age))
+		return s
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		secret("old1", 40*24*time.Hour),
+		secret("old2", 31*24*time.Hour),
+		secret("new", 29*24*time.Hour),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(NamespacedOnly), WithClock(fakeClock), WithMinAge(30*24*time.Hour))
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if remainingErr.Retained != 1 {
+		t.Errorf("expected 1 retained instance, got %d", remainingErr.Retained)
+	}
+	if expected := int64(24 * 60 * 60); remainingErr.Estimate != expected {
+		t.Errorf("expected estimate %d, got %d", expected, remainingErr.Estimate)
+	}
+	var deleted []string
+	for _, action := range mockMetadataClient.Actions() {
+		switch action.GetVerb() {
+		case "delete":
+			deleted = append(deleted, action.(kcptesting.DeleteAction).GetName())
+		case "delete-collection":
+			t.Errorf("unexpected delete-collection of %s", action.GetResource().Resource)
+		}
+	}
+	if diff := cmp.Diff([]string{"old1", "old2"}, deleted); diff != "" {
+		t.Errorf("unexpected deleted secrets: %s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingShouldDeleteObject(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	retain := newPartialObject("v1", "Secret", "retain", "ns1")
+	retain.Annotations["retain"] = "true"
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		retain,
+		newPartialObject("v1", "Secret", "delete", "ns1"),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(NamespacedOnly), WithShouldDeleteObject(func(gvr schema.GroupVersionResource, obj *metav1.PartialObjectMetadata) bool {
+		return obj.Annotations["retain"] != "true"
+	}))
+
+	report, err := d.DeleteWithReport(context.TODO(), newTerminatingLogicalCluster())
+	if err != nil {
+		t.Fatalf("expected vetoed secrets not to keep the content deletion from completing, got %v", err)
+	}
+	var deleted []string
+	for _, action := range mockMetadataClient.Actions() {
+		switch action.GetVerb() {
+		case "delete":
+			deleted = append(deleted, action.(kcptesting.DeleteAction).GetName())
+		case "delete-collection":
+			t.Errorf("unexpected delete-collection of %s", action.GetResource().Resource)
+		}
+	}
+	if diff := cmp.Diff([]string{"delete"}, deleted); diff != "" {
+		t.Errorf("unexpected deleted secrets: %s", diff)
+	}
+	for _, rr := range report.Resources {
+		if rr.GVR != secrets {
+			continue
+		}
+		if rr.Vetoed != 1 || rr.Remaining != 0 {
+			t.Errorf("expected 1 vetoed and no remaining secrets, got %s", rr)
+		}
+	}
+}
+
+func TestWatchUntilDeleted(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	resources := NewResourceListBuilder().
+		Add("", "v1", "secrets", "Secret", true, "get", "list", "watch", "delete", "deletecollection").
+		Add("", "v1", "configmaps", "ConfigMap", true, "get", "list", "delete", "deletecollection").
+		Build()
+	s1 := newPartialObject("v1", "Secret", "s1", "ns1")
+	s2 := newPartialObject("v1", "Secret", "s2", "ns1")
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, s1, s2)
+	fakeWatch := watch.NewFake()
+	watching := make(chan struct{})
+	mockMetadataClient.PrependWatchReactor("secrets", func(action kcptesting.Action) (bool, watch.Interface, error) {
+		close(watching)
+		return true, fakeWatch, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithScope(NamespacedOnly))
+
+	go func() {
+		<-watching
+		fakeWatch.Delete(s1)
+		fakeWatch.Delete(s2)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), wait.ForeverTestTimeout)
+	defer cancel()
+	progress := make(chan DeletionProgress)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- d.WatchUntilDeleted(ctx, newTerminatingLogicalCluster(), progress)
+	}()
+
+	remaining := map[schema.GroupVersionResource][]int{}
+	for p := range progress {
+		remaining[p.GVR] = append(remaining[p.GVR], p.Remaining)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("expected the content to be deleted, got %v", err)
+	}
+	expected := map[schema.GroupVersionResource][]int{
+		secrets:    {2, 1, 0},
+		configmaps: {0},
+	}
+	if diff := cmp.Diff(expected, remaining); diff != "" {
+		t.Errorf("unexpected progress (-want +got):\n%s", diff)
+	}
+}
+
+func TestWaitUntilEmptyInNamespace(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("v1", "Secret", "s2", "ns2"),
+	)
+	fakeWatch := watch.NewFake()
+	var watchedNamespaces []string
+	mockMetadataClient.PrependWatchReactor("secrets", func(action kcptesting.Action) (bool, watch.Interface, error) {
+		watchedNamespaces = append(watchedNamespaces, action.GetNamespace())
+		go fakeWatch.Delete(newPartialObject("v1", "Secret", "s1", "ns1"))
+		return true, fakeWatch, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, nil).(*logicalClusterResourcesDeleter)
+	d.namespacedResources = map[schema.GroupVersionResource]bool{secrets: true}
+	d.namespace = "ns1"
+
+	ctx, cancel := context.WithTimeout(context.Background(), wait.ForeverTestTimeout)
+	defer cancel()
+	if err := d.waitUntilEmpty(ctx, logicalcluster.Name("root"), secrets, sets.NewString("list", "watch"), nil); err != nil {
+		t.Fatalf("expected the secrets in ns1 to be deleted, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"ns1"}, watchedNamespaces); diff != "" {
+		t.Errorf("expected the watch to be scoped like the list (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingAwaitingFinalizers(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	stuck := newPartialObject("v1", "Secret", "stuck", "ns1")
+	deletedAt := metav1.NewTime(fakeClock.Now().Add(-2 * time.Hour))
+	stuck.DeletionTimestamp = &deletedAt
+	stuck.Finalizers = []string{"example.com/cleanup"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, stuck)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(NamespacedOnly), WithClock(fakeClock), WithAwaitingFinalizersGrace(time.Hour))
+
+	ws := newTerminatingLogicalCluster()
+	report, err := d.DeleteWithReport(context.TODO(), ws)
+	var remainingErr *ResourcesRemainingError
+	if !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	for _, action := range mockMetadataClient.Actions() {
+		if action.GetVerb() == "delete" || action.GetVerb() == "delete-collection" {
+			t.Errorf("unexpected %s of %s awaiting finalizers", action.GetVerb(), action.GetResource().Resource)
+		}
+	}
+	for _, rr := range report.Resources {
+		if rr.GVR == secrets && (rr.Remaining != 1 || rr.AwaitingFinalizers != 1) {
+			t.Errorf("expected 1 remaining secret awaiting finalizers, got %s", rr)
+		}
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers) {
+		t.Fatalf("expected WorkspaceDeletionAwaitingFinalizers, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers))
+	}
+	if message := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers); !strings.Contains(message, "example.com/cleanup in 1 resource instances") {
+		t.Errorf("expected the message to name the finalizer, got %q", message)
+	}
+
+	if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, "ns1", "stuck"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected content deletion to complete, got %v", err)
+	}
+	if conditions.Has(ws, tenancyv1alpha1.WorkspaceDeletionAwaitingFinalizers) {
+		t.Errorf("expected WorkspaceDeletionAwaitingFinalizers to be removed")
+	}
+}
+
+func TestCancelDeletion(t *testing.T) {
+	newDeleter := func(objects ...runtime.Object) (*kcpfakemetadata.FakeMetadataClusterClientset, WorkspaceResourcesDeleterInterface) {
+		mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+		return mockMetadataClient, NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+			return testResources(), nil
+		}, WithDeletionOrder(func(gvr schema.GroupVersionResource) DeletionOrder {
+			return ListThenDelete
+		}))
+	}
+
+	t.Run("nothing deleted yet", func(t *testing.T) {
+		mockMetadataClient, d := newDeleter()
+		ws := newTerminatingLogicalCluster()
+		if err := d.Delete(context.TODO(), ws); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, found := ws.Annotations[DeletionIssuedAnnotationKey]; found {
+			t.Fatalf("expected no deletion to be recorded for empty content")
+		}
+		if err := d.CancelDeletion(context.TODO(), ws); err != nil {
+			t.Fatalf("expected the deletion to be cancellable, got %v", err)
+		}
+		if conditions.Has(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+			t.Errorf("expected WorkspaceContentDeleted to be cleared")
+		}
+
+		mockMetadataClient.ClearActions()
+		if err := d.Delete(context.TODO(), ws); !goerrors.Is(err, ErrDeletionCancelled) {
+			t.Errorf("expected ErrDeletionCancelled, got %v", err)
+		}
+		if actions := mockMetadataClient.Actions(); len(actions) != 0 {
+			t.Errorf("expected no calls after cancellation, got %d", len(actions))
+		}
+	})
+
+	t.Run("content deleted", func(t *testing.T) {
+		_, d := newDeleter(newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""))
+		ws := newTerminatingLogicalCluster()
+		var remainingErr *ResourcesRemainingError
+		if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+			t.Fatalf("expected ResourcesRemainingError, got %v", err)
+		}
+		if _, found := ws.Annotations[DeletionIssuedAnnotationKey]; !found {
+			t.Fatalf("expected the delete-collection to be recorded")
+		}
+		if err := d.CancelDeletion(context.TODO(), ws); !goerrors.Is(err, ErrDeletionNotCancellable) {
+			t.Errorf("expected ErrDeletionNotCancellable, got %v", err)
+		}
+		if _, found := ws.Annotations[DeletionCancelledAnnotationKey]; found {
+			t.Errorf("expected the deletion not to be cancelled")
+		}
+	})
+}
+
+func TestWorkspaceTerminatingVerbOverrides(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("v1", "Secret", "s2", "ns1"),
+	)
+	resources := testResources()
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithScope(NamespacedOnly), WithVerbOverrides(map[schema.GroupVersionResource][]string{
+		secrets: {"get", "list", "delete"},
+	}))
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var deleted []string
+	for _, action := range mockMetadataClient.Actions() {
+		switch action.GetVerb() {
+		case "delete":
+			deleted = append(deleted, action.(kcptesting.DeleteAction).GetName())
+		case "delete-collection":
+			t.Errorf("unexpected delete-collection of %s", action.GetResource().Resource)
+		}
+	}
+	sort.Strings(deleted)
+	if diff := cmp.Diff([]string{"s1", "s2"}, deleted); diff != "" {
+		t.Errorf("unexpected deleted secrets: %s", diff)
+	}
+	if verbs := resources[0].APIResources[0].Verbs; !sets.NewString(verbs...).Has("deletecollection") {
+		t.Errorf("expected the discovered resources not to be modified, got %v", verbs)
+	}
+}
+
+func TestDeleteWorkspaceRBAC(t *testing.T) {
+	resources := NewResourceListBuilder().
+		Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection").
+		Add("rbac.authorization.k8s.io", "v1", "roles", "Role", true, "get", "list", "delete", "deletecollection").
+		Add("rbac.authorization.k8s.io", "v1", "clusterroles", "ClusterRole", false, "get", "list", "delete", "deletecollection").
+		Build()
+	labeled := func(obj *metav1.PartialObjectMetadata) *metav1.PartialObjectMetadata {
+		obj.Labels = map[string]string{WorkspaceRBACLabelKey: "root"}
+		return obj
+	}
+	type object struct {
+		gvr             schema.GroupVersionResource
+		namespace, name string
+		labeled         bool
+	}
+	objects := []object{
+		{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, name: "tenant-admin", labeled: true},
+		{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "clusterroles"}, name: "shared"},
+		{gvr: schema.GroupVersionResource{Group: "rbac.authorization.k8s.io", Version: "v1", Resource: "roles"}, namespace: "kube-system", name: "tenant-reader", labeled: true},
+		{gvr: schema.GroupVersionResource{Version: "v1", Resource: "secrets"}, namespace: "ns1", name: "tenant-secret", labeled: true},
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		labeled(newPartialObject("rbac.authorization.k8s.io/v1", "ClusterRole", "tenant-admin", "")),
+		newPartialObject("rbac.authorization.k8s.io/v1", "ClusterRole", "shared", ""),
+		labeled(newPartialObject("rbac.authorization.k8s.io/v1", "Role", "tenant-reader", "kube-system")),
+		labeled(newPartialObject("v1", "Secret", "tenant-secret", "ns1")),
+	)
+	tracker := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root"))
+	// delete-collection removes the labeled objects of the resource, which the fake does not do.
+	mockMetadataClient.PrependReactor("delete-collection", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		selector := action.(kcptesting.DeleteCollectionAction).GetListRestrictions().Labels
+		for _, obj := range objects {
+			if obj.gvr == action.GetResource() && obj.labeled && selector.Matches(labels.Set{WorkspaceRBACLabelKey: "root"}) {
+				if err := tracker.Delete(obj.gvr, obj.namespace, obj.name); err != nil && !errors.IsNotFound(err) {
+					return true, nil, err
+				}
+			}
+		}
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	if err := d.DeleteWorkspaceRBAC(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, obj := range objects {
+		_, err := tracker.Get(obj.gvr, obj.namespace, obj.name)
+		removed := errors.IsNotFound(err)
+		if expected := obj.labeled && obj.gvr.Group == "rbac.authorization.k8s.io"; removed != expected {
+			t.Errorf("expected %s %s/%s to be removed %v, got %v", obj.gvr.Resource, obj.namespace, obj.name, expected, removed)
+		}
+	}
+	for _, action := range mockMetadataClient.Actions() {
+		if action.GetResource().Resource == "secrets" {
+			t.Errorf("unexpected %s of secrets", action.GetVerb())
+		}
+	}
+}
+
+func TestWorkspaceTerminatingSkipContentDeletion(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		t.Fatalf("unexpected discovery")
+		return nil, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	ws.Annotations = map[string]string{SkipContentDeletionAnnotationKey: "true"}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if actions := mockMetadataClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no metadata client actions, got %d", len(actions))
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Fatalf("expected WorkspaceContentDeleted to be True")
+	}
+	if reason := conditions.GetReason(ws, tenancyv1alpha1.WorkspaceContentDeleted); reason != "ContentDeletionSkipped" {
+		t.Errorf("expected reason ContentDeletionSkipped, got %q", reason)
+	}
+}
+
+func TestWorkspaceTerminatingStatusClient(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	ws := newTerminatingLogicalCluster()
+	statusClient := kcpfakeclient.NewSimpleClientset(ws.DeepCopy())
+	conflicts := 0
+	statusClient.PrependReactor("update", "logicalclusters", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "status" || conflicts > 0 {
+			return false, nil, nil
+		}
+		conflicts++
+		return true, nil, errors.NewConflict(corev1alpha1.Resource("logicalclusters"), ws.Name, fmt.Errorf("the object has been modified"))
+	})
+	fakeClock := clocktesting.NewFakeClock(time.Date(2022, 12, 1, 12, 0, 0, 0, time.UTC))
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithStatusClient(statusClient), WithClock(fakeClock))
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if conflicts != 1 {
+		t.Errorf("expected one conflicting status update, got %d", conflicts)
+	}
+
+	expected := &corev1alpha1.LogicalClusterDeletionStatus{
+		Phase:                 string(DeletionInProgress),
+		Remaining:             1,
+		LastProcessedResource: "customresourcedefinitions.apiextensions.k8s.io",
+		LastUpdated:           metav1.NewTime(fakeClock.Now()),
+	}
+	if diff := cmp.Diff(expected, ws.Status.Deletion); diff != "" {
+		t.Errorf("unexpected deletion status of the passed LogicalCluster (-want +got):\n%s", diff)
+	}
+	written, err := statusClient.Cluster(logicalcluster.NewPath("root")).CoreV1alpha1().LogicalClusters().Get(context.TODO(), ws.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(expected, written.Status.Deletion); diff != "" {
+		t.Errorf("unexpected written deletion status (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingSentinelErrors(t *testing.T) {
+	discoveryErr := fmt.Errorf("discovery timeout")
+	tests := []struct {
+		name            string
+		discoveryErr    error
+		expectSentinel  error
+		unexpectedError error
+	}{
+		{name: "resources remaining", expectSentinel: ErrResourcesRemaining, unexpectedError: ErrDiscoveryFailed},
+		{name: "discovery failed", discoveryErr: discoveryErr, expectSentinel: ErrDiscoveryFailed, unexpectedError: ErrResourcesRemaining},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+			)
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), tt.discoveryErr
+			})
+
+			err := d.Delete(context.TODO(), newTerminatingLogicalCluster())
+			if !matchErrors(err, tt.expectSentinel) {
+				t.Errorf("expected %v, got %v", tt.expectSentinel, err)
+			}
+			if matchErrors(err, tt.unexpectedError) {
+				t.Errorf("expected no %v, got %v", tt.unexpectedError, err)
+			}
+			if tt.discoveryErr != nil && !goerrors.Is(err, tt.discoveryErr) {
+				t.Errorf("expected the discovery error to be wrapped, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingMaxDeletionsPerPass(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	items := map[string][2]string{"secrets": {"ns1", "s1"}, "customresourcedefinitions": {"", "crd1"}}
+	mockMetadataClient.PrependReactor("delete-collection", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		item := items[action.GetResource().Resource]
+		if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(action.GetResource(), item[0], item[1]); err != nil && !errors.IsNotFound(err) {
+			return true, nil, err
+		}
+		return true, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(AllScopes), WithMaxDeletionsPerPass(1))
+
+	ws := newTerminatingLogicalCluster()
+	for pass, expected := range []string{"secrets", "customresourcedefinitions"} {
+		mockMetadataClient.ClearActions()
+		err := d.Delete(context.TODO(), ws)
+		var deleteCollections []string
+		for _, action := range mockMetadataClient.Actions() {
+			if action.GetVerb() == "delete-collection" {
+				deleteCollections = append(deleteCollections, action.GetResource().Resource)
+			}
+		}
+		if diff := cmp.Diff([]string{expected}, deleteCollections); diff != "" {
+			t.Errorf("pass %d: unexpected delete-collections: %s", pass, diff)
+		}
+		var remainingErr *ResourcesRemainingError
+		if pass == 0 && !goerrors.As(err, &remainingErr) {
+			t.Fatalf("pass %d: expected ResourcesRemainingError, got %v", pass, err)
+		}
+		if pass == 1 && err != nil {
+			t.Fatalf("pass %d: expected content deletion to complete, got %v", pass, err)
+		}
+	}
+}
+
+func TestWorkspaceTerminatingContention(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	for _, halt := range []bool{false, true} {
+		t.Run(fmt.Sprintf("halt=%v", halt), func(t *testing.T) {
+			// delete-collection does not remove anything, as if a controller recreated the secrets.
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("v1", "Secret", "s1", "ns1"),
+				newPartialObject("v1", "Secret", "s2", "ns1"),
+			)
+			previous := &DeletionReport{Resources: []ResourceReport{{GVR: secrets, Found: -1, Remaining: 1}}}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, WithScope(NamespacedOnly), WithContentionDetection(func(clusterName logicalcluster.Name) *DeletionReport {
+				return previous
+			}, halt))
+
+			ws := newTerminatingLogicalCluster()
+			report, err := d.DeleteWithReport(context.TODO(), ws)
+			var remainingErr *ResourcesRemainingError
+			if !goerrors.As(err, &remainingErr) {
+				t.Fatalf("expected ResourcesRemainingError, got %v", err)
+			}
+			if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionContention) {
+				t.Fatalf("expected WorkspaceDeletionContention, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionContention))
+			}
+			if message := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceDeletionContention); !strings.Contains(message, "Instances of secrets are recreated") {
+				t.Errorf("expected the message to name secrets, got %q", message)
+			}
+			if _, found := ws.Annotations[DeletionHaltedAnnotationKey]; found != halt {
+				t.Errorf("expected halted annotation %v, got %q", halt, ws.Annotations[DeletionHaltedAnnotationKey])
+			}
+
+			previous = report
+			mockMetadataClient.ClearActions()
+			err = d.Delete(context.TODO(), ws)
+			deletedSecrets := false
+			for _, action := range mockMetadataClient.Actions() {
+				if action.GetResource() == secrets && action.GetVerb() == "delete-collection" {
+					deletedSecrets = true
+				}
+			}
+			if deletedSecrets == halt {
+				t.Errorf("expected secrets to be deleted %v, got %v", !halt, deletedSecrets)
+			}
+			if !halt {
+				if conditions.Has(ws, tenancyv1alpha1.WorkspaceDeletionContention) {
+					t.Errorf("expected WorkspaceDeletionContention to be removed without growth")
+				}
+				return
+			}
+			if err == nil || goerrors.As(err, &remainingErr) {
+				t.Errorf("expected an error while halted resources remain, got %v", err)
+			}
+			if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionContention) {
+				t.Errorf("expected WorkspaceDeletionContention while halted, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionContention))
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingRecreationDetection(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	newSecret := func(uid types.UID) *metav1.PartialObjectMetadata {
+		secret := newPartialObject("v1", "Secret", "s1", "ns1")
+		secret.UID = uid
+		return secret
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, newSecret("uid-1"))
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(NamespacedOnly), WithRecreationDetection())
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if conditions.Has(ws, tenancyv1alpha1.WorkspaceDeletionContention) {
+		t.Fatalf("expected no WorkspaceDeletionContention before the secret is recreated, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionContention))
+	}
+
+	// a controller replaces the deleted secret with a new one of the same name.
+	tracker := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root"))
+	if err := tracker.Delete(secrets, "ns1", "s1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := tracker.Add(newSecret("uid-2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err := d.DeleteWithReport(context.TODO(), ws)
+	if !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	var recreated []string
+	for _, rr := range report.Resources {
+		if rr.GVR == secrets {
+			recreated = rr.Recreated
+		}
+	}
+	if diff := cmp.Diff([]string{"ns1/s1"}, recreated); diff != "" {
+		t.Errorf("unexpected recreated secrets (-want +got):\n%s", diff)
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionContention) {
+		t.Fatalf("expected WorkspaceDeletionContention, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionContention))
+	}
+	if message := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceDeletionContention); !strings.Contains(message, "Instances of secrets are recreated") {
+		t.Errorf("expected the message to name secrets, got %q", message)
+	}
+}
+
+func TestWorkspaceTerminatingRecreationForgotten(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, newPartialObject("v1", "Secret", "s1", "ns1"))
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(NamespacedOnly), WithRecreationDetection()).(*logicalClusterResourcesDeleter)
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if _, ok := d.recreation.clusters[logicalcluster.From(ws)]; !ok {
+		t.Fatalf("expected the UIDs of the logical cluster to be recorded")
+	}
+
+	// the content deletion ends without completing.
+	ws.Annotations[SkipContentDeletionAnnotationKey] = "true"
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := d.recreation.clusters[logicalcluster.From(ws)]; ok {
+		t.Errorf("expected the UIDs of the logical cluster to be forgotten")
+	}
+}
+
+func TestRecreationTrackerBounded(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	items := []metav1.PartialObjectMetadata{*newPartialObject("v1", "Secret", "s1", "ns1")}
+	tracker := newRecreationTracker()
+	tracker.observe("root:0", secrets, items)
+	tracker.observe("root:1", secrets, items)
+	// the first logical cluster is observed again, which makes the second one the least recently observed.
+	tracker.observe("root:0", secrets, items)
+	for i := 2; i <= maxRecreationTrackedClusters; i++ {
+		tracker.observe(logicalcluster.Name(fmt.Sprintf("root:%d", i)), secrets, items)
+	}
+	if got := len(tracker.clusters); got != maxRecreationTrackedClusters {
+		t.Errorf("expected %d tracked logical clusters, got %d", maxRecreationTrackedClusters, got)
+	}
+	if _, ok := tracker.clusters["root:1"]; ok {
+		t.Errorf("expected the least recently observed logical cluster to be dropped")
+	}
+	if _, ok := tracker.clusters["root:0"]; !ok {
+		t.Errorf("expected the logical cluster observed again to be kept")
+	}
+}
+
+func TestWorkspaceTerminatingContinueOnError(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	resources := NewResourceListBuilder().
+		Add("", "v1", "resourcequotas", "ResourceQuota", true, "get", "list", "delete", "deletecollection").
+		Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection").
+		Build()
+	for _, continueOnError := range []bool{false, true} {
+		t.Run(fmt.Sprintf("continueOnError=%v", continueOnError), func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("v1", "ResourceQuota", "q1", "ns1"),
+				newPartialObject("v1", "Secret", "s1", "ns1"),
+			)
+			mockMetadataClient.PrependReactor("delete-collection", "resourcequotas", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.NewBadRequest("quotas cannot be deleted")
+			})
+			mockMetadataClient.PrependReactor("delete-collection", "secrets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, "ns1", "s1")
+			})
+			opts := []Option{WithScope(NamespacedOnly)}
+			if continueOnError {
+				opts = append(opts, WithContinueOnError())
+			}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return resources, nil
+			}, opts...)
+
+			ws := newTerminatingLogicalCluster()
+			err := d.Delete(context.TODO(), ws)
+			if err == nil || !strings.Contains(err.Error(), "quotas cannot be deleted") {
+				t.Fatalf("expected the resourcequotas error, got %v", err)
+			}
+			deletedSecrets := false
+			for _, action := range mockMetadataClient.Actions() {
+				if action.GetResource() == secrets && action.GetVerb() == "delete-collection" {
+					deletedSecrets = true
+				}
+			}
+			if deletedSecrets != continueOnError {
+				t.Errorf("expected secrets to be deleted %v, got %v", continueOnError, deletedSecrets)
+			}
+			expectedReason := "ContentDeletionFailed"
+			if continueOnError {
+				expectedReason = "ContentDeletionPartiallyFailed"
+			}
+			if reason := conditions.GetReason(ws, tenancyv1alpha1.WorkspaceContentDeleted); reason != expectedReason {
+				t.Errorf("expected reason %s, got %q", expectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingDeletionCheckpoint(t *testing.T) {
+	configmaps := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := NewResourceListBuilder().
+		Add("", "v1", "configmaps", "ConfigMap", true, "get", "list", "delete", "deletecollection").
+		Add("example.com", "v1", "widgets", "Widget", true, "get", "list", "delete", "deletecollection").
+		Build()
+	// delete-collection does not remove anything, so the widget remains until it is deleted below.
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("example.com/v1", "Widget", "w1", "ns1"),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithScope(AllScopes), WithDeletionCheckpoint())
+	listedConfigMaps := func() bool {
+		for _, action := range mockMetadataClient.Actions() {
+			if action.GetResource() == configmaps && action.GetVerb() == "list" {
+				return true
+			}
+		}
+		return false
+	}
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if checkpoint := ws.Annotations[DeletionCheckpointAnnotationKey]; checkpoint != "configmaps" {
+		t.Fatalf("expected configmaps to be checkpointed, got %q", checkpoint)
+	}
+
+	mockMetadataClient.ClearActions()
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if listedConfigMaps() {
+		t.Errorf("expected checkpointed configmaps not to be listed again")
+	}
+
+	if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "ns1", "w1"); err != nil {
+		t.Fatal(err)
+	}
+	mockMetadataClient.ClearActions()
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError before verifying the checkpoint, got %v", err)
+	}
+	if message := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted); message != "Verifying 1 resources emptied by earlier passes" {
+		t.Errorf("unexpected message %q", message)
+	}
+	if _, found := ws.Annotations[DeletionCheckpointAnnotationKey]; found {
+		t.Errorf("expected the checkpoint to be cleared for verification, got %q", ws.Annotations[DeletionCheckpointAnnotationKey])
+	}
+
+	mockMetadataClient.ClearActions()
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected content deletion to complete, got %v", err)
+	}
+	if !listedConfigMaps() {
+		t.Errorf("expected configmaps to be verified")
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected content to be deleted, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+	if _, found := ws.Annotations[DeletionCheckpointAnnotationKey]; found {
+		t.Errorf("expected the checkpoint to be removed, got %q", ws.Annotations[DeletionCheckpointAnnotationKey])
+	}
+}
+
+func TestWorkspaceTerminatingTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("v1", "Secret", "s1", "ns1"),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithScope(AllScopes), WithTracerProvider(tracerProvider))
+
+	ws := newTerminatingLogicalCluster()
+	err := d.Delete(context.TODO(), ws)
+	var remainingErr *ResourcesRemainingError
+	if !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+
+	var pass *sdktrace.SpanSnapshot
+	byGVR := map[string]*sdktrace.SpanSnapshot{}
+	for _, span := range exporter.GetSpans() {
+		switch span.Name {
+		case "WorkspaceContentDeletion":
+			pass = span
+		case "DeleteCollection":
+			for _, kv := range span.Attributes {
+				if kv.Key == "gvr" {
+					byGVR[kv.Value.AsString()] = span
+				}
+			}
+		default:
+			t.Errorf("unexpected span %q", span.Name)
+		}
+	}
+	if pass == nil {
+		t.Fatal("expected a WorkspaceContentDeletion span")
+	}
+	if pass.StatusCode != codes.Error {
+		t.Errorf("expected the pass span to record the remaining resources, got status %v", pass.StatusCode)
+	}
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	for _, gvr := range []schema.GroupVersionResource{secrets, crds} {
+		span, ok := byGVR[gvr.String()]
+		if !ok {
+			t.Errorf("expected a DeleteCollection span for %s", gvr)
+			continue
+		}
+		if span.Parent.SpanID() != pass.SpanContext.SpanID() {
+			t.Errorf("expected the span of %s to be a child of the pass span", gvr)
+		}
+	}
+	if span, ok := byGVR[secrets.String()]; ok {
+		attrs := map[string]int64{}
+		for _, kv := range span.Attributes {
+			if kv.Key == "found" || kv.Key == "remaining" {
+				attrs[string(kv.Key)] = kv.Value.AsInt64()
+			}
+		}
+		if diff := cmp.Diff(map[string]int64{"found": -1, "remaining": 1}, attrs); diff != "" {
+			t.Errorf("unexpected secrets span attributes (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestWorkspaceTerminatingProtectedResources(t *testing.T) {
+	nodelete := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "nodeletes"}
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "nodeletes", Kind: "Nodelete", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	})
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("example.com/v1", "Nodelete", "billing", ""),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}, WithShouldDelete(func(gvr schema.GroupVersionResource) bool {
+		return gvr != nodelete
+	}))
+
+	ws := newTerminatingLogicalCluster()
+	err := d.Delete(context.TODO(), ws)
+	if err == nil {
+		t.Fatal("expected an error while protected resources remain")
+	}
+	var remainingErr *ResourcesRemainingError
+	if goerrors.As(err, &remainingErr) {
+		t.Errorf("expected no ResourcesRemainingError, got %v", err)
+	}
+	if reason := conditions.GetReason(ws, tenancyv1alpha1.WorkspaceContentDeleted); reason != "ProtectedResourcesRemaining" {
+		t.Errorf("expected reason ProtectedResourcesRemaining, got %q", reason)
+	}
+	for _, action := range mockMetadataClient.Actions() {
+		if action.GetResource() == nodelete && action.GetVerb() != "list" {
+			t.Errorf("unexpected %s of protected resource", action.GetVerb())
+		}
+	}
+
+	if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(nodelete, "", "billing"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("expected content deletion to complete, got %v", err)
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected content to be deleted, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+}
+
+func TestWorkspaceTerminatingResourceTimings(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	fakeClock := clocktesting.NewFakeClock(time.Now())
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		fakeClock.Step(3 * time.Second)
+		return false, nil, nil
+	})
+	timings := NewResourceTimings()
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithClock(fakeClock), WithResourceTimings(timings), WithSlowResourceThreshold(time.Second))
+
+	ws := newTerminatingLogicalCluster()
+	for i := 0; i < 2; i++ {
+		if err := d.Delete(context.TODO(), ws); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	durations := timings.Durations(logicalcluster.Name("root"))
+	if got := durations[crds]; got != 6*time.Second {
+		t.Errorf("expected 6s accumulated for %s, got %s", crds, got)
+	}
+	if got, ok := durations[secrets]; got != 0 || ok {
+		t.Errorf("expected no duration for the namespaced %s, got %s", secrets, got)
+	}
+	if diff := cmp.Diff([]ResourceTiming{{GVR: crds, Duration: 6 * time.Second}}, timings.Slowest(logicalcluster.Name("root"), 1)); diff != "" {
+		t.Errorf("unexpected slowest resources (-want +got):\n%s", diff)
+	}
+
+	timings.Forget(logicalcluster.Name("root"))
+	if durations := timings.Durations(logicalcluster.Name("root")); len(durations) != 0 {
+		t.Errorf("expected no durations after forgetting, got %v", durations)
+	}
+}
+
+func TestWorkspaceTerminatingDeleteCollectionUnsupported(t *testing.T) {
+	tests := []struct {
+		name                    string
+		verbs                   []string
+		deleteCollectionErr     error
+		metadataClientActionSet metaActionSet
+	}{
+		{
+			name:  "deletecollection not discovered",
+			verbs: []string{"get", "list", "delete"},
+			metadataClientActionSet: []metaAction{
+				{"widgets", "list"},
+				{"widgets", "delete"},
+				{"widgets", "delete"},
+				{"widgets", "list"},
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+			},
+		},
+		{
+			name:                "deletecollection rejected by the server",
+			verbs:               []string{"get", "list", "delete", "deletecollection"},
+			deleteCollectionErr: errors.NewMethodNotSupported(schema.GroupResource{Group: "example.com", Resource: "widgets"}, "deletecollection"),
+			metadataClientActionSet: []metaAction{
+				{"widgets", "delete-collection"},
+				{"widgets", "list"},
+				{"widgets", "delete"},
+				{"widgets", "delete"},
+				{"widgets", "list"},
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources := append(testResources(), &metav1.APIResourceList{
+				GroupVersion: "example.com/v1",
+				APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: tt.verbs}},
+			})
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("example.com/v1", "Widget", "w1", ""),
+				newPartialObject("example.com/v1", "Widget", "w2", ""),
+			)
+			if tt.deleteCollectionErr != nil {
+				mockMetadataClient.PrependReactor("delete-collection", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.deleteCollectionErr
+				})
+			}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return resources, nil
+			})
+
+			ws := newTerminatingLogicalCluster()
+			if err := d.Delete(context.TODO(), ws); err != nil {
+				t.Fatalf("expected no remaining content, got %v", err)
+			}
+			tt.metadataClientActionSet.expectInOrder(t, mockMetadataClient.Actions())
+		})
+	}
+}
+
+func TestWorkspaceTerminatingUnavailableAPI(t *testing.T) {
+	metrics := schema.GroupVersionResource{Group: "metrics.example.com", Version: "v1beta1", Resource: "samples"}
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "metrics.example.com/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "samples", Kind: "Sample", Verbs: []string{"get", "list", "delete"}}},
+	})
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	mockMetadataClient.PrependReactor("list", "samples", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewServiceUnavailable("the aggregated API server is down")
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	err := d.Delete(context.TODO(), ws)
+	var remainingErr *ResourcesRemainingError
+	if !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected a ResourcesRemainingError, got %v", err)
+	}
+	if diff := cmp.Diff([]schema.GroupVersionResource{metrics}, remainingErr.UnavailableResources); diff != "" {
+		t.Errorf("unexpected unavailable resources (-want +got):\n%s", diff)
+	}
+	if remainingErr.Estimate <= 0 {
+		t.Errorf("expected a positive estimate to retry, got %d", remainingErr.Estimate)
+	}
+	cond := conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted)
+	if cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != "SomeResourcesRemain" {
+		t.Fatalf("expected content to remain, got %v", cond)
+	}
+	if expected := "Some APIs are temporarily unavailable: samples.metrics.example.com"; cond.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, cond.Message)
+	}
+}
+
+func TestWorkspaceTerminatingForbidden(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}}},
+	})
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+		newPartialObject("example.com/v1", "Widget", "w1", ""),
+	)
+	permitted := false
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if !permitted {
+			return true, nil, errors.NewForbidden(crds.GroupResource(), "", goerrors.New("RBAC: access denied"))
+		}
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", "crd1")
+	})
+	mockMetadataClient.PrependReactor("delete-collection", "widgets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(widgets, "", "w1")
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if diff := cmp.Diff([]schema.GroupVersionResource{crds}, remainingErr.ForbiddenResources); diff != "" {
+		t.Errorf("unexpected forbidden resources (-want +got):\n%s", diff)
+	}
+	if _, err := mockMetadataClient.Cluster(logicalcluster.NewPath("root")).Resource(widgets).Get(context.TODO(), "w1", metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the widget to be deleted, got %v", err)
+	}
+
+	forbidden := conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionForbidden)
+	if forbidden == nil || forbidden.Status != v1.ConditionTrue {
+		t.Fatalf("expected %s to be True, got %v", tenancyv1alpha1.WorkspaceDeletionForbidden, forbidden)
+	}
+	if expected := "Not permitted to delete customresourcedefinitions.apiextensions.k8s.io"; forbidden.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, forbidden.Message)
+	}
+	if cond := conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted); cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != "DeletionForbidden" {
+		t.Errorf("expected content deletion to be forbidden, got %v", cond)
+	}
+
+	t.Log("the condition is removed once the permissions are granted")
+	permitted = true
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conditions.Has(ws, tenancyv1alpha1.WorkspaceDeletionForbidden) {
+		t.Errorf("expected %s to be removed", tenancyv1alpha1.WorkspaceDeletionForbidden)
+	}
+}
+
+func TestWorkspaceTerminatingTransientListFailure(t *testing.T) {
+	crds := schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}
+	tests := []struct {
+		name      string
+		listErr   error
+		wantErr   bool
+		wantLists int
+	}{
+		{name: "transient", listErr: errors.NewInternalError(goerrors.New("etcd leader changed")), wantLists: 2},
+		{name: "permanent", listErr: errors.NewForbidden(crds, "", goerrors.New("RBAC: access denied")), wantErr: true, wantLists: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+			lists := 0
+			mockMetadataClient.PrependReactor("list", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				lists++
+				if lists == 1 {
+					return true, nil, tt.listErr
+				}
+				return false, nil, nil
+			})
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			})
+
+			err := d.Delete(context.TODO(), newTerminatingLogicalCluster())
+			if tt.wantErr && err == nil {
+				t.Errorf("expected an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if lists != tt.wantLists {
+				t.Errorf("expected %d list calls, got %d", tt.wantLists, lists)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingMaxDeletionAttempts(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+	)
+	mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewInternalError(goerrors.New("etcd is down"))
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithMaxDeletionAttempts(3))
+
+	ws := newTerminatingLogicalCluster()
+	for pass := 1; pass <= 3; pass++ {
+		err := d.Delete(context.TODO(), ws)
+		if got, expected := ws.Annotations[DeletionAttemptsAnnotationKey], strconv.Itoa(pass); got != expected {
+			t.Errorf("pass %d: expected %d attempts, got %q", pass, pass, got)
+		}
+		if err == nil || !strings.Contains(err.Error(), "etcd is down") {
+			t.Errorf("pass %d: expected the failure to be reported, got %v", pass, err)
+		}
+		var exceeded *DeletionAttemptsExceededError
+		if terminal := goerrors.As(err, &exceeded); terminal != (pass == 3) {
+			t.Errorf("pass %d: expected terminal error %v, got %v", pass, pass == 3, err)
+		}
+		if terminal := conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionFailedTerminal); terminal != (pass == 3) {
+			t.Errorf("pass %d: expected terminal condition %v, got %v", pass, pass == 3, conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionFailedTerminal))
+		}
+	}
+	if reason := conditions.GetReason(ws, tenancyv1alpha1.WorkspaceDeletionFailedTerminal); reason != "MaxAttemptsExceeded" {
+		t.Errorf("expected reason MaxAttemptsExceeded, got %q", reason)
+	}
+
+	// no more attempts once the maximum is reached.
+	mockMetadataClient.ClearActions()
+	var exceeded *DeletionAttemptsExceededError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &exceeded) {
+		t.Errorf("expected the deletion to have failed terminally, got %v", err)
+	}
+	if actions := mockMetadataClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no content deletion after the maximum attempts, got %v", actions)
+	}
+
+	// removing the annotation resets the count.
+	delete(ws.Annotations, DeletionAttemptsAnnotationKey)
+	if err := d.Delete(context.TODO(), ws); goerrors.As(err, &exceeded) {
+		t.Errorf("expected the deletion to be attempted again, got %v", err)
+	}
+	if len(mockMetadataClient.Actions()) == 0 {
+		t.Error("expected the content deletion to resume")
+	}
+	if c := conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionFailedTerminal); c != nil {
+		t.Errorf("expected no terminal condition after the reset, got %v", c)
+	}
+}
+
+func TestWorkspaceTerminatingMaxDeletionAttemptsRemainingContent(t *testing.T) {
+	crd := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", "")
+	crd.Finalizers = []string{"example.com/finalizer"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, crd)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithMaxDeletionAttempts(3))
+
+	// a healthy wait for finalizers takes more passes than the maximum attempts.
+	ws := newTerminatingLogicalCluster()
+	for pass := 1; pass <= 10; pass++ {
+		err := d.Delete(context.TODO(), ws)
+		var remainingErr *ResourcesRemainingError
+		if !goerrors.As(err, &remainingErr) {
+			t.Fatalf("pass %d: expected ResourcesRemainingError, got %v", pass, err)
+		}
+		var exceeded *DeletionAttemptsExceededError
+		if goerrors.As(err, &exceeded) {
+			t.Fatalf("pass %d: expected remaining content not to count as a failed attempt, got %v", pass, err)
+		}
+	}
+	if attempts, ok := ws.Annotations[DeletionAttemptsAnnotationKey]; ok {
+		t.Errorf("expected no attempts to be counted, got %q", attempts)
+	}
+	if c := conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionFailedTerminal); c != nil {
+		t.Errorf("expected no terminal condition, got %v", c)
+	}
+}
+
+func TestDiffReports(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	report := func(remaining map[schema.GroupVersionResource]int) DeletionReport {
+		var r DeletionReport
+		for gvr, n := range remaining {
+			r.Resources = append(r.Resources, ResourceReport{GVR: gvr, Found: -1, Remaining: n})
+		}
+		return r
+	}
+	tests := []struct {
+		name       string
+		old, new   DeletionReport
+		expected   ReportDiff
+		growing    []ResourceDiff
+		expectSame bool
+	}{
+		{
+			name:       "unchanged",
+			old:        report(map[schema.GroupVersionResource]int{secrets: 2}),
+			new:        report(map[schema.GroupVersionResource]int{secrets: 2}),
+			expectSame: true,
+		},
+		{
+			name:     "added",
+			old:      report(map[schema.GroupVersionResource]int{secrets: 2, crds: 0}),
+			new:      report(map[schema.GroupVersionResource]int{secrets: 2, crds: 3}),
+			expected: ReportDiff{Added: []ResourceDiff{{GVR: crds, New: 3}}},
+			growing:  []ResourceDiff{{GVR: crds, New: 3}},
+		},
+		{
+			name:     "removed",
+			old:      report(map[schema.GroupVersionResource]int{secrets: 2, crds: 3}),
+			new:      report(map[schema.GroupVersionResource]int{crds: 3}),
+			expected: ReportDiff{Removed: []ResourceDiff{{GVR: secrets, Old: 2}}},
+		},
+		{
+			name:     "changed",
+			old:      report(map[schema.GroupVersionResource]int{secrets: 2, crds: 3}),
+			new:      report(map[schema.GroupVersionResource]int{secrets: 5, crds: 1}),
+			expected: ReportDiff{Changed: []ResourceDiff{{GVR: secrets, Old: 2, New: 5}, {GVR: crds, Old: 3, New: 1}}},
+			growing:  []ResourceDiff{{GVR: secrets, Old: 2, New: 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffReports(tt.old, tt.new)
+			if d := cmp.Diff(tt.expected, diff); d != "" {
+				t.Errorf("unexpected diff: %s", d)
+			}
+			if d := cmp.Diff(tt.growing, diff.Growing()); d != "" {
+				t.Errorf("unexpected growing resources: %s", d)
+			}
+			if diff.Empty() != tt.expectSame {
+				t.Errorf("expected Empty() to be %v", tt.expectSame)
+			}
+		})
+	}
+}
+
+func TestDeleteWithReport(t *testing.T) {
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete"}}},
+	})
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("example.com/v1", "Widget", "w1", ""),
+		newPartialObject("example.com/v1", "Widget", "w2", ""),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	})
+
+	report, err := d.DeleteWithReport(context.TODO(), newTerminatingLogicalCluster())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ResourceReport{
+		{GVR: schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}, Found: -1, DeleteCollectionIssued: true},
+		{GVR: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}, Found: 2},
+	}
+	if diff := cmp.Diff(expected, report.Resources); diff != "" {
+		t.Fatalf("unexpected report (-want +got):\n%s", diff)
+	}
+
+	// the report must match what was sent to the server.
+	for _, rr := range report.Resources {
+		deleteCollections, deletes := 0, 0
+		for _, action := range mockMetadataClient.Actions() {
+			if action.GetResource() != rr.GVR {
+				continue
+			}
+			switch action.GetVerb() {
+			case "delete-collection":
+				deleteCollections++
+			case "delete":
+				deletes++
+			}
+		}
+		if rr.DeleteCollectionIssued != (deleteCollections > 0) {
+			t.Errorf("%s: report says delete-collection issued %v, but %d were sent", rr.GVR, rr.DeleteCollectionIssued, deleteCollections)
+		}
+		if rr.Found >= 0 && rr.Found != deletes {
+			t.Errorf("%s: report says %d found, but %d were deleted", rr.GVR, rr.Found, deletes)
+		}
+	}
+
+	expectedSummary := "customresourcedefinitions.apiextensions.k8s.io: not listed, delete-collection issued, 0 remaining\n" +
+		"widgets.example.com: 2 found, no delete-collection, 0 remaining"
+	if got := report.String(); got != expectedSummary {
+		t.Errorf("expected summary %q, got %q", expectedSummary, got)
+	}
+}
+
+func TestWorkspaceTerminatingGracePeriod(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	now := metav1.Now()
+
+	tests := []struct {
+		name          string
+		gracePeriod   time.Duration
+		cancel        bool
+		wantRemaining bool
+		wantErr       bool
+		wantLists     int
+	}{
+		{name: "no grace period", wantRemaining: true, wantLists: 1},
+		{name: "finalized within the grace period", gracePeriod: time.Hour, wantLists: 2},
+		{name: "cancelled while waiting", gracePeriod: time.Hour, cancel: true, wantErr: true, wantLists: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crd := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", "")
+			crd.DeletionTimestamp = &now
+			crd.Finalizers = []string{"example.com/cleanup"}
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, crd)
+			fakeClock := clocktesting.NewFakeClock(time.Now())
+			lists := 0
+			mockMetadataClient.PrependReactor("list", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				lists++
+				if lists == 2 {
+					// the finalizer is done by the time of the second list.
+					if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(crds, "", "crd1"); err != nil {
+						return true, nil, err
+					}
+				}
+				return false, nil, nil
+			})
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, WithGracePeriod(tt.gracePeriod), WithClock(fakeClock))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go func() {
+				// end the grace period as soon as the deleter waits for it.
+				for !fakeClock.HasWaiters() {
+					if ctx.Err() != nil {
+						return
+					}
+					time.Sleep(time.Millisecond)
+				}
+				if tt.cancel {
+					cancel()
+				} else {
+					fakeClock.Step(tt.gracePeriod)
+				}
+			}()
+			err := d.Delete(ctx, newTerminatingLogicalCluster())
+			cancel()
+			var remainingErr *ResourcesRemainingError
+			if got := goerrors.As(err, &remainingErr); got != tt.wantRemaining {
+				t.Errorf("expected ResourcesRemainingError %v, got %v", tt.wantRemaining, err)
+			}
+			if !tt.wantRemaining && (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if lists != tt.wantLists {
+				t.Errorf("expected %d lists, got %d", tt.wantLists, lists)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingListers(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	secret := newPartialObject("v1", "Secret", "s1", "ns1")
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, secret)
+	mockMetadataClient.PrependReactor("delete-collection", "secrets", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		return true, nil, mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root")).Delete(secrets, "ns1", "s1")
+	})
+	// the informer cache has not observed the deletion yet.
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(secret.DeepCopy()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return NewResourceListBuilder().
+			Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection").
+			Build(), nil
+	}, WithScope(AllScopes), WithListers(func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) cache.GenericLister {
+		if gvr != secrets {
+			return nil
+		}
+		return cache.NewGenericLister(indexer, gvr.GroupResource())
+	}))
+	numLists := func() int {
+		n := 0
+		for _, action := range mockMetadataClient.Actions() {
+			if action.GetResource() == secrets && action.GetVerb() == "list" {
+				n++
+			}
+		}
+		return n
+	}
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError for the cached secret, got %v", err)
+	}
+	if n := numLists(); n != 0 {
+		t.Errorf("expected the remaining secrets to be counted from the cache, got %d live lists", n)
+	}
+
+	if err := indexer.Delete(secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mockMetadataClient.ClearActions()
+	if err := d.Delete(context.TODO(), ws); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := numLists(); n != 1 {
+		t.Errorf("expected a single live list confirming the cache, got %d", n)
+	}
+	if !conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected WorkspaceContentDeleted to be True, got %v", conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted))
+	}
+}
+
+func TestWorkspaceTerminatingListPagination(t *testing.T) {
+	var objects []runtime.Object
+	for i := 0; i < 7; i++ {
+		objects = append(objects, newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", fmt.Sprintf("crd%d", i), ""))
+	}
+	pager := &pagingMetadataClient{ClusterInterface: kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)}
+	d := NewWorkspacedResourcesDeleter(pager, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithListPageSize(3))
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if remainingErr.EstimatedRemaining != 7 {
+		t.Errorf("expected 7 remaining resource instances, got %d", remainingErr.EstimatedRemaining)
+	}
+	if diff := cmp.Diff([]int64{3, 3, 3}, pager.limits); diff != "" {
+		t.Errorf("unexpected list limits (-want +got):\n%s", diff)
+	}
+}
+
+func TestWorkspaceTerminatingListBufferPool(t *testing.T) {
+	var objects []runtime.Object
+	for i := 0; i < 7; i++ {
+		objects = append(objects, newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", fmt.Sprintf("crd%d", i), ""))
+	}
+	pager := &pagingMetadataClient{ClusterInterface: kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)}
+	d := NewWorkspacedResourcesDeleter(pager, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithListPageSize(3), WithBufferPool(NewListBufferPool()))
+
+	// later passes reuse the buffers released by earlier ones.
+	for pass := 0; pass < 3; pass++ {
+		var remainingErr *ResourcesRemainingError
+		if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); !goerrors.As(err, &remainingErr) {
+			t.Fatalf("pass %d: expected ResourcesRemainingError, got %v", pass, err)
+		}
+		if diff := cmp.Diff(map[schema.GroupVersionResource]int{{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}: 7}, remainingErr.RemainingByResource); diff != "" {
+			t.Errorf("pass %d: unexpected remaining resources (-want +got):\n%s", pass, diff)
+		}
+	}
+}
+
+func BenchmarkListPages(b *testing.B) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	var objects []runtime.Object
+	for i := 0; i < 1000; i++ {
+		objects = append(objects, newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", fmt.Sprintf("crd%d", i), ""))
+	}
+
+	for _, bm := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "unpooled"},
+		{name: "pooled", opts: []Option{WithBufferPool(NewListBufferPool())}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			d := NewWorkspacedResourcesDeleter(&pagingMetadataClient{ClusterInterface: kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)}, nil, append([]Option{WithListPageSize(50)}, bm.opts...)...).(*logicalClusterResourcesDeleter)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				list, err := d.listPages(context.TODO(), logicalcluster.Name("root"), crds)
+				if err != nil {
+					b.Fatal(err)
+				}
+				d.releaseList(list)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingForceRemoveFinalizers(t *testing.T) {
+	tests := []struct {
+		name            string
+		opts            []Option
+		confirmation    string
+		expectedPatches []int
+	}{
+		{
+			name:            "disabled",
+			confirmation:    "root",
+			expectedPatches: []int{0, 0, 0},
+		},
+		{
+			name:            "after two passes",
+			opts:            []Option{WithForceRemoveFinalizers(2)},
+			confirmation:    "root",
+			expectedPatches: []int{0, 1, 2},
+		},
+		{
+			name:            "not confirmed",
+			opts:            []Option{WithForceRemoveFinalizers(2)},
+			expectedPatches: []int{0, 0, 0},
+		},
+		{
+			name:            "mismatching confirmation",
+			opts:            []Option{WithForceRemoveFinalizers(2)},
+			confirmation:    "true",
+			expectedPatches: []int{0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := metav1.Now()
+			stuck := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", "")
+			stuck.DeletionTimestamp = &now
+			stuck.Finalizers = []string{"example.com/gone-controller"}
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, stuck)
+			patches := 0
+			mockMetadataClient.PrependReactor("patch", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				patches++
+				if patch := string(action.(kcptesting.PatchAction).GetPatch()); patch != `{"metadata":{"finalizers":null}}` {
+					t.Errorf("unexpected patch %s", patch)
+				}
+				// the fake does not delete the object once its finalizers are gone.
+				return true, stuck, nil
+			})
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, tt.opts...)
+
+			ws := newTerminatingLogicalCluster()
+			if tt.confirmation != "" {
+				ws.Annotations[ForceFinalizeAnnotationKey] = tt.confirmation
+			}
+			var got []int
+			for range tt.expectedPatches {
+				var remainingErr *ResourcesRemainingError
+				if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+					t.Fatalf("expected ResourcesRemainingError, got %v", err)
+				}
+				got = append(got, patches)
+			}
+			if diff := cmp.Diff(tt.expectedPatches, got); diff != "" {
+				t.Errorf("unexpected number of patches after each pass (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestObserveProgress(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+
+	passes := []struct {
+		remaining       map[schema.GroupVersionResource]int
+		expectedStalled bool
+	}{
+		{remaining: map[schema.GroupVersionResource]int{crds: 3, widgets: 2}},
+		{remaining: map[schema.GroupVersionResource]int{crds: 3, widgets: 1}},
+		{remaining: map[schema.GroupVersionResource]int{crds: 3, widgets: 1}},
+		{remaining: map[schema.GroupVersionResource]int{crds: 3, widgets: 1}, expectedStalled: true},
+		{remaining: map[schema.GroupVersionResource]int{crds: 4, widgets: 1}, expectedStalled: true},
+		{remaining: map[schema.GroupVersionResource]int{crds: 4}},
+	}
+
+	ws := newTerminatingLogicalCluster()
+	var snapshot RemainingSnapshot
+	for i, pass := range passes {
+		snapshot = ObserveProgress(ws, snapshot, pass.remaining, 2)
+		if stalled := conditions.IsTrue(ws, tenancyv1alpha1.WorkspaceDeletionStalled); stalled != pass.expectedStalled {
+			t.Errorf("pass %d: expected stalled %v, got %v", i, pass.expectedStalled, conditions.Get(ws, tenancyv1alpha1.WorkspaceDeletionStalled))
+		}
+	}
+
+	ws = newTerminatingLogicalCluster()
+	snapshot = RemainingSnapshot{Remaining: map[schema.GroupVersionResource]int{crds: 3, widgets: 1}, StalledPasses: 1}
+	ObserveProgress(ws, snapshot, map[schema.GroupVersionResource]int{crds: 3, widgets: 1}, 2)
+	expected := "No progress in 2 deletion passes: customresourcedefinitions.apiextensions.k8s.io has 3 resource instances, widgets.example.com has 1 resource instances"
+	if got := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceDeletionStalled); got != expected {
+		t.Errorf("expected message %q, got %q", expected, got)
+	}
+}
+
+func TestResourcesRemainingErrorByResource(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+		newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", ""),
+	)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if diff := cmp.Diff(map[schema.GroupVersionResource]int{crds: 2}, remainingErr.RemainingByResource); diff != "" {
+		t.Errorf("unexpected remaining resources (-want +got):\n%s", diff)
+	}
+}
+
+func TestResourcesRemainingErrorDetails(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	now := metav1.Now()
+	var objects []runtime.Object
+	for _, s := range []struct{ name, namespace string }{{"s1", "ns1"}, {"s2", "ns1"}, {"s3", "ns2"}} {
+		secret := newPartialObject("v1", "Secret", s.name, s.namespace)
+		secret.DeletionTimestamp = &now
+		secret.Finalizers = []string{"example.com/cleanup"}
+		objects = append(objects, secret)
+	}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, objects...)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.DeleteInNamespaces(context.TODO(), newTerminatingLogicalCluster(), []string{"ns1", "ns2"}, true); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if diff := cmp.Diff(map[schema.GroupVersionResource]int{secrets: 3}, remainingErr.RemainingByResource); diff != "" {
+		t.Errorf("unexpected remaining resources (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]int{"ns1": 2, "ns2": 1}, remainingErr.RemainingByNamespace); diff != "" {
+		t.Errorf("unexpected remaining namespaces (-want +got):\n%s", diff)
+	}
+	if expected := "some content remains in the logical cluster, estimate 5 seconds before it is removed: 3 resource instances remaining in namespaces ns1, ns2"; remainingErr.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, remainingErr.Error())
+	}
+	if expected := "secrets. has 3 resource instances, namespace ns1 has 2 resource instances, namespace ns2 has 1 resource instances"; remainingErr.Details() != expected {
+		t.Errorf("expected details %q, got %q", expected, remainingErr.Details())
+	}
+}
+
+func TestWorkspaceTerminatingSkipExternallyOwned(t *testing.T) {
+	secrets := schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	owner := newPartialObject("v1", "Secret", "owner", "ns1")
+	owner.UID = "owner-uid"
+	owned := newPartialObject("v1", "Secret", "owned", "ns1")
+	owned.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: "owner", UID: "owner-uid"}}
+	external := newPartialObject("v1", "Secret", "external", "ns1")
+	external.OwnerReferences = []metav1.OwnerReference{{APIVersion: "v1", Kind: "Secret", Name: "elsewhere", UID: "elsewhere-uid"}}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, owner, owned, external)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithSkipExternallyOwned())
+
+	var remainingErr *ResourcesRemainingError
+	if err := d.DeleteInNamespaces(context.TODO(), newTerminatingLogicalCluster(), []string{"ns1"}, true); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if remainingErr.ExternallyOwned != 1 {
+		t.Errorf("expected 1 externally owned instance, got %d", remainingErr.ExternallyOwned)
+	}
+
+	var deleted []string
+	for _, action := range mockMetadataClient.Actions() {
+		if action.Matches("delete-collection", "secrets") {
+			t.Errorf("unexpected delete-collection of secrets")
+		}
+		if deleteAction, ok := action.(kcptesting.DeleteAction); ok && action.GetResource() == secrets {
+			deleted = append(deleted, deleteAction.GetName())
+		}
+	}
+	sort.Strings(deleted)
+	if diff := cmp.Diff([]string{"owned", "owner"}, deleted); diff != "" {
+		t.Errorf("unexpected deletions (-want +got):\n%s", diff)
+	}
+	if _, err := mockMetadataClient.Cluster(logicalcluster.NewPath("root")).Resource(secrets).Namespace("ns1").Get(context.TODO(), "external", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the externally owned secret to be preserved: %v", err)
+	}
+}
+
+func TestWorkspaceTerminatingDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        DeletionPolicy
+		wantRemaining bool
+		wantStatus    v1.ConditionStatus
+	}{
+		{name: "strict", policy: StrictDeletion, wantRemaining: true, wantStatus: v1.ConditionFalse},
+		{name: "best effort", policy: BestEffortDeletion, wantStatus: v1.ConditionTrue},
+		{name: "unset", wantRemaining: true, wantStatus: v1.ConditionFalse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+			)
+			var gotType tenancyv1alpha1.WorkspaceTypeReference
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, WithDeletionPolicy(func(workspaceType tenancyv1alpha1.WorkspaceTypeReference) DeletionPolicy {
+				gotType = workspaceType
+				return tt.policy
+			}))
+
+			ws := newTerminatingLogicalCluster()
+			ws.Annotations[tenancyv1alpha1.LogicalClusterTypeAnnotationKey] = "root:org:ephemeral"
+			err := d.Delete(context.TODO(), ws)
+			var remainingErr *ResourcesRemainingError
+			if got := goerrors.As(err, &remainingErr); got != tt.wantRemaining {
+				t.Fatalf("expected ResourcesRemainingError %v, got %v", tt.wantRemaining, err)
+			}
+			if !tt.wantRemaining && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expected := (tenancyv1alpha1.WorkspaceTypeReference{Name: "ephemeral", Path: "root:org"}); gotType != expected {
+				t.Errorf("expected workspace type %v, got %v", expected, gotType)
+			}
+			condition := conditions.Get(ws, tenancyv1alpha1.WorkspaceContentDeleted)
+			if condition == nil {
+				t.Fatal("expected WorkspaceContentDeleted condition")
+			}
+			if condition.Status != tt.wantStatus {
+				t.Errorf("expected condition status %s, got %s", tt.wantStatus, condition.Status)
+			}
+			if tt.policy == BestEffortDeletion && condition.Severity != conditionsv1alpha1.ConditionSeverityWarning {
+				t.Errorf("expected warning severity, got %q", condition.Severity)
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingNamespaces(t *testing.T) {
+	now := metav1.Now()
+	ns := newPartialObject("v1", "Namespace", "ns1", "")
+	ns.DeletionTimestamp = &now
+	ns.Finalizers = []string{"kubernetes"}
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme, ns)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		resources := testResources()
+		resources[0].APIResources = append(resources[0].APIResources, metav1.APIResource{
+			Name:       "namespaces",
+			Namespaced: false,
+			Kind:       "Namespace",
+			Verbs:      []string{"get", "list", "delete", "deletecollection"},
+		})
+		return resources, nil
+	})
+
+	ws := newTerminatingLogicalCluster()
+	var remainingErr *ResourcesRemainingError
+	if err := d.Delete(context.TODO(), ws); !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ResourcesRemainingError, got %v", err)
+	}
+	if remainingErr.TerminatingNamespaces != 1 {
+		t.Errorf("expected 1 terminating namespace, got %d", remainingErr.TerminatingNamespaces)
+	}
+	if remainingErr.RemainingObjects != 0 {
+		t.Errorf("expected no remaining objects, got %d", remainingErr.RemainingObjects)
+	}
+	message := conditions.GetMessage(ws, tenancyv1alpha1.WorkspaceContentDeleted)
+	if !strings.Contains(message, "Waiting for 1 terminating namespaces to be finalized") {
+		t.Errorf("expected the condition to mention the terminating namespace, got %q", message)
+	}
+	if strings.Contains(message, "Some resources are remaining") {
+		t.Errorf("expected terminating namespaces not to be reported as remaining resources, got %q", message)
+	}
+}
+
+func TestWorkspaceTerminatingMetadataClientResolver(t *testing.T) {
+	coreClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	extensionsClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	d := NewWorkspacedResourcesDeleter(kcpfakemetadata.NewSimpleMetadataClient(scheme), func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		resources := testResources()
+		resources[0].APIResources = append(resources[0].APIResources, metav1.APIResource{
+			Name:       "namespaces",
+			Namespaced: false,
+			Kind:       "Namespace",
+			Verbs:      []string{"get", "list", "delete", "deletecollection"},
+		})
+		return resources, nil
+	}, WithMetadataClientResolver(func(gvr schema.GroupVersionResource) kcpmetadata.ClusterInterface {
+		if gvr.Group == "" {
+			return coreClient
+		}
+		return extensionsClient
+	}))
+
+	if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		client   *kcpfakemetadata.FakeMetadataClusterClientset
+		resource string
+	}{
+		"core":       {client: coreClient, resource: "namespaces"},
+		"extensions": {client: extensionsClient, resource: "customresourcedefinitions"},
+	} {
+		actions := tc.client.Actions()
+		if len(actions) == 0 {
+			t.Errorf("expected actions on the %s client", name)
+		}
+		for _, action := range actions {
+			if action.GetResource().Resource != tc.resource {
+				t.Errorf("expected only %s actions on the %s client, got %v", tc.resource, name, action)
+			}
+		}
+	}
+}
+
+func TestWorkspaceTerminatingConcurrentDelete(t *testing.T) {
+	discover := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}
+
+	sequentialClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	if err := NewWorkspacedResourcesDeleter(sequentialClient, discover).Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+		t.Fatal(err)
+	}
+
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	mockMetadataClient.PrependReactor("*", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+		return false, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, discover)
+
+	lcs := []*corev1alpha1.LogicalCluster{newTerminatingLogicalCluster(), newTerminatingLogicalCluster()}
+	errs := make([]error, len(lcs))
+	var wg sync.WaitGroup
+	wg.Add(len(lcs))
+	go func() {
+		defer wg.Done()
+		errs[0] = d.Delete(context.TODO(), lcs[0])
+	}()
+	<-entered
+	go func() {
+		defer wg.Done()
+		errs[1] = d.Delete(context.TODO(), lcs[1])
+	}()
+	// give the second call time to join the pass in flight.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, lc := range lcs {
+		if errs[i] != nil {
+			t.Errorf("call %d: unexpected error: %v", i, errs[i])
+		}
+		if !conditions.IsTrue(lc, tenancyv1alpha1.WorkspaceContentDeleted) {
+			t.Errorf("call %d: expected content to be deleted", i)
+		}
+		for _, key := range []string{ContentDeletionStartedAnnotationKey, DeletionIssuedAnnotationKey} {
+			if _, ok := lc.Annotations[key]; !ok {
+				t.Errorf("call %d: expected annotation %s", i, key)
+			}
+		}
+	}
+	if diff := cmp.Diff(lcs[0].Annotations, lcs[1].Annotations); diff != "" {
+		t.Errorf("expected the same annotations for both calls: %s", diff)
+	}
+	if got, expected := len(mockMetadataClient.Actions()), len(sequentialClient.Actions()); got != expected {
+		t.Errorf("expected %d actions of a single pass, got %d", expected, got)
+	}
+}
+
+func TestWorkspaceTerminatingConcurrentDeleteFirstCallerCancelled(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	mockMetadataClient.PrependReactor("*", "*", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		once.Do(func() {
+			close(entered)
+			<-release
+		})
+		return false, nil, nil
+	})
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+
+	lcs := []*corev1alpha1.LogicalCluster{newTerminatingLogicalCluster(), newTerminatingLogicalCluster()}
+	errs := make([]error, len(lcs))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(len(lcs))
+	go func() {
+		defer wg.Done()
+		errs[0] = d.Delete(ctx, lcs[0])
+	}()
+	<-entered
+	go func() {
+		defer wg.Done()
+		errs[1] = d.Delete(context.TODO(), lcs[1])
+	}()
+	// give the second call time to join the pass in flight.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if !goerrors.Is(errs[0], context.Canceled) {
+		t.Errorf("expected the cancelled call to return context.Canceled, got %v", errs[0])
+	}
+	if errs[1] != nil {
+		t.Errorf("expected the pass to complete for the second call, got %v", errs[1])
+	}
+	if !conditions.IsTrue(lcs[1], tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected content to be deleted for the second call")
+	}
+	if _, ok := lcs[1].Annotations[DeletionIssuedAnnotationKey]; !ok {
+		t.Errorf("expected annotation %s for the second call", DeletionIssuedAnnotationKey)
+	}
+}
+
+func TestDeleteAll(t *testing.T) {
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	remainingCRD := newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", "")
+	remainingCRD.Annotations[logicalcluster.AnnotationKey] = "root:remaining"
+	if err := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root:remaining")).Add(remainingCRD); err != nil {
+		t.Fatal(err)
+	}
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	}, WithWorkerCount(2))
+
+	clusters := []logicalcluster.Name{"root:deletable", "root:remaining", "root:gone"}
+	lcs := map[logicalcluster.Name]*corev1alpha1.LogicalCluster{}
+	for _, clusterName := range clusters[:2] {
+		lc := newTerminatingLogicalCluster()
+		lc.Annotations[logicalcluster.AnnotationKey] = clusterName.String()
+		lcs[clusterName] = lc
+	}
+	err := d.DeleteAll(context.TODO(), clusters, func(clusterName logicalcluster.Name) *corev1alpha1.LogicalCluster {
+		return lcs[clusterName]
+	})
+
+	var remainingErr *ClustersRemainingError
+	if !goerrors.As(err, &remainingErr) {
+		t.Fatalf("expected ClustersRemainingError, got %v", err)
+	}
+	if got, expected := err.Error(), "some content remains in logical clusters root:remaining"; got != expected {
+		t.Errorf("expected error %q, got %q", expected, got)
+	}
+	if !conditions.IsTrue(lcs["root:deletable"], tenancyv1alpha1.WorkspaceContentDeleted) {
+		t.Errorf("expected content of root:deletable to be deleted")
+	}
+}
+
+func TestWorkspaceTerminatingLogging(t *testing.T) {
+	var lock sync.Mutex
+	var lines []string
+	logger := funcr.New(func(prefix, args string) {
+		lock.Lock()
+		defer lock.Unlock()
+		lines = append(lines, args)
+	}, funcr.Options{Verbosity: 4})
+
+	mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme)
+	d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return testResources(), nil
+	})
+	ws := newTerminatingLogicalCluster()
+	ws.Annotations[core.LogicalClusterPathAnnotationKey] = "root:org:team"
+	if err := d.Delete(klog.NewContext(context.TODO(), logger), ws); err != nil {
+		t.Fatal(err)
+	}
+
+	var deletedCollection, finished bool
+	for _, line := range lines {
+		if !strings.Contains(line, `"logicalCluster"="root"`) || !strings.Contains(line, `"workspace"="root:org:team"`) {
+			t.Errorf("expected logical cluster and workspace in log line %s", line)
+		}
+		deletedCollection = deletedCollection || strings.Contains(line, `"msg"="deleted collection"`)
+		finished = finished || strings.Contains(line, `"msg"="content deletion pass finished"`)
+	}
+	if !deletedCollection || !finished {
+		t.Errorf("expected delete-collection and outcome to be logged, got %v", lines)
+	}
+}
+
+func TestWorkspaceTerminatingPreDeleteHooks(t *testing.T) {
+	crds := schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+	tests := []struct {
+		name                    string
+		hookErr                 error
+		expectError             bool
+		metadataClientActionSet metaActionSet
+	}{
+		{
+			name: "hook succeeds",
+			metadataClientActionSet: []metaAction{
+				{"customresourcedefinitions", "list"},
+				{"customresourcedefinitions", "delete-collection"},
+				{"customresourcedefinitions", "list"},
+			},
+		},
+		{
+			name:        "hook fails",
+			hookErr:     goerrors.New("credentials still in use"),
+			expectError: true,
+			metadataClientActionSet: []metaAction{
+				{"customresourcedefinitions", "list"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd1", ""),
+				newPartialObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "crd2", ""),
+			)
+			mockMetadataClient.PrependReactor("delete-collection", "customresourcedefinitions", func(action kcptesting.Action) (bool, runtime.Object, error) {
+				tracker := mockMetadataClient.Tracker().Cluster(logicalcluster.NewPath("root"))
+				return true, nil, utilerrors.NewAggregate([]error{tracker.Delete(crds, "", "crd1"), tracker.Delete(crds, "", "crd2")})
+			})
+			calls := map[string]int{}
+			d := NewWorkspacedResourcesDeleter(mockMetadataClient, func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+				return testResources(), nil
+			}, WithPreDeleteHooks(map[schema.GroupVersionResource]PreDeleteHookFunc{
+				crds: func(ctx context.Context, gvr schema.GroupVersionResource, obj *metav1.PartialObjectMetadata) error {
+					calls[obj.Name]++
+					return tt.hookErr
+				},
+			}))
+
+			err := d.Delete(context.TODO(), newTerminatingLogicalCluster())
+			if (err != nil) != tt.expectError {
+				t.Fatalf("expected error %v, got %v", tt.expectError, err)
+			}
+			tt.metadataClientActionSet.expectInOrder(t, mockMetadataClient.Actions())
+			if !tt.expectError {
+				if diff := cmp.Diff(map[string]int{"crd1": 1, "crd2": 1}, calls); diff != "" {
+					t.Errorf("unexpected hook calls (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceTerminatingRateLimiter(t *testing.T) {
+	resources := append(testResources(), &metav1.APIResourceList{
+		GroupVersion: "example.com/v1",
+		APIResources: []metav1.APIResource{
+			{Name: "widgets", Kind: "Widget", Verbs: []string{"get", "list", "delete", "deletecollection"}},
+			{Name: "gadgets", Kind: "Gadget", Verbs: []string{"get", "list", "delete"}},
+		},
+	})
+	discover := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		return resources, nil
+	}
+
+	t.Run("every mutating call is gated", func(t *testing.T) {
+		mockMetadataClient := kcpfakemetadata.NewSimpleMetadataClient(scheme,
+			newPartialObject("example.com/v1", "Gadget", "g1", ""),
+			newPartialObject("example.com/v1", "Gadget", "g2", ""),
+		)
+		limiter := &countingRateLimiter{}
+		d := NewWorkspacedResourcesDeleter(mockMetadataClient, discover, WithRateLimiter(limiter))
+		if err := d.Delete(context.TODO(), newTerminatingLogicalCluster()); err != nil {
+			t.Fatal(err)
+		}
+		mutating := 0
+		for _, action := range mockMetadataClient.Actions() {
+			if action.GetVerb() != "list" {
+				mutating++
+			}
+		}
+		// delete-collection of crds and widgets, delete of each gadget.
+		if mutating != 4 || limiter.waits != mutating {
+			t.Errorf("expected 4 mutating calls each waiting for the limiter, got %d calls and %d waits", mutating, limiter.waits)
+		}
+	})
+
+	t.Run("waiting is cancelled with the context", func(t *testing.T) {
+		d := NewWorkspacedResourcesDeleter(kcpfakemetadata.NewSimpleMetadataClient(scheme), discover,
+			WithRateLimiter(flowcontrol.NewTokenBucketRateLimiter(0.001, 1)))
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		if err := d.Delete(ctx, newTerminatingLogicalCluster()); !goerrors.Is(err, context.Canceled) {
+			t.Errorf("expected context cancelled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected to return promptly after cancellation, took %s", elapsed)
+		}
+	})
+}
+
+// countingRateLimiter permits every call, counting the calls to Wait.
+type countingRateLimiter struct {
+	flowcontrol.RateLimiter
+
+	lock  sync.Mutex
+	waits int
+}
+
+func (l *countingRateLimiter) Wait(ctx context.Context) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.waits++
+	return nil
+}
+
+type metaAction struct {
+	resource string
+	verb     string
+}
+
+type metaActionSet []metaAction
+
+func (m metaActionSet) match(action kcptesting.Action) bool {
+	for _, a := range m {
+		if action.Matches(a.verb, a.resource) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// expectInOrder asserts that actions match the set one by one, in order.
+func (m metaActionSet) expectInOrder(t *testing.T, actions []kcptesting.Action) {
+	t.Helper()
+
+	if len(actions) != len(m) {
+		t.Fatalf("mismatched actions, expect %d actions, got %d actions: %v", len(m), len(actions), actions)
+	}
+	for i, action := range actions {
+		if !action.Matches(m[i].verb, m[i].resource) {
+			t.Errorf("expect action %d for resource %q for verb %q but got %v", i, m[i].resource, m[i].verb, action)
+		}
+	}
+}
+
+// deleteOptionsRecorder records the DeleteOptions of delete and delete-collection calls,
+// which the fake metadata client does not keep on its actions. Optionally, it delays these
+// calls to simulate latency.
+type deleteOptionsRecorder struct {
+	kcpmetadata.ClusterInterface
+	latency time.Duration
+
+	lock    sync.Mutex
+	options []metav1.DeleteOptions
+}
+
+func (r *deleteOptionsRecorder) Cluster(clusterPath logicalcluster.Path) metadata.Interface {
+	return &deleteOptionsRecorderCluster{Interface: r.ClusterInterface.Cluster(clusterPath), recorder: r}
+}
+
+func (r *deleteOptionsRecorder) record(opts metav1.DeleteOptions) {
+	time.Sleep(r.latency)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.options = append(r.options, opts)
+}
+
+type deleteOptionsRecorderCluster struct {
+	metadata.Interface
+	recorder *deleteOptionsRecorder
+}
+
+func (c *deleteOptionsRecorderCluster) Resource(gvr schema.GroupVersionResource) metadata.Getter {
+	return &deleteOptionsRecorderResource{ResourceInterface: c.Interface.Resource(gvr), getter: c.Interface.Resource(gvr), recorder: c.recorder}
+}
+
+type deleteOptionsRecorderResource struct {
+	metadata.ResourceInterface
+	getter   metadata.Getter
+	recorder *deleteOptionsRecorder
+}
+
+func (r *deleteOptionsRecorderResource) Namespace(ns string) metadata.ResourceInterface {
+	return &deleteOptionsRecorderResource{ResourceInterface: r.getter.Namespace(ns), getter: r.getter, recorder: r.recorder}
+}
+
+func (r *deleteOptionsRecorderResource) Delete(ctx context.Context, name string, opts metav1.DeleteOptions, subresources ...string) error {
+	r.recorder.record(opts)
+	return r.ResourceInterface.Delete(ctx, name, opts, subresources...)
+}
+
+func (r *deleteOptionsRecorderResource) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	r.recorder.record(opts)
+	return r.ResourceInterface.DeleteCollection(ctx, opts, listOpts)
+}
+
+func newTerminatingLogicalCluster() *corev1alpha1.LogicalCluster {
+	now := metav1.Now()
+	return &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{LogicalClusterDeletionFinalizer},
+			Annotations:       map[string]string{logicalcluster.AnnotationKey: "root"},
+		},
+	}
+}
+
+func newPartialObject(apiversion, kind, name, namespace string) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiversion,
+			Kind:       kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root"},
+		},
+	}
+}
+
+// testResources returns a mocked up set of resources across different api groups for testing namespace controller.
+func testResources() []*metav1.APIResourceList {
+	return NewResourceListBuilder().
+		Add("", "v1", "secrets", "Secret", true, "get", "list", "delete", "deletecollection", "create", "update").
+		Add("", "v1", "nodelete", "NoDelete", true, "get", "list", "create", "update").
+		Add("apiextensions.k8s.io", "v1", "customresourcedefinitions", "CustomResourceDefinition", false, "get", "list", "delete", "deletecollection", "create", "update").
+		Build()
+}
+
+// matchError returns true if errors match, false if they don't. Sentinel errors like ErrResourcesRemaining
+// are matched with errors.Is, other errors by error message only for convenience which should be sufficient
+// for these tests.
+func matchErrors(e1, e2 error) bool {
+	if e1 == nil && e2 == nil {
+		return true
+	}
+	if e1 != nil && e2 != nil {
+		if e2 == ErrResourcesRemaining || e2 == ErrDiscoveryFailed {
+			return goerrors.Is(e1, e2)
+		}
+		return e1.Error() == e2.Error()
+	}
+	return false
+}
+
+// pagingMetadataClient serves list calls in pages of the requested limit, which the fake metadata
+// client does not support. The continue token is the offset of the next page.
+type pagingMetadataClient struct {
+	kcpmetadata.ClusterInterface
+	limits []int64
+}
+
+func (c *pagingMetadataClient) Cluster(clusterPath logicalcluster.Path) metadata.Interface {
+	return &pagingMetadataClientCluster{Interface: c.ClusterInterface.Cluster(clusterPath), client: c}
+}
+
+type pagingMetadataClientCluster struct {
+	metadata.Interface
+	client *pagingMetadataClient
+}
+
+func (c *pagingMetadataClientCluster) Resource(gvr schema.GroupVersionResource) metadata.Getter {
+	return &pagingMetadataClientResource{ResourceInterface: c.Interface.Resource(gvr), getter: c.Interface.Resource(gvr), client: c.client}
+}
+
+type pagingMetadataClientResource struct {
+	metadata.ResourceInterface
+	getter metadata.Getter
+	client *pagingMetadataClient
+}
+
+func (r *pagingMetadataClientResource) Namespace(ns string) metadata.ResourceInterface {
+	return &pagingMetadataClientResource{ResourceInterface: r.getter.Namespace(ns), getter: r.getter, client: r.client}
+}
+
+func (r *pagingMetadataClientResource) List(ctx context.Context, opts metav1.ListOptions) (*metav1.PartialObjectMetadataList, error) {
+	r.client.limits = append(r.client.limits, opts.Limit)
+	list, err := r.ResourceInterface.List(ctx, opts)
+	if err != nil || opts.Limit == 0 {
+		return list, err
+	}
+	offset := 0
+	if opts.Continue != "" {
+		if offset, err = strconv.Atoi(opts.Continue); err != nil {
+			return nil, err
+		}
+	}
+	end := offset + int(opts.Limit)
+	if end < len(list.Items) {
+		list.Continue = strconv.Itoa(end)
+	} else {
+		end = len(list.Items)
+	}
+	list.Items = list.Items[offset:end]
+	return list, nil
 }
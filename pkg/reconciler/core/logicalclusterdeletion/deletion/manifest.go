@@ -0,0 +1,88 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ManifestEntry describes an object deleted during the deletion of a logical cluster.
+type ManifestEntry struct {
+	Cluster   logicalcluster.Name `json:"cluster"`
+	Group     string              `json:"group,omitempty"`
+	Version   string              `json:"version"`
+	Resource  string              `json:"resource"`
+	Namespace string              `json:"namespace,omitempty"`
+	Name      string              `json:"name"`
+	UID       types.UID           `json:"uid"`
+	DeletedAt metav1.Time         `json:"deletedAt"`
+}
+
+// ManifestWriter receives an entry for every object the deleter deletes, e.g. to keep an audit
+// trail in object storage. Objects that are already terminating are not written again.
+type ManifestWriter interface {
+	Write(ctx context.Context, entry ManifestEntry) error
+}
+
+// NewJSONManifestWriter returns a ManifestWriter that streams entries as JSON lines to w.
+// It is safe for concurrent use.
+func NewJSONManifestWriter(w io.Writer) ManifestWriter {
+	return &jsonManifestWriter{encoder: json.NewEncoder(w)}
+}
+
+type jsonManifestWriter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+func (w *jsonManifestWriter) Write(_ context.Context, entry ManifestEntry) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.encoder.Encode(entry)
+}
+
+// writeManifest writes the items of gvr that are not yet terminating to the manifest.
+func (d *logicalClusterResourcesDeleter) writeManifest(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) error {
+	now := metav1.NewTime(d.clock.Now())
+	for _, item := range items {
+		if !item.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := d.manifest.Write(ctx, ManifestEntry{
+			Cluster:   clusterName,
+			Group:     gvr.Group,
+			Version:   gvr.Version,
+			Resource:  gvr.Resource,
+			Namespace: item.Namespace,
+			Name:      item.Name,
+			UID:       item.UID,
+			DeletedAt: now,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const (
+	deleteCollectionSucceeded   = "success"
+	deleteCollectionFailed      = "error"
+	deleteCollectionUnsupported = "unsupported"
+)
+
+// Metrics are the metrics about the progress of logical cluster content deletions.
+type Metrics struct {
+	// RemainingResources is the number of remaining instances by logical cluster and resource.
+	RemainingResources *compbasemetrics.GaugeVec
+	// DeleteCollections is the number of delete-collection requests by outcome.
+	DeleteCollections *compbasemetrics.CounterVec
+}
+
+// NewMetrics returns unregistered deletion metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		RemainingResources: compbasemetrics.NewGaugeVec(
+			&compbasemetrics.GaugeOpts{
+				Name:           "kcp_workspace_deletion_remaining_resources",
+				Help:           "Number of resource instances remaining in a terminating logical cluster, by resource.",
+				StabilityLevel: compbasemetrics.ALPHA,
+			},
+			[]string{"logical_cluster", "gvr"},
+		),
+		DeleteCollections: compbasemetrics.NewCounterVec(
+			&compbasemetrics.CounterOpts{
+				Name:           "kcp_workspace_deletion_delete_collection_total",
+				Help:           "Number of delete-collection requests issued for terminating logical clusters, by outcome.",
+				StabilityLevel: compbasemetrics.ALPHA,
+			},
+			[]string{"outcome"},
+		),
+	}
+}
+
+// Register registers the metrics with the given registry, e.g. a dedicated one in tests.
+func (m *Metrics) Register(registry compbasemetrics.KubeRegistry) {
+	registry.MustRegister(m.RemainingResources)
+	registry.MustRegister(m.DeleteCollections)
+}
+
+// observeRemaining records the number of remaining instances of gvr. Resources without remaining
+// instances are dropped, i.e. they are reported as zero.
+func (m *Metrics) observeRemaining(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, numRemaining int) {
+	labels := map[string]string{"logical_cluster": clusterName.String(), "gvr": gvr.String()}
+	if numRemaining == 0 {
+		m.RemainingResources.Delete(labels)
+		return
+	}
+	m.RemainingResources.With(labels).Set(float64(numRemaining))
+}
+
+// defaultMetrics are used by deleters unless WithMetrics is set.
+var defaultMetrics = NewMetrics()
+
+var registerMetrics sync.Once
+
+// Register registers the default deletion metrics with the legacy registry.
+func Register() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(defaultMetrics.RemainingResources)
+		legacyregistry.MustRegister(defaultMetrics.DeleteCollections)
+	})
+}
+
+func init() {
+	Register()
+}
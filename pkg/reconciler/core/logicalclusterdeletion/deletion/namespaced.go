@@ -0,0 +1,142 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// DeleteInNamespaces deletes the namespaced content of the logical cluster in the given namespaces, e.g.
+// to evict a tenant from some namespaces of a workspace that survives. Afterwards, cluster-scoped content
+// is deleted as by Delete unless skipClusterScoped is true. Namespaces themselves are never deleted.
+// Like DeleteSelected, it never changes the conditions or finalizers of the logical cluster, and returns
+// a ResourcesRemainingError if content is still being deleted.
+func (d *logicalClusterResourcesDeleter) DeleteInNamespaces(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, namespaces []string, skipClusterScoped bool) error {
+	ctx, logger := withLogicalClusterLogger(ctx, logicalCluster)
+	logger = logger.WithValues("operation", "deleteInNamespaces", "namespaces", namespaces)
+	logger.V(5).Info("running operation")
+	clusterName := logicalcluster.From(logicalCluster)
+
+	resources, err := d.discoverResources(clusterName)
+	if isLogicalClusterGone(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// a scoped copy of the deleter. Settled resources are tracked for full deletions only.
+	scoped := *d
+	scoped.namespacedResources = namespacedGroupVersionResources(resources)
+	scoped.settled = nil
+	// remaining content counted from informer caches is only confirmed by full deletions.
+	scoped.listerFor = nil
+	groupVersionResources, err := scoped.deletableGroupVersionResources(resources)
+	if err != nil {
+		return err
+	}
+	delete(groupVersionResources, namespacesGVR)
+
+	clusterDeletedAt := metav1.NewTime(d.clock.Now())
+	if logicalCluster.DeletionTimestamp != nil {
+		clusterDeletedAt = *logicalCluster.DeletionTimestamp
+	}
+
+	var errs []error
+	remaining := contentRemaining{byResource: map[schema.GroupVersionResource]int{}, byNamespace: map[string]int{}}
+	for _, namespace := range namespaces {
+		scoped.namespace = namespace
+		inNamespace := map[schema.GroupVersionResource]sets.String{}
+		for gvr, verbs := range groupVersionResources {
+			if scoped.namespacedResources[gvr] {
+				inNamespace[gvr] = verbs
+			}
+		}
+		nsRemaining, err := scoped.deleteByPhase(ctx, clusterName, inNamespace, clusterDeletedAt)
+		if ctx.Err() != nil {
+			return fmt.Errorf("content deletion in namespaces of logical cluster %s interrupted: %w", clusterName, ctx.Err())
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if nsRemaining.estimate > remaining.estimate {
+			remaining.estimate = nsRemaining.estimate
+		}
+		remaining.numRemaining += nsRemaining.numRemaining
+		remaining.externallyOwned += nsRemaining.externallyOwned
+		remaining.retained += nsRemaining.retained
+		for gvr, n := range nsRemaining.byResource {
+			remaining.byResource[gvr] += n
+		}
+		for ns, n := range nsRemaining.byNamespace {
+			remaining.byNamespace[ns] += n
+		}
+	}
+
+	if !skipClusterScoped && remaining.numRemaining == 0 && len(errs) == 0 {
+		clusterScoped := map[schema.GroupVersionResource]sets.String{}
+		for gvr, verbs := range groupVersionResources {
+			if !scoped.namespacedResources[gvr] {
+				clusterScoped[gvr] = verbs
+			}
+		}
+		scoped.namespace = metav1.NamespaceAll
+		remaining, err = scoped.deleteByPhase(ctx, clusterName, clusterScoped, clusterDeletedAt)
+		if ctx.Err() != nil {
+			return fmt.Errorf("content deletion in namespaces of logical cluster %s interrupted: %w", clusterName, ctx.Err())
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	if remaining.numRemaining > 0 {
+		return remaining.remainingError(fmt.Sprintf("%d resource instances remaining in namespaces %s", remaining.numRemaining, strings.Join(namespaces, ", ")))
+	}
+	return nil
+}
+
+// namespacedGroupVersionResources returns the namespaced resources of lists.
+func namespacedGroupVersionResources(lists []*metav1.APIResourceList) map[schema.GroupVersionResource]bool {
+	ret := map[schema.GroupVersionResource]bool{}
+	for _, rl := range lists {
+		gv, err := schema.ParseGroupVersion(rl.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range rl.APIResources {
+			if r.Namespaced {
+				ret[gv.WithResource(r.Name)] = true
+			}
+		}
+	}
+	return ret
+}
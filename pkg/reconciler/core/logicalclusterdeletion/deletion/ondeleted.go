@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// reportDeleted calls the OnDeleted callback with the number of listed items that are not remaining,
+// by namespace in order.
+func (d *logicalClusterResourcesDeleter) reportDeleted(gvr schema.GroupVersionResource, listed, remaining []metav1.PartialObjectMetadata) {
+	key := func(item *metav1.PartialObjectMetadata) string {
+		return item.Namespace + "/" + item.Name + "/" + string(item.UID)
+	}
+	remainingKeys := sets.NewString()
+	for i := range remaining {
+		remainingKeys.Insert(key(&remaining[i]))
+	}
+	byNamespace := map[string]int{}
+	for i := range listed {
+		if !remainingKeys.Has(key(&listed[i])) {
+			byNamespace[listed[i].Namespace]++
+		}
+	}
+	for _, namespace := range sets.StringKeySet(byNamespace).List() {
+		d.onDeleted(gvr, namespace, byNamespace[namespace])
+	}
+}
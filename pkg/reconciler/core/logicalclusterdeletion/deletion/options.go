@@ -0,0 +1,566 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	kcpmetadata "github.com/kcp-dev/client-go/metadata"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/trace"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/utils/clock"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+)
+
+// Option configures optional behaviour of the deleter returned by NewWorkspacedResourcesDeleter.
+type Option func(*logicalClusterResourcesDeleter)
+
+// DeletionOrder defines the sequence of calls issued for a single resource during a deletion pass.
+type DeletionOrder string
+
+const (
+	// DeleteThenVerify issues a delete-collection first and lists afterwards to verify that
+	// no instances remain. This is the default.
+	DeleteThenVerify DeletionOrder = "DeleteThenVerify"
+	// ListThenDelete lists first and skips the delete-collection and the verification list
+	// when there is nothing to delete. This trades one extra list for non-empty resources
+	// against two saved calls for empty ones.
+	ListThenDelete DeletionOrder = "ListThenDelete"
+)
+
+// WithDeletionOrder configures the order of list and delete-collection calls per resource.
+// Resources for which orderFn returns an empty order use DeleteThenVerify.
+func WithDeletionOrder(orderFn func(gvr schema.GroupVersionResource) DeletionOrder) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.deletionOrderFn = orderFn
+	}
+}
+
+func (d *logicalClusterResourcesDeleter) deletionOrder(gvr schema.GroupVersionResource) DeletionOrder {
+	if d.deletionOrderFn == nil {
+		return DeleteThenVerify
+	}
+	if order := d.deletionOrderFn(gvr); order != "" {
+		return order
+	}
+	return DeleteThenVerify
+}
+
+// Scope selects the resources processed by a deletion pass by whether they are namespaced.
+type Scope string
+
+const (
+	// ClusterScopedOnly deletes the cluster-scoped resources, including namespaces. Namespaced content
+	// goes away with its namespace. This is the default.
+	ClusterScopedOnly Scope = "ClusterScopedOnly"
+	// NamespacedOnly deletes the namespaced resources in all namespaces, and keeps the cluster-scoped
+	// ones. Content deletion is not reported complete, as the cluster-scoped content remains.
+	NamespacedOnly Scope = "NamespacedOnly"
+	// AllScopes deletes both the namespaced and the cluster-scoped resources.
+	AllScopes Scope = "AllScopes"
+)
+
+// WithDeletionLock acquires lock for the logical cluster before every deletion pass and releases it
+// afterwards, such that deleters on different shards do not delete the same content concurrently.
+func WithDeletionLock(lock DeletionLock) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.lock = lock
+	}
+}
+
+// WithIsDeletable checks every deletion pass with isDeletable before discovering and deleting any content.
+// If it returns false, the pass is skipped with a NotReadyForDeletionError, and the WorkspaceNotReadyForDeletion
+// condition is set with the returned reason. The pass does not count as a deletion attempt.
+func WithIsDeletable(isDeletable func(logicalCluster *corev1alpha1.LogicalCluster) (bool, string)) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.isDeletable = isDeletable
+	}
+}
+
+// WithOnDeleted calls onDeleted with the number of instances of a resource in a namespace that are gone
+// after they were deleted by delete-collection or one by one, e.g. to release their quota. Cluster-scoped
+// instances are reported with an empty namespace. Resources are listed before they are deleted, and
+// instances still waiting for finalizers are reported in the pass in which they are gone. onDeleted can
+// be called concurrently for different resources when deleting with several workers.
+func WithOnDeleted(onDeleted func(gvr schema.GroupVersionResource, namespace string, count int)) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.onDeleted = onDeleted
+	}
+}
+
+// WithDeletionSummary accumulates the number of deleted instances by resource across deletion passes,
+// and reports the totals in the message of the WorkspaceContentDeleted condition once the content is
+// deleted. Resources are listed before they are deleted to count their instances.
+func WithDeletionSummary() Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.deletionSummary = true
+	}
+}
+
+// WithBufferPool reuses the buffers collecting the items of paginated lists from the given pool, to
+// reduce allocations when deleting logical clusters with many objects. The pool can be shared by
+// deleters running concurrently.
+func WithBufferPool(pool *ListBufferPool) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.bufferPool = pool
+	}
+}
+
+// WithScope restricts the resources processed by every deletion pass to the given scope, e.g. to
+// delete namespaced content while namespaces finalize, and cluster-scoped content later with another
+// deleter. It does not apply to DeleteInNamespaces.
+func WithScope(scope Scope) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.scope = scope
+	}
+}
+
+// WithSettledAfter skips resources in a deletion pass once they have been found empty in the
+// given number of consecutive passes of the same logical cluster. Settled resources are checked
+// again after the resync period has elapsed. A resync of zero never checks settled resources
+// again until the logical cluster is fully deleted.
+//
+// Only use this if nothing can recreate instances of the settled resources during deletion.
+func WithSettledAfter(passes int, resync time.Duration) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		if passes > 0 {
+			d.settled = newSettledTracker(passes, resync)
+		}
+	}
+}
+
+// WithPropagationPolicy sets the propagation policy of every delete and delete-collection call.
+// The default is background propagation.
+func WithPropagationPolicy(policy metav1.DeletionPropagation) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.propagationPolicy = policy
+	}
+}
+
+// WithMigrationInventory enables the migration mode: content is deleted with orphan propagation,
+// and every object is recorded with the given recorder before it is deleted, such that a migration
+// tool can re-create it and re-adopt its dependents in another logical cluster.
+func WithMigrationInventory(recorder InventoryRecorder) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.propagationPolicy = metav1.DeletePropagationOrphan
+		d.inventory = recorder
+	}
+}
+
+// PreDeletePatchFunc computes a patch that transitions obj into a deletable state, e.g. by clearing a
+// deletion protection field that a validating webhook enforces. A nil patch means obj can be deleted as is.
+type PreDeletePatchFunc func(obj *metav1.PartialObjectMetadata) (patchType types.PatchType, patch []byte, err error)
+
+// WithPreDeletePatches patches every instance of the given resources with the corresponding
+// PreDeletePatchFunc before the resource is deleted.
+func WithPreDeletePatches(patches map[schema.GroupVersionResource]PreDeletePatchFunc) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.preDeletePatches = patches
+	}
+}
+
+// PreDeleteHookFunc runs external cleanup for obj before it is deleted, e.g. to revoke cloud credentials
+// referenced by it. An error defers the deletion of all instances of gvr to a later pass.
+type PreDeleteHookFunc func(ctx context.Context, gvr schema.GroupVersionResource, obj *metav1.PartialObjectMetadata) error
+
+// WithPreDeleteHooks runs the corresponding PreDeleteHookFunc for every instance of the given resources
+// that is not terminating yet, before the resource is deleted. Hooks must be idempotent, as they run
+// again for instances that were not deleted in a pass.
+func WithPreDeleteHooks(hooks map[schema.GroupVersionResource]PreDeleteHookFunc) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.preDeleteHooks = hooks
+	}
+}
+
+// WithNamespaceLabelAggregator counts objects deleted one by one per value of the namespace
+// labels configured on the aggregator.
+func WithNamespaceLabelAggregator(aggregator *NamespaceLabelAggregator) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.namespaceLabelAggregator = aggregator
+	}
+}
+
+// WithAdaptiveDeleteBatch deletes objects of resources without delete-collection support in
+// concurrent batches, starting with initial deletions at a time. The batch size grows up to max
+// while the server keeps up, and shrinks when it throttles. By default objects are deleted serially.
+func WithAdaptiveDeleteBatch(initial, max int) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		if initial < 1 {
+			initial = 1
+		}
+		if max < initial {
+			max = initial
+		}
+		d.deleteBatchInitial, d.deleteBatchMax = initial, max
+	}
+}
+
+// WithManifest writes an entry for every deleted object to the given manifest writer.
+// Collections are listed before they are deleted in order to record their items.
+func WithManifest(manifest ManifestWriter) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.manifest = manifest
+	}
+}
+
+// WithGroupMigrations adds resources that have been migrated from a legacy group to a canonical group,
+// in addition to the well-known migrations out of the extensions group. When both are served, the
+// resource is only drained via the canonical group.
+func WithGroupMigrations(migrations map[schema.GroupResource]schema.GroupResource) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		merged := make(map[schema.GroupResource]schema.GroupResource, len(d.groupMigrations)+len(migrations))
+		for legacy, canonical := range d.groupMigrations {
+			merged[legacy] = canonical
+		}
+		for legacy, canonical := range migrations {
+			merged[legacy] = canonical
+		}
+		d.groupMigrations = merged
+	}
+}
+
+// WithClock sets the clock the deleter uses to observe the progress of a deletion and to wait, e.g.
+// for the grace period. Defaults to the real clock.
+func WithClock(c clock.Clock) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.clock = c
+	}
+}
+
+// WithResourceTimings accumulates the time spent deleting the content of each resource into timings.
+func WithResourceTimings(timings *ResourceTimings) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.timings = timings
+	}
+}
+
+// WithSlowResourceThreshold logs resources whose content deletion takes longer than threshold within
+// a single pass. Zero disables the logging.
+func WithSlowResourceThreshold(threshold time.Duration) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.slowResourceThreshold = threshold
+	}
+}
+
+// WithForceRemoveFinalizers strips the finalizers of terminating objects of the logical cluster that
+// remain after the given number of consecutive deletion passes, e.g. because the controller owning
+// the finalizer is gone. Finalizers are only removed from logical clusters confirming it with the
+// ForceFinalizeAnnotationKey annotation. This is destructive: whatever the finalizers were guarding,
+// e.g. external resources, is leaked. Zero, the default, never removes finalizers.
+func WithForceRemoveFinalizers(afterPasses int) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		if afterPasses > 0 {
+			d.forceRemoveFinalizersAfter = afterPasses
+			d.stuck = newStuckTracker()
+		}
+	}
+}
+
+// WithRateLimiter gates every delete, delete-collection and patch call of the deleter with limiter, e.g. to
+// protect a shard from delete-collection storms during bulk offboarding. Waiting for the limiter is
+// aborted when the context is done. By default, calls are not throttled.
+func WithRateLimiter(limiter flowcontrol.RateLimiter) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.rateLimiter = limiter
+	}
+}
+
+// WithListPageSize sets the maximum number of items returned per list call. The deleter follows the
+// continue token until all items are listed. Zero lists all items at once. The default is 500.
+func WithListPageSize(limit int64) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.listPageSize = limit
+	}
+}
+
+// WithExplicitResources deletes only the given resources, without calling the discovery function, e.g. for
+// a targeted cleanup of a known set of resources. Instead, discoverGroupVersion is called once per
+// GroupVersion of the given resources, e.g. with ServerResourcesForGroupVersion of a discovery client, to
+// look up whether a resource is served, its scope and its verbs. Scope, exclusions and protections still
+// apply.
+func WithExplicitResources(gvrs []schema.GroupVersionResource, discoverGroupVersion func(clusterName logicalcluster.Path, groupVersion string) (*metav1.APIResourceList, error)) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.explicitResources = append([]schema.GroupVersionResource{}, gvrs...)
+		d.discoverGroupVersionFn = discoverGroupVersion
+	}
+}
+
+// WithSelfIdentity declares the service account the deleter accesses logical clusters as, e.g. when its
+// teardown identity lives in the workspaces it deletes. The resources of the service account, its token
+// secrets, the role bindings and cluster role bindings granting it access and the roles they refer to are
+// looked up with kubeClusterClient in every pass, and deleted after all other content but namespaces, such
+// that the deleter does not revoke its own access mid-teardown.
+func WithSelfIdentity(serviceAccount types.NamespacedName, kubeClusterClient kcpkubernetesclientset.ClusterInterface) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.selfServiceAccount = &serviceAccount
+		d.kubeClusterClient = kubeClusterClient
+	}
+}
+
+// WithListers counts the instances remaining after a resource was deleted from the informer-backed
+// lister returned by listerFor, instead of listing them live, e.g. on shards with warm informer caches.
+// Delete and delete-collection calls are still live. As caches might be stale, the resources they
+// report empty are listed live once more before the content deletion completes. listerFor returns nil
+// for resources without a cache, which are listed live.
+func WithListers(listerFor func(clusterName logicalcluster.Name, gvr schema.GroupVersionResource) cache.GenericLister) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.listerFor = listerFor
+	}
+}
+
+// WithGracePeriod gives instances remaining after they were deleted the given duration to be
+// finalized, and lists them again before counting them as remaining. The wait is skipped when
+// nothing remains, and ends early when the context is done.
+func WithGracePeriod(gracePeriod time.Duration) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.gracePeriod = gracePeriod
+	}
+}
+
+// WithMaxDeletionAttempts fails the content deletion of a logical cluster terminally after the given
+// number of passes that failed, counted in DeletionAttemptsAnnotationKey. Passes that only leave content
+// remaining, e.g. waiting for finalizers, are not counted. No content is deleted afterwards until the
+// annotation is removed. Zero, the default, attempts indefinitely.
+func WithMaxDeletionAttempts(maxAttempts int) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.maxAttempts = maxAttempts
+	}
+}
+
+// WithAllowlist permits the deletion of resources that are excluded by default until the
+// allowlist expires, as observed by the deleter's clock.
+func WithAllowlist(allowlist Allowlist) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.allowlist = &allowlist
+	}
+}
+
+// WithOnContentDeleted calls onContentDeleted once the content of a logical cluster is deleted, i.e. in
+// the pass that turns the WorkspaceContentDeleted condition True, e.g. to record an audit entry or to
+// release quota. It is not called again by later passes. If it fails, the condition stays True and
+// the error is returned by Delete.
+func WithOnContentDeleted(onContentDeleted func(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.onContentDeleted = onContentDeleted
+	}
+}
+
+// WithListOptions uses the options returned by listOptionsFor in the list and delete-collection calls of
+// each resource, e.g. a field selector to only delete the pods of a certain phase. Instances not matching
+// the options are neither deleted nor counted as remaining. Limit and Continue are set by the deleter.
+func WithListOptions(listOptionsFor func(gvr schema.GroupVersionResource) metav1.ListOptions) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.listOptionsFor = listOptionsFor
+	}
+}
+
+// WithContentionDetection compares the remaining instances by resource after every deletion pass with
+// the report of the previous pass returned by previousReport, e.g. as kept by the caller from
+// DeleteWithReport. Resources whose remaining instances grew are named in the WorkspaceDeletionContention
+// condition. With halt, their deletion is halted in later passes to avoid a busy loop, until they are
+// removed from the DeletionHaltedAnnotationKey annotation. previousReport returns nil if there is no
+// previous report.
+func WithContentionDetection(previousReport func(clusterName logicalcluster.Name) *DeletionReport, halt bool) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.previousReport = previousReport
+		d.haltContended = halt
+	}
+}
+
+// WithRecreationDetection records the UIDs of the objects deleted, and flags objects listed later under
+// the same namespace and name with a different UID as recreated, e.g. by a controller that keeps
+// regenerating them. Unlike WithContentionDetection, this catches churn that does not grow the number of
+// remaining instances. The resources of recreated objects are named in the WorkspaceDeletionContention
+// condition and halted like contended resources, if enabled. Resources are listed before they are deleted.
+func WithRecreationDetection() Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.recreation = newRecreationTracker()
+	}
+}
+
+// WithContinueOnError keeps deleting the resources of later deletion phases when the deletion of resources
+// of earlier phases failed, for a best-effort teardown that deletes as much content as possible. Within a
+// phase, failing resources never stop the others. The errors are aggregated and returned at the end of
+// the pass, and the WorkspaceContentDeleted condition names the failed resources.
+func WithContinueOnError() Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.continueOnError = true
+	}
+}
+
+// WithMaxDeletionsPerPass caps the delete and delete-collection calls issued by a single call to Delete,
+// across all resources, to keep the passes of huge logical clusters short. Once the budget is spent, the
+// remaining resources are deferred and ResourcesRemainingError is returned, such that the next pass
+// continues with them. Resources are listed before they are deleted, such that resources emptied by
+// earlier passes do not spend the budget.
+func WithMaxDeletionsPerPass(maxDeletions int) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.maxDeletionsPerPass = maxDeletions
+	}
+}
+
+// WithMinAge only deletes objects created more than minAge ago according to the clock of the deleter,
+// e.g. to purge content past its retention period. Younger objects are retained and reported as such.
+// Objects are listed and deleted one by one instead of by delete-collection. The content deletion does
+// not complete while retained objects remain.
+func WithMinAge(minAge time.Duration) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.minAge = minAge
+	}
+}
+
+// WithSkipExternallyOwned leaves objects with owner references that do not resolve to an object in the
+// terminating logical cluster to the garbage collector, e.g. when their owners live in another workspace.
+// Resources are then listed and their other instances deleted one by one instead of by delete-collection.
+// Remaining externally owned objects are reported separately, and the content deletion does not complete
+// until they are gone.
+func WithSkipExternallyOwned() Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.skipExternallyOwned = true
+	}
+}
+
+// WithShouldDelete protects the resources for which shouldDelete returns false from deletion, e.g. to
+// enforce data-retention policies. Their content is never deleted, and the content deletion does not
+// complete as long as instances of protected resources remain.
+func WithShouldDelete(shouldDelete func(gvr schema.GroupVersionResource) bool) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.shouldDelete = shouldDelete
+	}
+}
+
+// WithVerbOverrides replaces the verbs discovered for the given resources, e.g. for resources of
+// extension API servers that advertise deletecollection without honoring it. The overridden verbs
+// decide whether and how the content of a resource is deleted: without deletecollection, instances
+// are deleted one by one, and without delete, the resource is not deleted at all.
+func WithVerbOverrides(overrides map[schema.GroupVersionResource][]string) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.verbOverrides = overrides
+	}
+}
+
+// WithShouldDeleteObject protects the objects for which shouldDeleteObject returns false from deletion,
+// e.g. secrets annotated to be retained. Objects are listed and deleted one by one instead of by
+// delete-collection. Vetoed objects are reported as such, and do not keep the content deletion from
+// completing.
+func WithShouldDeleteObject(shouldDeleteObject func(gvr schema.GroupVersionResource, obj *metav1.PartialObjectMetadata) bool) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.shouldDeleteObject = shouldDeleteObject
+	}
+}
+
+// WithWorkerCount deletes the content of up to n resources of the same deletion phase concurrently.
+// Phases are still processed one after the other. By default, resources are processed one by one.
+func WithWorkerCount(n int) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.workerCount = n
+	}
+}
+
+// WithMetrics sets the metrics updated by the deleter instead of the default ones registered
+// with the legacy registry.
+func WithMetrics(m *Metrics) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.metrics = m
+	}
+}
+
+// WithDeletionPriority deletes resources with a higher priority before those with a lower one
+// within the same deletion phase, e.g. custom resources before the secrets they own. Resources
+// have equal priority by default. With multiple workers, higher priorities are started first.
+// CRDs are deleted after the custom resources they define regardless of their priority.
+func WithDeletionPriority(priority func(gvr schema.GroupVersionResource) int) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.deletionPriority = priority
+	}
+}
+
+// WithEventRecorder records Kubernetes events about the progress of the content deletion on the
+// LogicalCluster.
+func WithEventRecorder(recorder events.EventRecorder) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.eventRecorder = recorder
+	}
+}
+
+// WithMetadataClientResolver resolves the metadata client per resource, e.g. when API groups are
+// served by different backends. By default, the client passed to NewWorkspacedResourcesDeleter is
+// used for all resources.
+func WithMetadataClientResolver(clientFor func(gvr schema.GroupVersionResource) kcpmetadata.ClusterInterface) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.metadataClientFor = clientFor
+	}
+}
+
+// WithDeletionPolicy selects the deletion policy by the type of the workspace backed by the logical
+// cluster. Types for which policyFn returns an empty policy, and logical clusters without a type,
+// use StrictDeletion.
+func WithDeletionPolicy(policyFn func(workspaceType tenancyv1alpha1.WorkspaceTypeReference) DeletionPolicy) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.deletionPolicyFn = policyFn
+	}
+}
+
+// WithDeletionCheckpoint records the resources found empty by a deletion pass in the
+// DeletionCheckpointAnnotationKey annotation of the LogicalCluster, and skips them in later passes.
+// Before the content deletion completes, a final pass verifies all resources again, such that
+// instances recreated in the meantime are still deleted.
+func WithDeletionCheckpoint() Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.checkpoint = true
+	}
+}
+
+// WithTracerProvider traces the content deletion passes with a tracer of the given provider, with a
+// span per resource below the span of the pass. If the context passed to Delete carries a recording
+// span, its tracer is used instead. By default, no spans are recorded.
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.tracerProvider = tracerProvider
+	}
+}
+
+// WithAwaitingFinalizersGrace classifies instances that are terminating and wait for finalizers
+// separately. They are not deleted again, and once they have been terminating for longer than grace,
+// the WorkspaceDeletionAwaitingFinalizers condition names the finalizers they wait for. Resources are
+// listed before they are deleted to find such instances.
+func WithAwaitingFinalizersGrace(grace time.Duration) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.awaitingFinalizersGrace = grace
+	}
+}
+
+// WithStatusClient writes the progress of the content deletion to status.deletion of the LogicalCluster
+// after every pass, through the given client. Unlike the conditions, which are committed by the caller,
+// the status is written right away, and conflicting writes are retried on the latest LogicalCluster.
+func WithStatusClient(client kcpclientset.ClusterInterface) Option {
+	return func(d *logicalClusterResourcesDeleter) {
+		d.statusClient = client
+	}
+}
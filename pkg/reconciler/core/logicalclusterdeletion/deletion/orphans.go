@@ -0,0 +1,81 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	kcpmetadata "github.com/kcp-dev/client-go/metadata"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// OwnerLogicalClusterUIDLabelKey marks a namespace as belonging to the LogicalCluster with the given UID.
+// Only namespaces with this label are considered by ReapOrphanNamespaces.
+const OwnerLogicalClusterUIDLabelKey = "deletion.kcp.io/owner-logicalcluster-uid"
+
+var logicalClustersGVR = corev1alpha1.SchemeGroupVersion.WithResource("logicalclusters")
+
+// ReapOrphanNamespaces deletes the namespaces of the given logical cluster that outlived the LogicalCluster
+// they belong to, e.g. because of a finalizer ordering bug. A namespace is orphaned if its
+// OwnerLogicalClusterUIDLabelKey label names another UID than the current LogicalCluster, or the
+// LogicalCluster does not exist anymore. Namespaces without the label and namespaces already terminating
+// are never touched. It returns the names of the namespaces it deleted.
+func ReapOrphanNamespaces(ctx context.Context, client kcpmetadata.ClusterInterface, clusterName logicalcluster.Name) ([]string, error) {
+	logger := klog.FromContext(ctx).WithValues("operation", "ReapOrphanNamespaces", "logicalCluster", clusterName.String())
+
+	var ownerUID types.UID
+	owner, err := client.Cluster(clusterName.Path()).Resource(logicalClustersGVR).Get(ctx, corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return nil, err
+	default:
+		ownerUID = owner.UID
+	}
+
+	namespaces, err := client.Cluster(clusterName.Path()).Resource(namespacesGVR).List(ctx, metav1.ListOptions{LabelSelector: OwnerLogicalClusterUIDLabelKey})
+	if err != nil {
+		return nil, err
+	}
+
+	var reaped []string
+	var errs []error
+	background := metav1.DeletePropagationBackground
+	for _, ns := range namespaces.Items {
+		uid, ok := ns.Labels[OwnerLogicalClusterUIDLabelKey]
+		if !ok || types.UID(uid) == ownerUID || ns.DeletionTimestamp != nil {
+			continue
+		}
+		logger.V(2).Info("deleting orphaned namespace", "namespace", ns.Name, "ownerUID", uid)
+		preconditions := metav1.Preconditions{UID: &ns.UID}
+		err := client.Cluster(clusterName.Path()).Resource(namespacesGVR).Delete(ctx, ns.Name, metav1.DeleteOptions{Preconditions: &preconditions, PropagationPolicy: &background})
+		if err != nil && !errors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		reaped = append(reaped, ns.Name)
+	}
+	return reaped, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// deletionPhase orders the deletion of resources within a pass. A phase is only started
+// when all resources of the previous phases are gone.
+type deletionPhase int
+
+const (
+	// deletionPhaseEarly holds resources that can interfere with the deletion of other content,
+	// like ResourceQuotas and LimitRanges enforced by admission.
+	deletionPhaseEarly deletionPhase = iota
+	// deletionPhaseDefault is the phase of all content without special ordering needs.
+	deletionPhaseDefault
+	// deletionPhaseSelf holds the resources of the objects the deleter itself depends on to access the
+	// logical cluster, like its service account, the token secret and RBAC. Deleting them earlier
+	// could revoke the access of the deleter mid-teardown.
+	deletionPhaseSelf
+	// deletionPhaseNamespaces is the final phase. It holds the namespaces, which are only deleted
+	// once all other content is gone, such that the namespace controller finalizing them does not
+	// race with the deletion of their content and strand it in terminating namespaces.
+	deletionPhaseNamespaces
+)
+
+// earlyGroupResources are resources that admission consults, and that could otherwise race with the teardown.
+var earlyGroupResources = map[schema.GroupResource]bool{
+	{Resource: "resourcequotas"}: true,
+	{Resource: "limitranges"}:    true,
+}
+
+// deletionPhaseOf returns the phase in which gvr is deleted. self are the resources the deleter depends on.
+func deletionPhaseOf(gvr schema.GroupVersionResource, self map[schema.GroupResource]bool) deletionPhase {
+	if gvr.GroupResource() == namespacesGVR.GroupResource() {
+		return deletionPhaseNamespaces
+	}
+	if earlyGroupResources[gvr.GroupResource()] {
+		return deletionPhaseEarly
+	}
+	if self[gvr.GroupResource()] {
+		return deletionPhaseSelf
+	}
+	return deletionPhaseDefault
+}
+
+// groupByDeletionPhase groups the resources by deletion phase, in phase order. Empty phases are omitted.
+// Within a phase, resources are sorted by group, then resource. self are the resources the deleter depends on.
+func groupByDeletionPhase(gvrs map[schema.GroupVersionResource]sets.String, self map[schema.GroupResource]bool) [][]schema.GroupVersionResource {
+	byPhase := map[deletionPhase][]schema.GroupVersionResource{}
+	for _, gvr := range sortedGroupVersionResources(gvrs) {
+		phase := deletionPhaseOf(gvr, self)
+		byPhase[phase] = append(byPhase[phase], gvr)
+	}
+
+	var phases [][]schema.GroupVersionResource
+	for phase := deletionPhaseEarly; phase <= deletionPhaseNamespaces; phase++ {
+		if len(byPhase[phase]) > 0 {
+			phases = append(phases, byPhase[phase])
+		}
+	}
+	return phases
+}
+
+// sortByDeletionPriority orders the resources of a phase by descending priority, such that
+// dependent types are deleted before the types they depend on.
+func sortByDeletionPriority(phase []schema.GroupVersionResource, priority func(gvr schema.GroupVersionResource) int) {
+	sort.SliceStable(phase, func(i, j int) bool {
+		return priority(phase[i]) > priority(phase[j])
+	})
+}
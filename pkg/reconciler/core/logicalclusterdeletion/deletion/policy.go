@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// DeletionPolicy defines whether the deletion of a logical cluster waits for its content to be gone.
+type DeletionPolicy string
+
+const (
+	// StrictDeletion waits until all content of the logical cluster is gone. This is the default.
+	StrictDeletion DeletionPolicy = "Strict"
+	// BestEffortDeletion deletes the content of the logical cluster once and considers it deleted
+	// even if some of it remains, e.g. for ephemeral workspaces that must never get stuck.
+	BestEffortDeletion DeletionPolicy = "BestEffort"
+)
+
+// deletionPolicy returns the policy for the type of the given logical cluster. Logical clusters
+// without a type use StrictDeletion.
+func (d *logicalClusterResourcesDeleter) deletionPolicy(logicalCluster *corev1alpha1.LogicalCluster) DeletionPolicy {
+	if d.deletionPolicyFn == nil {
+		return StrictDeletion
+	}
+	annotationValue, found := logicalCluster.Annotations[tenancyv1alpha1.LogicalClusterTypeAnnotationKey]
+	if !found {
+		return StrictDeletion
+	}
+	wtCluster, wtName := logicalcluster.NewPath(annotationValue).Split()
+	if policy := d.deletionPolicyFn(tenancyv1alpha1.WorkspaceTypeReference{
+		Name: tenancyv1alpha1.WorkspaceTypeName(wtName),
+		Path: wtCluster.String(),
+	}); policy != "" {
+		return policy
+	}
+	return StrictDeletion
+}
+
+// abandonRemaining marks the content of the logical cluster as deleted although some remains.
+func (d *logicalClusterResourcesDeleter) abandonRemaining(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, remaining contentRemaining) {
+	klog.FromContext(ctx).V(2).Info("abandoning remaining content with best-effort deletion policy", "remaining", remaining.numRemaining)
+	if d.settled != nil {
+		d.settled.forget(logicalcluster.From(logicalCluster))
+	}
+	if d.stuck != nil {
+		d.stuck.forget(logicalcluster.From(logicalCluster))
+	}
+	forgetEstimatedCompletion(logicalCluster)
+	d.event(logicalCluster, corev1.EventTypeWarning, eventReasonContentAbandoned, "Abandoned %d remaining resource instances: %s", remaining.numRemaining, remaining.message)
+	setDeletionConditions(logicalCluster, corev1.ConditionTrue, "ContentAbandoned", conditionsv1alpha1.ConditionSeverityWarning, remaining.message)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// preflightObjectName is the name of the object a dry-run delete is issued for when a resource does not
+// support delete-collection. The object need not exist, authorization is checked before it is looked up.
+const preflightObjectName = "kcp-deletion-preflight"
+
+// PreflightReport describes which of the verbs needed for the deletion the deletion identity is
+// permitted to use, by resource.
+type PreflightReport struct {
+	// Resources are the reports by resource, sorted by group, version and resource.
+	Resources []PreflightResource
+}
+
+// PreflightResource describes the verbs permitted for a single resource.
+type PreflightResource struct {
+	GVR schema.GroupVersionResource
+	// Permitted are the checked verbs the deletion identity is permitted to use.
+	Permitted []string
+	// Denied are the checked verbs the deletion identity is not permitted to use.
+	Denied []string
+}
+
+// Denied returns the resources with verbs the deletion identity is not permitted to use.
+func (r *PreflightReport) Denied() []schema.GroupVersionResource {
+	var denied []schema.GroupVersionResource
+	for _, pr := range r.Resources {
+		if len(pr.Denied) > 0 {
+			denied = append(denied, pr.GVR)
+		}
+	}
+	return denied
+}
+
+// Preflight checks for every resource Delete would delete whether the deletion identity is permitted
+// to list it and to delete its instances, without deleting anything and without changing the conditions
+// of the logical cluster. Deletion is checked with a dry-run delete-collection, or a dry-run delete if the
+// resource does not support delete-collection. Errors other than Forbidden are returned aggregated.
+func (d *logicalClusterResourcesDeleter) Preflight(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) (*PreflightReport, error) {
+	ctx, logger := withLogicalClusterLogger(ctx, logicalCluster)
+	logger = logger.WithValues("operation", "preflight")
+	clusterName := logicalcluster.From(logicalCluster)
+
+	var errs []error
+	resources, err := d.discoverResources(clusterName)
+	if isLogicalClusterGone(err) {
+		return &PreflightReport{}, nil
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	groupVersionResources, err := d.deletableGroupVersionResources(resources)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	report := &PreflightReport{}
+	for _, gvr := range sortedGroupVersionResources(groupVersionResources) {
+		resource := PreflightResource{GVR: gvr}
+		for verb, check := range d.preflightChecks(clusterName, gvr, groupVersionResources[gvr]) {
+			if err := d.throttle(ctx); err != nil {
+				return report, err
+			}
+			err := check(ctx)
+			switch {
+			case err == nil || errors.IsNotFound(err):
+				resource.Permitted = append(resource.Permitted, verb)
+			case errors.IsForbidden(err):
+				logger.V(4).Info("verb not permitted", "gvr", gvr, "verb", verb, "reason", err.Error())
+				resource.Denied = append(resource.Denied, verb)
+			default:
+				errs = append(errs, fmt.Errorf("failed to check %s of %s: %w", verb, gvr, err))
+			}
+		}
+		sort.Strings(resource.Permitted)
+		sort.Strings(resource.Denied)
+		report.Resources = append(report.Resources, resource)
+	}
+	return report, utilerrors.NewAggregate(errs)
+}
+
+// preflightChecks returns the calls checking the verbs needed to delete gvr, by verb.
+func (d *logicalClusterResourcesDeleter) preflightChecks(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String) map[string]func(ctx context.Context) error {
+	client := d.resourceClient(clusterName, gvr).Namespace(d.namespaceOf(gvr))
+	dryRun := d.deleteOptions()
+	dryRun.DryRun = []string{metav1.DryRunAll}
+
+	checks := map[string]func(ctx context.Context) error{}
+	if verbs.Has(string(operationList)) {
+		checks[string(operationList)] = func(ctx context.Context) error {
+			opts := d.listOptions(gvr)
+			opts.Limit = 1
+			_, err := client.List(ctx, opts)
+			return err
+		}
+	}
+	if verbs.Has(string(operationDeleteCollection)) {
+		checks[string(operationDeleteCollection)] = func(ctx context.Context) error {
+			return client.DeleteCollection(ctx, dryRun, d.listOptions(gvr))
+		}
+	} else if verbs.Has("delete") {
+		checks["delete"] = func(ctx context.Context) error {
+			return client.Delete(ctx, preflightObjectName, dryRun)
+		}
+	}
+	return checks
+}
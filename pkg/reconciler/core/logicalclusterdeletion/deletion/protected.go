@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// partitionProtected splits gvrs into the resources to delete and those protected by shouldDelete.
+func (d *logicalClusterResourcesDeleter) partitionProtected(gvrs map[schema.GroupVersionResource]sets.String) (deletable, protected map[schema.GroupVersionResource]sets.String) {
+	if d.shouldDelete == nil {
+		return gvrs, nil
+	}
+	deletable = make(map[schema.GroupVersionResource]sets.String, len(gvrs))
+	protected = map[schema.GroupVersionResource]sets.String{}
+	for gvr, verbs := range gvrs {
+		if d.shouldDelete(gvr) {
+			deletable[gvr] = verbs
+		} else {
+			protected[gvr] = verbs
+		}
+	}
+	return deletable, protected
+}
+
+// protectedInstances counts the remaining instances by protected resource.
+type protectedInstances map[schema.GroupVersionResource]int
+
+func (p protectedInstances) String() string {
+	remaining := make([]string, 0, len(p))
+	for gvr, n := range p {
+		remaining = append(remaining, fmt.Sprintf("%s.%s has %d resource instances", gvr.Resource, gvr.Group, n))
+	}
+	// sort for stable updates
+	sort.Strings(remaining)
+	return strings.Join(remaining, ", ")
+}
+
+// protectedRemaining lists the protected resources and returns those that still have instances.
+func (d *logicalClusterResourcesDeleter) protectedRemaining(ctx context.Context, clusterName logicalcluster.Name, protected map[schema.GroupVersionResource]sets.String) (protectedInstances, error) {
+	ret := protectedInstances{}
+	for _, gvr := range sortedGroupVersionResources(protected) {
+		list, listSupported, err := d.listCollection(ctx, clusterName, gvr, protected[gvr])
+		if err != nil {
+			return nil, fmt.Errorf("failed to list protected resource %s: %w", gvr, err)
+		}
+		if listSupported && len(list.Items) > 0 {
+			ret[gvr] = len(list.Items)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"errors"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// quotaAdmissionRejection matches the messages of the Forbidden errors with which the ResourceQuota and
+// LimitRanger admission plugins reject requests. They carry neither a specific reason nor causes.
+var quotaAdmissionRejection = regexp.MustCompile(
+	// ResourceQuota.
+	`is forbidden: (exceeded quota|failed quota|status unknown for quota|insufficient quota to consume|quota usage is negative for resource\(s\)): ` +
+		// LimitRanger, possibly aggregating several violations.
+		`|is forbidden: .*((minimum|maximum) \S+ usage per \S+ is |max limit to request ratio per \S+ is |there was an error enforcing limit ranges)`,
+)
+
+// isQuotaInterference returns true if a quota or limit admission rejected a deletion request.
+func isQuotaInterference(errs []error) bool {
+	for _, err := range errs {
+		var status apierrors.APIStatus
+		if !apierrors.IsForbidden(err) || !errors.As(err, &status) {
+			continue
+		}
+		if quotaAdmissionRejection.MatchString(status.Status().Message) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// WorkspaceRBACLabelKey labels the RBAC objects created for a workspace with the name of its logical
+// cluster, such that DeleteWorkspaceRBAC can remove them with the workspace.
+const WorkspaceRBACLabelKey = "deletion.kcp.io/workspace-rbac"
+
+// rbacGroupResources are the resources deleted by DeleteWorkspaceRBAC.
+var rbacGroupResources = map[schema.GroupResource]bool{
+	{Group: rbac.GroupName, Resource: "roles"}:               true,
+	{Group: rbac.GroupName, Resource: "rolebindings"}:        true,
+	{Group: rbac.GroupName, Resource: "clusterroles"}:        true,
+	{Group: rbac.GroupName, Resource: "clusterrolebindings"}: true,
+}
+
+// DeleteWorkspaceRBAC deletes the roles, role bindings, cluster roles and cluster role bindings of the
+// logical cluster labeled with WorkspaceRBACLabelKey for it, in all namespaces. This includes RBAC
+// objects that the content deletion skips, i.e. cluster roles and bindings excluded by default and
+// namespaced objects outside of its scope. Like DeleteSelected, it never
+// changes the conditions or finalizers of the logical cluster, and returns a ResourcesRemainingError
+// if RBAC objects are still being deleted.
+func (d *logicalClusterResourcesDeleter) DeleteWorkspaceRBAC(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+	ctx, logger := withLogicalClusterLogger(ctx, logicalCluster)
+	clusterName := logicalcluster.From(logicalCluster)
+	selector := labels.SelectorFromSet(labels.Set{WorkspaceRBACLabelKey: clusterName.String()})
+	logger = logger.WithValues("operation", "deleteWorkspaceRBAC", "selector", selector.String())
+	logger.V(5).Info("running operation")
+
+	resources, err := d.discoverResources(clusterName)
+	if isLogicalClusterGone(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// a scoped copy of the deleter. Settled resources are tracked for full deletions only.
+	scoped := *d
+	scoped.labelSelector = selector.String()
+	scoped.namespacedResources = namespacedGroupVersionResources(resources)
+	scoped.settled = nil
+	// remaining content counted from informer caches is only confirmed by full deletions.
+	scoped.listerFor = nil
+
+	// cluster roles and bindings are excluded from the content deletion by default, but not here.
+	rbacResources := discovery.FilteredBy(and{
+		discovery.SupportsAllVerbs{Verbs: []string{"delete"}},
+		isNotSubresource{},
+		discovery.ResourcePredicateFunc(func(groupVersion string, r *metav1.APIResource) bool {
+			gv, err := schema.ParseGroupVersion(groupVersion)
+			return err == nil && rbacGroupResources[gv.WithResource(r.Name).GroupResource()]
+		}),
+	}, d.applyVerbOverrides(resources))
+	gvrs, err := groupVersionResources(rbacResources)
+	if err != nil {
+		return err
+	}
+	gvrs, _ = scoped.partitionProtected(resolveVersions(gvrs, preferredVersions(resources)))
+
+	clusterDeletedAt := metav1.NewTime(d.clock.Now())
+	if logicalCluster.DeletionTimestamp != nil {
+		clusterDeletedAt = *logicalCluster.DeletionTimestamp
+	}
+	remaining, err := scoped.deleteByPhase(ctx, clusterName, gvrs, clusterDeletedAt)
+	if err != nil {
+		return err
+	}
+	if remaining.numRemaining > 0 {
+		return remaining.remainingError(fmt.Sprintf("%d RBAC objects remaining", remaining.numRemaining))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// NotReadyForDeletionError is returned if the content of a logical cluster cannot be deleted yet, e.g.
+// because it is still initializing and not serving discovery. The deletion should be retried later.
+type NotReadyForDeletionError struct {
+	Reason string
+}
+
+func (e *NotReadyForDeletionError) Error() string {
+	return fmt.Sprintf("logical cluster is not ready for deletion: %s", e.Reason)
+}
+
+// checkDeletable returns a NotReadyForDeletionError and sets the WorkspaceNotReadyForDeletion condition
+// if the IsDeletable predicate rejects the logical cluster. Otherwise, the condition is removed.
+func (d *logicalClusterResourcesDeleter) checkDeletable(logicalCluster *corev1alpha1.LogicalCluster) error {
+	if d.isDeletable == nil {
+		return nil
+	}
+	deletable, reason := d.isDeletable(logicalCluster)
+	if deletable {
+		conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceNotReadyForDeletion)
+		return nil
+	}
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:     tenancyv1alpha1.WorkspaceNotReadyForDeletion,
+		Status:   corev1.ConditionTrue,
+		Severity: conditionsv1alpha1.ConditionSeverityInfo,
+		Reason:   "NotReady",
+		Message:  reason,
+	})
+	return &NotReadyForDeletionError{Reason: reason}
+}
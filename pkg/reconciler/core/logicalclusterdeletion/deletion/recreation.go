@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxRecreationTrackedClusters bounds the number of logical clusters whose UIDs are recorded, such that
+// logical clusters that are never passed again, e.g. because they went away, do not leak memory. The
+// least recently observed logical cluster is dropped first.
+const maxRecreationTrackedClusters = 1024
+
+// recreationTracker records the UIDs of the objects the deleter issued deletes for, by namespace/name,
+// to recognize objects recreated under the same name in later lists.
+type recreationTracker struct {
+	lock     sync.Mutex
+	clusters map[logicalcluster.Name]map[schema.GroupVersionResource]map[string]types.UID
+	// observed is the sequence number of the last observation per logical cluster.
+	observed map[logicalcluster.Name]uint64
+	seq      uint64
+}
+
+func newRecreationTracker() *recreationTracker {
+	return &recreationTracker{
+		clusters: map[logicalcluster.Name]map[schema.GroupVersionResource]map[string]types.UID{},
+		observed: map[logicalcluster.Name]uint64{},
+	}
+}
+
+// observe returns the namespace/name of the listed items of gvr whose UID differs from the one recorded
+// for the same name, i.e. that were recreated after a delete was issued for them. The UIDs of all items
+// are recorded, as a delete is issued for them.
+func (t *recreationTracker) observe(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+	if t.clusters[clusterName] == nil {
+		if len(t.clusters) >= maxRecreationTrackedClusters {
+			t.evictLeastRecentlyObserved()
+		}
+		t.clusters[clusterName] = map[schema.GroupVersionResource]map[string]types.UID{}
+	}
+	t.seq++
+	t.observed[clusterName] = t.seq
+	uids := t.clusters[clusterName][gvr]
+	if uids == nil {
+		uids = map[string]types.UID{}
+		t.clusters[clusterName][gvr] = uids
+	}
+	var recreated []string
+	for _, item := range items {
+		key := item.Name
+		if item.Namespace != metav1.NamespaceNone {
+			key = item.Namespace + "/" + item.Name
+		}
+		if uid, ok := uids[key]; ok && uid != item.UID {
+			recreated = append(recreated, key)
+		}
+		uids[key] = item.UID
+	}
+	return recreated
+}
+
+// forget drops all state of the given logical cluster.
+func (t *recreationTracker) forget(clusterName logicalcluster.Name) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.clusters, clusterName)
+	delete(t.observed, clusterName)
+}
+
+// evictLeastRecentlyObserved drops the state of the logical cluster observed least recently. The lock
+// must be held.
+func (t *recreationTracker) evictLeastRecentlyObserved() {
+	var oldest logicalcluster.Name
+	var oldestSeq uint64
+	for clusterName, seq := range t.observed {
+		if oldest == "" || seq < oldestSeq {
+			oldest, oldestSeq = clusterName, seq
+		}
+	}
+	delete(t.clusters, oldest)
+	delete(t.observed, oldest)
+}
+
+// observeRecreated returns the listed items of gvr that were recreated since a delete was issued for
+// them, and records the listed items as deleted. It is a no-op if recreation detection is disabled.
+func (d *logicalClusterResourcesDeleter) observeRecreated(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) []string {
+	if d.recreation == nil {
+		return nil
+	}
+	return d.recreation.observe(clusterName, gvr, items)
+}
+
+// forgetRecreatedOnEnd drops the recorded UIDs of the logical cluster if the pass returning err ended its
+// content deletion, i.e. the logical cluster is not deleting or finalized anymore, its content deletion was
+// skipped, cancelled, given up or completed, or its remaining content was abandoned.
+func (d *logicalClusterResourcesDeleter) forgetRecreatedOnEnd(clusterName logicalcluster.Name, err error) {
+	if d.recreation == nil {
+		return
+	}
+	var exceeded *DeletionAttemptsExceededError
+	if err == nil || errors.Is(err, ErrDeletionCancelled) || errors.As(err, &exceeded) {
+		d.recreation.forget(clusterName)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DeletionReport describes what a deletion pass did for every resource it attempted to delete.
+type DeletionReport struct {
+	// Resources are the reports by resource, sorted by group, version and resource.
+	Resources []ResourceReport
+
+	// lastProcessed is the resource whose result was added last.
+	lastProcessed schema.GroupVersionResource
+}
+
+// ResourceReport describes what a deletion pass did for a single resource.
+type ResourceReport struct {
+	GVR schema.GroupVersionResource
+	// Found is the number of instances listed before they were deleted, or -1 if the resource was
+	// not listed before deleting it.
+	Found int
+	// DeleteCollectionIssued is true if a delete-collection call was sent for the resource.
+	DeleteCollectionIssued bool
+	// Remaining is the number of instances remaining after the pass.
+	Remaining int
+	// ExternallyOwned is the number of remaining instances that were skipped because they are owned
+	// outside of the logical cluster.
+	ExternallyOwned int
+	// Retained is the number of remaining instances that were not deleted because they are younger than
+	// the minimum age.
+	Retained int
+	// Vetoed is the number of instances that were intentionally not deleted because they were vetoed.
+	// They are not counted as remaining.
+	Vetoed int
+	// AwaitingFinalizers is the number of remaining instances that have been terminating for longer than
+	// the grace period, waiting for finalizers.
+	AwaitingFinalizers int
+	// Recreated are the instances, as namespace/name, that were recreated under the same name after a
+	// delete was issued for them.
+	Recreated []string
+	// Skipped is true if the resource was not deleted because it was found empty in earlier passes.
+	Skipped bool
+	// DeferredBy are the resources whose remaining instances deferred the deletion of the resource to a
+	// later pass, e.g. the custom resources of CRDs.
+	DeferredBy []schema.GroupVersionResource
+	// Err is the error deleting the resource, if any.
+	Err error
+}
+
+// add records the result of deleting a resource. It is a no-op on a nil report.
+func (r *DeletionReport) add(result gvrDeletionResult) {
+	if r == nil || result.gvr.Empty() {
+		return
+	}
+	r.lastProcessed = result.gvr
+	r.Resources = append(r.Resources, ResourceReport{
+		GVR:                    result.gvr,
+		Found:                  result.metadata.numFound,
+		DeleteCollectionIssued: result.metadata.deleteCollectionIssued,
+		Remaining:              result.metadata.numRemaining,
+		ExternallyOwned:        result.metadata.numExternallyOwned,
+		Retained:               result.metadata.numRetained,
+		Vetoed:                 result.metadata.numVetoed,
+		AwaitingFinalizers:     result.metadata.numAwaitingFinalizers,
+		Recreated:              result.metadata.recreated,
+		Skipped:                result.settled,
+		DeferredBy:             result.deferredBy,
+		Err:                    result.err,
+	})
+}
+
+func (r *DeletionReport) sort() {
+	if r == nil {
+		return
+	}
+	sort.Slice(r.Resources, func(i, j int) bool {
+		return r.Resources[i].GVR.String() < r.Resources[j].GVR.String()
+	})
+}
+
+// String returns a human-readable summary of the report with one line per resource.
+func (r *DeletionReport) String() string {
+	if r == nil || len(r.Resources) == 0 {
+		return "no resources deleted"
+	}
+	lines := make([]string, 0, len(r.Resources))
+	for _, rr := range r.Resources {
+		lines = append(lines, rr.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (rr ResourceReport) String() string {
+	name := rr.GVR.Resource
+	if rr.GVR.Group != "" {
+		name = rr.GVR.Resource + "." + rr.GVR.Group
+	}
+	if rr.Skipped {
+		return fmt.Sprintf("%s: skipped, empty in earlier passes", name)
+	}
+	if len(rr.DeferredBy) > 0 {
+		deferredBy := make([]string, 0, len(rr.DeferredBy))
+		for _, gvr := range rr.DeferredBy {
+			deferredBy = append(deferredBy, gvr.GroupResource().String())
+		}
+		return fmt.Sprintf("%s: deferred until instances of %s are gone", name, strings.Join(deferredBy, ", "))
+	}
+	found := "not listed"
+	if rr.Found >= 0 {
+		found = fmt.Sprintf("%d found", rr.Found)
+	}
+	deleteCollection := "no delete-collection"
+	if rr.DeleteCollectionIssued {
+		deleteCollection = "delete-collection issued"
+	}
+	ret := fmt.Sprintf("%s: %s, %s, %d remaining", name, found, deleteCollection, rr.Remaining)
+	if rr.ExternallyOwned > 0 {
+		ret += fmt.Sprintf(" (%d externally owned)", rr.ExternallyOwned)
+	}
+	if rr.Retained > 0 {
+		ret += fmt.Sprintf(" (%d retained)", rr.Retained)
+	}
+	if rr.AwaitingFinalizers > 0 {
+		ret += fmt.Sprintf(" (%d awaiting finalizers)", rr.AwaitingFinalizers)
+	}
+	if len(rr.Recreated) > 0 {
+		ret += fmt.Sprintf(", %d recreated", len(rr.Recreated))
+	}
+	if rr.Vetoed > 0 {
+		ret += fmt.Sprintf(", %d vetoed", rr.Vetoed)
+	}
+	if rr.Err != nil {
+		ret += fmt.Sprintf(", error: %v", rr.Err)
+	}
+	return ret
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ReportDiff describes how the remaining instances by resource changed between two deletion reports.
+// All entries are sorted by resource.
+type ReportDiff struct {
+	// Added are the resources with remaining instances that had none in the old report.
+	Added []ResourceDiff
+	// Removed are the resources without remaining instances that had some in the old report.
+	Removed []ResourceDiff
+	// Changed are the resources whose number of remaining instances changed otherwise.
+	Changed []ResourceDiff
+}
+
+// ResourceDiff is the number of remaining instances of a resource in the old and the new report.
+type ResourceDiff struct {
+	GVR schema.GroupVersionResource
+	Old int
+	New int
+}
+
+// Grew returns true if instances of the resource were added between the reports.
+func (rd ResourceDiff) Grew() bool {
+	return rd.New > rd.Old
+}
+
+// Empty returns true if no resource changed between the reports.
+func (d ReportDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Growing returns the added and changed resources whose number of remaining instances grew, e.g.
+// because some controller recreates them faster than they are deleted.
+func (d ReportDiff) Growing() []ResourceDiff {
+	var growing []ResourceDiff
+	for _, diffs := range [][]ResourceDiff{d.Added, d.Changed} {
+		for _, rd := range diffs {
+			if rd.Grew() {
+				growing = append(growing, rd)
+			}
+		}
+	}
+	sortResourceDiffs(growing)
+	return growing
+}
+
+// DiffReports compares the remaining instances by resource of two deletion reports, e.g. of
+// consecutive passes, to tell whether the deletion makes progress.
+func DiffReports(oldReport, newReport DeletionReport) ReportDiff {
+	oldRemaining, newRemaining := remainingByResource(oldReport), remainingByResource(newReport)
+
+	var diff ReportDiff
+	for gvr, n := range newRemaining {
+		switch o := oldRemaining[gvr]; {
+		case o == 0:
+			diff.Added = append(diff.Added, ResourceDiff{GVR: gvr, New: n})
+		case o != n:
+			diff.Changed = append(diff.Changed, ResourceDiff{GVR: gvr, Old: o, New: n})
+		}
+	}
+	for gvr, o := range oldRemaining {
+		if _, found := newRemaining[gvr]; !found {
+			diff.Removed = append(diff.Removed, ResourceDiff{GVR: gvr, Old: o})
+		}
+	}
+	sortResourceDiffs(diff.Added)
+	sortResourceDiffs(diff.Removed)
+	sortResourceDiffs(diff.Changed)
+	return diff
+}
+
+// remainingByResource returns the resources of the report with remaining instances.
+func remainingByResource(report DeletionReport) map[schema.GroupVersionResource]int {
+	remaining := map[schema.GroupVersionResource]int{}
+	for _, rr := range report.Resources {
+		if rr.Remaining > 0 {
+			remaining[rr.GVR] += rr.Remaining
+		}
+	}
+	return remaining
+}
+
+func sortResourceDiffs(diffs []ResourceDiff) {
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].GVR.String() < diffs[j].GVR.String()
+	})
+}
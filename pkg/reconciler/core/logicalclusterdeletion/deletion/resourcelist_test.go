@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceListBuilder builds discovery fixtures for the deleter, grouped by group version in the
+// order the group versions are first added.
+type ResourceListBuilder struct {
+	lists []*metav1.APIResourceList
+}
+
+// NewResourceListBuilder returns an empty builder of discovery fixtures.
+func NewResourceListBuilder() *ResourceListBuilder {
+	return &ResourceListBuilder{}
+}
+
+// Add adds a resource with the given verbs. An empty group is the core group.
+func (b *ResourceListBuilder) Add(group, version, resource, kind string, namespaced bool, verbs ...string) *ResourceListBuilder {
+	groupVersion := schema.GroupVersion{Group: group, Version: version}.String()
+	var list *metav1.APIResourceList
+	for _, l := range b.lists {
+		if l.GroupVersion == groupVersion {
+			list = l
+			break
+		}
+	}
+	if list == nil {
+		list = &metav1.APIResourceList{GroupVersion: groupVersion}
+		b.lists = append(b.lists, list)
+	}
+	list.APIResources = append(list.APIResources, metav1.APIResource{
+		Name:       resource,
+		Namespaced: namespaced,
+		Kind:       kind,
+		Verbs:      verbs,
+	})
+	return b
+}
+
+// Build returns the discovery fixture.
+func (b *ResourceListBuilder) Build() []*metav1.APIResourceList {
+	return b.lists
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sort"
+	"time"
+
+	rbac "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/utils/clock"
+)
+
+// defaultExcludedGroupResources are kept to keep the logical cluster accessible for users in case
+// they have to debug.
+var defaultExcludedGroupResources = []schema.GroupResource{
+	{Group: rbac.GroupName, Resource: "clusterroles"},
+	{Group: rbac.GroupName, Resource: "clusterrolebindings"},
+}
+
+// Allowlist temporarily permits the deletion of resources that are excluded by default, e.g. for
+// the duration of a maintenance window. Once it has expired, the default exclusions apply again.
+type Allowlist struct {
+	// Resources are the excluded group resources which may be deleted.
+	Resources []schema.GroupResource
+	// ExpiresAt is the time after which the allowlist is ignored.
+	ExpiresAt time.Time
+}
+
+// allows returns true if gr is allowlisted and the allowlist has not expired at the given time.
+func (a *Allowlist) allows(gr schema.GroupResource, now time.Time) bool {
+	if a == nil || !now.Before(a.ExpiresAt) {
+		return false
+	}
+	for _, allowed := range a.Resources {
+		if allowed == gr {
+			return true
+		}
+	}
+	return false
+}
+
+// isNotExcludedResource returns a predicate filtering out the resources excluded by default,
+// except those permitted by an unexpired allowlist.
+func (d *logicalClusterResourcesDeleter) isNotExcludedResource(now time.Time) discovery.ResourcePredicate {
+	ret := and{}
+	for _, gr := range defaultExcludedGroupResources {
+		if d.allowlist.allows(gr, now) {
+			continue
+		}
+		ret = append(ret, isNotGroupResource{group: gr.Group, resource: gr.Resource})
+	}
+	return ret
+}
+
+// DeletableResources returns the resources of lists that support list and deletecollection and are
+// deleted with a logical cluster under the default exclusions, sorted by group, version and resource.
+// Subresources and namespaced resources, which go away with their namespace, are not included.
+// Resources served under several versions are returned with a single version. Lists with an unparseable group version are skipped.
+func DeletableResources(lists []*metav1.APIResourceList) []schema.GroupVersionResource {
+	valid := make([]*metav1.APIResourceList, 0, len(lists))
+	for _, rl := range lists {
+		if _, err := schema.ParseGroupVersion(rl.GroupVersion); err == nil {
+			valid = append(valid, rl)
+		}
+	}
+
+	d := &logicalClusterResourcesDeleter{groupMigrations: defaultGroupMigrations, clock: clock.RealClock{}}
+	gvrs, _ := d.candidateGroupVersionResources(valid, []string{"list", "deletecollection"})
+	ret := make([]schema.GroupVersionResource, 0, len(gvrs))
+	for gvr := range gvrs {
+		ret = append(ret, gvr)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
+// defaultGroupMigrations maps resources of legacy groups to the group they have been migrated to.
+// Both are backed by the same storage, so they must only be drained once.
+var defaultGroupMigrations = map[schema.GroupResource]schema.GroupResource{
+	{Group: "extensions", Resource: "deployments"}:         {Group: "apps", Resource: "deployments"},
+	{Group: "extensions", Resource: "daemonsets"}:          {Group: "apps", Resource: "daemonsets"},
+	{Group: "extensions", Resource: "replicasets"}:         {Group: "apps", Resource: "replicasets"},
+	{Group: "extensions", Resource: "ingresses"}:           {Group: "networking.k8s.io", Resource: "ingresses"},
+	{Group: "extensions", Resource: "networkpolicies"}:     {Group: "networking.k8s.io", Resource: "networkpolicies"},
+	{Group: "extensions", Resource: "podsecuritypolicies"}: {Group: "policy", Resource: "podsecuritypolicies"},
+}
+
+// resolveGroupMigrations drops resources of legacy groups from gvrs if the group they have been
+// migrated to is served as well, such that they are drained via the canonical group only. Legacy
+// resources are kept if the canonical group is not served (yet).
+func resolveGroupMigrations(gvrs map[schema.GroupVersionResource]sets.String, migrations map[schema.GroupResource]schema.GroupResource) map[schema.GroupVersionResource]sets.String {
+	served := make(map[schema.GroupResource]bool, len(gvrs))
+	for gvr := range gvrs {
+		served[gvr.GroupResource()] = true
+	}
+
+	ret := make(map[schema.GroupVersionResource]sets.String, len(gvrs))
+	for gvr, verbs := range gvrs {
+		if canonical, ok := migrations[gvr.GroupResource()]; ok && served[canonical] {
+			continue
+		}
+		ret[gvr] = verbs
+	}
+	return ret
+}
+
+// preferredVersions returns the version listed first for each group of lists. Discovery lists the
+// versions of a group in order of preference, starting with the preferred version.
+func preferredVersions(lists []*metav1.APIResourceList) map[string]string {
+	ret := map[string]string{}
+	for _, rl := range lists {
+		gv, err := schema.ParseGroupVersion(rl.GroupVersion)
+		if err != nil {
+			continue
+		}
+		if _, found := ret[gv.Group]; !found {
+			ret[gv.Group] = gv.Version
+		}
+	}
+	return ret
+}
+
+// resolveVersions keeps a single version of resources served under several versions of their
+// group. All versions are backed by the same storage, so their instances must only be deleted and
+// counted once. The preferred version of the group is kept if it serves the resource, otherwise
+// the highest version by Kubernetes version priority, e.g. v1 over v1beta1.
+func resolveVersions(gvrs map[schema.GroupVersionResource]sets.String, preferred map[string]string) map[schema.GroupVersionResource]sets.String {
+	kept := make(map[schema.GroupResource]schema.GroupVersionResource, len(gvrs))
+	for gvr := range gvrs {
+		gr := gvr.GroupResource()
+		other, found := kept[gr]
+		switch {
+		case !found:
+			kept[gr] = gvr
+		case other.Version == preferred[gr.Group]:
+		case gvr.Version == preferred[gr.Group] || version.CompareKubeAwareVersionStrings(gvr.Version, other.Version) > 0:
+			kept[gr] = gvr
+		}
+	}
+
+	ret := make(map[schema.GroupVersionResource]sets.String, len(kept))
+	for _, gvr := range kept {
+		ret[gvr] = gvrs[gvr]
+	}
+	return ret
+}
+
+// sortGroupVersionResources sorts gvrs by group, then resource, then version, such that they are
+// processed in the same order on every pass independently of map iteration.
+func sortGroupVersionResources(gvrs []schema.GroupVersionResource) {
+	sort.Slice(gvrs, func(i, j int) bool {
+		if gvrs[i].Group != gvrs[j].Group {
+			return gvrs[i].Group < gvrs[j].Group
+		}
+		if gvrs[i].Resource != gvrs[j].Resource {
+			return gvrs[i].Resource < gvrs[j].Resource
+		}
+		return gvrs[i].Version < gvrs[j].Version
+	})
+}
+
+// sortedGroupVersionResources returns the keys of gvrs in the order of sortGroupVersionResources.
+func sortedGroupVersionResources(gvrs map[schema.GroupVersionResource]sets.String) []schema.GroupVersionResource {
+	ret := make([]schema.GroupVersionResource, 0, len(gvrs))
+	for gvr := range gvrs {
+		ret = append(ret, gvr)
+	}
+	sortGroupVersionResources(ret)
+	return ret
+}
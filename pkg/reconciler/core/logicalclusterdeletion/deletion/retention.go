@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"math"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// partitionRetained splits items into those created before now minus the minimum age, which can be
+// deleted, and the younger ones, which are retained. It returns the estimate in seconds until the
+// first retained item is old enough to be deleted, 0 if none is retained.
+func (d *logicalClusterResourcesDeleter) partitionRetained(items []metav1.PartialObjectMetadata) (eligible, retained []metav1.PartialObjectMetadata, estimate int64) {
+	cutoff := d.clock.Now().Add(-d.minAge)
+	for _, item := range items {
+		if item.CreationTimestamp.Time.Before(cutoff) {
+			eligible = append(eligible, item)
+			continue
+		}
+		retained = append(retained, item)
+		seconds := int64(math.Ceil(item.CreationTimestamp.Time.Sub(cutoff).Seconds()))
+		if seconds < 1 {
+			seconds = 1
+		}
+		if estimate == 0 || seconds < estimate {
+			estimate = seconds
+		}
+	}
+	return eligible, retained, estimate
+}
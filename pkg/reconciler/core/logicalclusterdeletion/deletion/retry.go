@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// transientRetryBackoff bounds the attempts of list and delete-collection calls failing with transient
+// server errors, e.g. during an etcd leader election. Steps is the maximum number of attempts.
+var transientRetryBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: 100 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.5,
+}
+
+// isTransient returns true for server errors that are likely to succeed when retried right away.
+// Permanent errors like NotFound or Forbidden are not retried.
+func isTransient(err error) bool {
+	return errors.IsInternalError(err) || errors.IsTooManyRequests(err) || errors.IsUnexpectedServerError(err)
+}
+
+// retryTransient calls fn until it succeeds, fails with an error that is not transient, or the attempts
+// of transientRetryBackoff are exhausted. It does not wait beyond the deadline of ctx. The error of the
+// last attempt is returned.
+func (d *logicalClusterResourcesDeleter) retryTransient(ctx context.Context, fn func() error) error {
+	backoff := transientRetryBackoff
+	attempts := backoff.Steps
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !isTransient(err) || attempt >= attempts {
+			return err
+		}
+		delay := backoff.Step()
+		if deadline, ok := ctx.Deadline(); ok && d.clock.Now().Add(delay).After(deadline) {
+			return err
+		}
+		klog.FromContext(ctx).V(4).Info("retrying after transient error", "attempt", attempt, "delay", delay, "err", err.Error())
+		if waitErr := d.waitFor(ctx, delay); waitErr != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// DeleteSelected deletes the content of the logical cluster matching the label selector, e.g. to reset
+// ephemeral resources of a workspace that survives. It uses the same discovery, filtering and phases as
+// Delete, but never changes the conditions or finalizers of the logical cluster. It returns a
+// ResourcesRemainingError if selected content is still being deleted.
+func (d *logicalClusterResourcesDeleter) DeleteSelected(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, selector labels.Selector) error {
+	ctx, logger := withLogicalClusterLogger(ctx, logicalCluster)
+	logger = logger.WithValues("operation", "deleteSelected", "selector", selector.String())
+	logger.V(5).Info("running operation")
+	clusterName := logicalcluster.From(logicalCluster)
+
+	var errs []error
+	resources, err := d.discoverResources(clusterName)
+	if isLogicalClusterGone(err) {
+		return nil
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// a scoped copy of the deleter. Settled resources are tracked for full deletions only. Namespaced
+	// content does not go away with its namespace here, it is selected in all namespaces.
+	selected := *d
+	selected.labelSelector = selector.String()
+	selected.namespacedResources = namespacedGroupVersionResources(resources)
+	selected.namespace = metav1.NamespaceAll
+	selected.settled = nil
+	// remaining content counted from informer caches is only confirmed by full deletions.
+	selected.listerFor = nil
+	groupVersionResources, err := selected.deletableGroupVersionResources(resources)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	clusterDeletedAt := metav1.NewTime(d.clock.Now())
+	if logicalCluster.DeletionTimestamp != nil {
+		clusterDeletedAt = *logicalCluster.DeletionTimestamp
+	}
+
+	var remaining contentRemaining
+	if len(errs) == 0 {
+		remaining, err = selected.deleteByPhase(ctx, clusterName, groupVersionResources, clusterDeletedAt)
+		if ctx.Err() != nil {
+			return fmt.Errorf("selected content deletion of logical cluster %s interrupted: %w", clusterName, ctx.Err())
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	if remaining.numRemaining > 0 {
+		return remaining.remainingError(fmt.Sprintf("%d selected resource instances remaining", remaining.numRemaining))
+	}
+	return nil
+}
+
+// deleteByPhase deletes the content of gvrs phase by phase, not starting a phase before the earlier
+// ones are complete. It returns the finalizer estimate in seconds and the instances remaining of the
+// last started phase.
+func (d *logicalClusterResourcesDeleter) deleteByPhase(ctx context.Context, clusterName logicalcluster.Name, gvrs map[schema.GroupVersionResource]sets.String, clusterDeletedAt metav1.Time) (contentRemaining, error) {
+	var errs []error
+	remaining := contentRemaining{byResource: map[schema.GroupVersionResource]int{}, byNamespace: map[string]int{}}
+	self, err := d.selfDependencies(ctx, clusterName)
+	if err != nil {
+		return remaining, fmt.Errorf("failed to look up the objects the deleter depends on: %w", err)
+	}
+	for _, phase := range groupByDeletionPhase(gvrs, self) {
+		if remaining.numRemaining > 0 || len(errs) > 0 {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return remaining, err
+		}
+		for _, result := range d.deleteAllContentForPhase(ctx, clusterName, phase, gvrs, clusterDeletedAt) {
+			if result.err != nil {
+				errs = append(errs, result.err)
+			}
+			if result.metadata.finalizerEstimateSeconds > remaining.estimate {
+				remaining.estimate = result.metadata.finalizerEstimateSeconds
+			}
+			if result.metadata.numRemaining > 0 {
+				remaining.numRemaining += result.metadata.numRemaining
+				remaining.byResource[result.gvr] += result.metadata.numRemaining
+			}
+			remaining.externallyOwned += result.metadata.numExternallyOwned
+			remaining.retained += result.metadata.numRetained
+			for namespace, numRemaining := range result.metadata.namespacesToNumRemaining {
+				remaining.byNamespace[namespace] += numRemaining
+			}
+		}
+	}
+	return remaining, utilerrors.NewAggregate(errs)
+}
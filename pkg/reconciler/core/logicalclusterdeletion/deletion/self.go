@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	serviceAccountsGroupResource     = schema.GroupResource{Resource: "serviceaccounts"}
+	secretsGroupResource             = schema.GroupResource{Resource: "secrets"}
+	rolesGroupResource               = schema.GroupResource{Group: rbac.GroupName, Resource: "roles"}
+	roleBindingsGroupResource        = schema.GroupResource{Group: rbac.GroupName, Resource: "rolebindings"}
+	clusterRolesGroupResource        = schema.GroupResource{Group: rbac.GroupName, Resource: "clusterroles"}
+	clusterRoleBindingsGroupResource = schema.GroupResource{Group: rbac.GroupName, Resource: "clusterrolebindings"}
+)
+
+// selfDependencies returns the resources holding objects the deleter depends on to access the logical
+// cluster as its service account: the service account itself, its token secrets, the bindings granting
+// it access and the roles they refer to. It returns nil without a self identity.
+func (d *logicalClusterResourcesDeleter) selfDependencies(ctx context.Context, clusterName logicalcluster.Name) (map[schema.GroupResource]bool, error) {
+	if d.selfServiceAccount == nil {
+		return nil, nil
+	}
+	sa := *d.selfServiceAccount
+	client := d.kubeClusterClient.Cluster(clusterName.Path())
+	deps := map[schema.GroupResource]bool{}
+
+	serviceAccount, err := client.CoreV1().ServiceAccounts(sa.Namespace).Get(ctx, sa.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		deps[serviceAccountsGroupResource] = true
+		if len(serviceAccount.Secrets) > 0 {
+			deps[secretsGroupResource] = true
+		}
+	}
+
+	secrets, err := client.CoreV1().Secrets(sa.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, secret := range secrets.Items {
+			if secret.Type == corev1.SecretTypeServiceAccountToken && secret.Annotations[corev1.ServiceAccountNameKey] == sa.Name {
+				deps[secretsGroupResource] = true
+				break
+			}
+		}
+	}
+
+	roleBindings, err := client.RbacV1().RoleBindings(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, binding := range roleBindings.Items {
+			if bindsServiceAccount(binding.Subjects, sa) {
+				deps[roleBindingsGroupResource] = true
+				deps[roleRefGroupResource(binding.RoleRef)] = true
+			}
+		}
+	}
+
+	clusterRoleBindings, err := client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		for _, binding := range clusterRoleBindings.Items {
+			if bindsServiceAccount(binding.Subjects, sa) {
+				deps[clusterRoleBindingsGroupResource] = true
+				deps[roleRefGroupResource(binding.RoleRef)] = true
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+// bindsServiceAccount returns true if the subjects include the service account.
+func bindsServiceAccount(subjects []rbac.Subject, sa types.NamespacedName) bool {
+	for _, subject := range subjects {
+		if subject.Kind == rbac.ServiceAccountKind && subject.Name == sa.Name && subject.Namespace == sa.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// roleRefGroupResource returns the resource of the role a binding refers to.
+func roleRefGroupResource(ref rbac.RoleRef) schema.GroupResource {
+	if ref.Kind == "Role" {
+		return rolesGroupResource
+	}
+	return clusterRolesGroupResource
+}
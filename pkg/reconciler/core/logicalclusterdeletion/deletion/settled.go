@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// settledTracker remembers resources that were found empty in a number of consecutive
+// deletion passes of a logical cluster. Settled resources are skipped in later passes
+// until the resync period has elapsed, so that long teardowns only work on resources
+// that are still draining.
+type settledTracker struct {
+	// passes is the number of consecutive empty passes after which a resource is settled.
+	passes int
+	// resync is the duration after which a settled resource is checked again.
+	resync time.Duration
+
+	lock     sync.Mutex
+	clusters map[logicalcluster.Name]map[schema.GroupVersionResource]*settledState
+}
+
+type settledState struct {
+	emptyPasses int
+	settledAt   time.Time
+}
+
+func newSettledTracker(passes int, resync time.Duration) *settledTracker {
+	return &settledTracker{
+		passes:   passes,
+		resync:   resync,
+		clusters: map[logicalcluster.Name]map[schema.GroupVersionResource]*settledState{},
+	}
+}
+
+// isSettled returns true if gvr has been found empty often enough to be skipped now.
+func (t *settledTracker) isSettled(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	state, ok := t.clusters[clusterName][gvr]
+	if !ok || state.settledAt.IsZero() {
+		return false
+	}
+	if t.resync > 0 && now.Sub(state.settledAt) >= t.resync {
+		// check again, and settle again after another series of empty passes.
+		delete(t.clusters[clusterName], gvr)
+		return false
+	}
+	return true
+}
+
+// observe records the outcome of a pass for gvr.
+func (t *settledTracker) observe(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, empty bool, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !empty {
+		delete(t.clusters[clusterName], gvr)
+		return
+	}
+
+	gvrs, ok := t.clusters[clusterName]
+	if !ok {
+		gvrs = map[schema.GroupVersionResource]*settledState{}
+		t.clusters[clusterName] = gvrs
+	}
+	state, ok := gvrs[gvr]
+	if !ok {
+		state = &settledState{}
+		gvrs[gvr] = state
+	}
+	state.emptyPasses++
+	if state.emptyPasses >= t.passes && state.settledAt.IsZero() {
+		state.settledAt = now
+	}
+}
+
+// forget drops all state of the given logical cluster.
+func (t *settledTracker) forget(clusterName logicalcluster.Name) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.clusters, clusterName)
+}
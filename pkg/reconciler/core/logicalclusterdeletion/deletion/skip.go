@@ -0,0 +1,42 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+)
+
+// SkipContentDeletionAnnotationKey set to "true" on a LogicalCluster preserves its content when it is
+// deleted, e.g. because the content was migrated elsewhere. The content is reported deleted without
+// deleting anything.
+const SkipContentDeletionAnnotationKey = "deletion.kcp.io/skip-content"
+
+// skipContentDeletion reports the content of the logical cluster as deleted without deleting it, and
+// returns true, if SkipContentDeletionAnnotationKey is set.
+func skipContentDeletion(logicalCluster *corev1alpha1.LogicalCluster) bool {
+	if logicalCluster.Annotations[SkipContentDeletionAnnotationKey] != "true" {
+		return false
+	}
+	setDeletionConditions(logicalCluster, corev1.ConditionTrue, "ContentDeletionSkipped", conditionsv1alpha1.ConditionSeverityNone,
+		fmt.Sprintf("Content deletion was skipped by the %s annotation, the content was not deleted", SkipContentDeletionAnnotationKey))
+	return true
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// RemainingSnapshot is the content remaining in a logical cluster after a deletion pass. Callers keep
+// it between passes to detect deletions that make no progress.
+type RemainingSnapshot struct {
+	// Remaining is the number of remaining instances by resource.
+	Remaining map[schema.GroupVersionResource]int
+	// StalledPasses is the number of consecutive passes in which no remaining count decreased.
+	StalledPasses int
+}
+
+// ObserveProgress compares the remaining content of a deletion pass against the previous snapshot,
+// and returns the new snapshot. Once no remaining count has decreased for stalledAfter consecutive passes,
+// the WorkspaceDeletionStalled condition is set on the LogicalCluster, naming the remaining resources.
+// The condition is removed as soon as the deletion makes progress again.
+func ObserveProgress(logicalCluster *corev1alpha1.LogicalCluster, previous RemainingSnapshot, remaining map[schema.GroupVersionResource]int, stalledAfter int) RemainingSnapshot {
+	current := RemainingSnapshot{Remaining: remaining}
+	if len(remaining) > 0 && !madeProgress(previous.Remaining, remaining) {
+		current.StalledPasses = previous.StalledPasses + 1
+	}
+
+	if current.StalledPasses == 0 || current.StalledPasses < stalledAfter {
+		conditions.Delete(logicalCluster, tenancyv1alpha1.WorkspaceDeletionStalled)
+		return current
+	}
+
+	stalled := make([]string, 0, len(remaining))
+	for gvr, n := range remaining {
+		stalled = append(stalled, fmt.Sprintf("%s.%s has %d resource instances", gvr.Resource, gvr.Group, n))
+	}
+	// sort for stable updates
+	sort.Strings(stalled)
+	conditions.Set(logicalCluster, &conditionsv1alpha1.Condition{
+		Type:    tenancyv1alpha1.WorkspaceDeletionStalled,
+		Status:  corev1.ConditionTrue,
+		Reason:  "NoProgress",
+		Message: fmt.Sprintf("No progress in %d deletion passes: %s", current.StalledPasses, strings.Join(stalled, ", ")),
+	})
+	return current
+}
+
+// madeProgress returns true if any resource of previous has fewer or no instances remaining.
+// Without a previous snapshot, there is nothing to compare against.
+func madeProgress(previous, current map[schema.GroupVersionResource]int) bool {
+	if previous == nil {
+		return true
+	}
+	for gvr, n := range previous {
+		if current[gvr] < n {
+			return true
+		}
+	}
+	return false
+}
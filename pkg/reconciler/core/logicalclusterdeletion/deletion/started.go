@@ -0,0 +1,57 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"time"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// ContentDeletionStartedAnnotationKey records when the first content deletion pass of a LogicalCluster
+// started, in RFC 3339 format. It can be later than the DeletionTimestamp, e.g. when the deletion
+// waited for other finalizers or for the controller to catch up.
+const ContentDeletionStartedAnnotationKey = "deletion.kcp.io/content-deletion-started"
+
+// markContentDeletionStarted sets ContentDeletionStartedAnnotationKey to the current time, unless an
+// earlier pass already did.
+func (d *logicalClusterResourcesDeleter) markContentDeletionStarted(logicalCluster *corev1alpha1.LogicalCluster) {
+	if _, ok := logicalCluster.Annotations[ContentDeletionStartedAnnotationKey]; ok {
+		return
+	}
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[ContentDeletionStartedAnnotationKey] = d.clock.Now().UTC().Format(time.RFC3339)
+}
+
+// ContentDeletionDuration returns how long the content deletion of the given LogicalCluster took, from
+// the start of the first pass until the WorkspaceContentDeleted condition became True. It returns false
+// if the content deletion has not completed, or its start was not recorded.
+func ContentDeletionDuration(logicalCluster *corev1alpha1.LogicalCluster) (time.Duration, bool) {
+	condition := conditions.Get(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted)
+	if condition == nil || !conditions.IsTrue(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted) {
+		return 0, false
+	}
+	started, err := time.Parse(time.RFC3339, logicalCluster.Annotations[ContentDeletionStartedAnnotationKey])
+	if err != nil {
+		return 0, false
+	}
+	return condition.LastTransitionTime.Sub(started), true
+}
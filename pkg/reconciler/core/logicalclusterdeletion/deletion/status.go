@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+)
+
+// DeletionStatus is the state of the content deletion of a logical cluster.
+type DeletionStatus string
+
+const (
+	// DeletionNotStarted means the logical cluster is not being deleted, or no deletion pass has completed yet.
+	DeletionNotStarted DeletionStatus = "NotStarted"
+	// DeletionInProgress means content is still going away, or the last pass was interrupted.
+	DeletionInProgress DeletionStatus = "InProgress"
+	// DeletionFailed means the last pass could not delete all content and needs attention.
+	DeletionFailed DeletionStatus = "Failed"
+	// DeletionCompleted means all content of the logical cluster has been deleted.
+	DeletionCompleted DeletionStatus = "Completed"
+)
+
+// DeletionStatusOf derives the state of the content deletion of the given LogicalCluster from its
+// WorkspaceContentDeleted condition, such that callers need not interpret the condition themselves.
+// Failures that resolve on their own, e.g. content waiting for finalizers, are reported as in progress.
+func DeletionStatusOf(logicalCluster *corev1alpha1.LogicalCluster) DeletionStatus {
+	if logicalCluster.DeletionTimestamp.IsZero() {
+		return DeletionNotStarted
+	}
+	condition := conditions.Get(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted)
+	if condition == nil {
+		return DeletionNotStarted
+	}
+	switch condition.Status {
+	case corev1.ConditionTrue:
+		return DeletionCompleted
+	case corev1.ConditionFalse:
+		if condition.Severity == conditionsv1alpha1.ConditionSeverityInfo {
+			return DeletionInProgress
+		}
+		return DeletionFailed
+	default:
+		return DeletionInProgress
+	}
+}
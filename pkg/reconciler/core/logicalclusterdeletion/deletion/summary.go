@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// DeletionSummaryAnnotationKey accumulates the number of deleted instances by resource across the content
+// deletion passes of a LogicalCluster, as a JSON object keyed by resource.group. It is only maintained if
+// the deletion summary is enabled, and removed once the content is deleted.
+const DeletionSummaryAnnotationKey = "internal.core.kcp.io/deletion-summary"
+
+// deletedInstances returns the number of instances deleted in a pass for the given result, if known.
+func deletedInstances(result gvrDeletionResult) int {
+	kept := result.metadata.numRemaining + result.metadata.numVetoed
+	if result.err != nil || result.metadata.numFound <= kept {
+		return 0
+	}
+	return result.metadata.numFound - kept
+}
+
+// deletionSummary returns the deleted instances by resource recorded on the logical cluster. An invalid
+// annotation is ignored, the summary starts over.
+func deletionSummary(logicalCluster *corev1alpha1.LogicalCluster) map[string]int {
+	summary := map[string]int{}
+	if value, ok := logicalCluster.Annotations[DeletionSummaryAnnotationKey]; ok {
+		if err := json.Unmarshal([]byte(value), &summary); err != nil {
+			return map[string]int{}
+		}
+	}
+	return summary
+}
+
+// addToDeletionSummary adds the instances deleted in a pass to the summary recorded on the logical cluster.
+func addToDeletionSummary(logicalCluster *corev1alpha1.LogicalCluster, deleted map[string]int) {
+	if len(deleted) == 0 {
+		return
+	}
+	summary := deletionSummary(logicalCluster)
+	for resource, n := range deleted {
+		summary[resource] += n
+	}
+	value, err := json.Marshal(summary)
+	if err != nil {
+		return
+	}
+	if logicalCluster.Annotations == nil {
+		logicalCluster.Annotations = map[string]string{}
+	}
+	logicalCluster.Annotations[DeletionSummaryAnnotationKey] = string(value)
+}
+
+// completeDeletionSummary removes the summary from the logical cluster and returns it as a message.
+func completeDeletionSummary(logicalCluster *corev1alpha1.LogicalCluster) string {
+	summary := deletionSummary(logicalCluster)
+	delete(logicalCluster.Annotations, DeletionSummaryAnnotationKey)
+	total := 0
+	for _, n := range summary {
+		total += n
+	}
+	return fmt.Sprintf("Deleted %d resource instances of %d resources", total, len(summary))
+}
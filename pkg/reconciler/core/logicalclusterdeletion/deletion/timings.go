@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ResourceTiming is the wall-clock time spent deleting the content of a resource.
+type ResourceTiming struct {
+	GVR      schema.GroupVersionResource
+	Duration time.Duration
+}
+
+// ResourceTimings accumulates the wall-clock time spent deleting the content per resource and
+// logical cluster across deletion passes, to attribute slow deletions during an incident. Entries
+// are kept until Forget is called. It is safe for concurrent use.
+type ResourceTimings struct {
+	lock      sync.Mutex
+	durations map[logicalcluster.Name]map[schema.GroupVersionResource]time.Duration
+}
+
+// NewResourceTimings returns empty ResourceTimings.
+func NewResourceTimings() *ResourceTimings {
+	return &ResourceTimings{
+		durations: map[logicalcluster.Name]map[schema.GroupVersionResource]time.Duration{},
+	}
+}
+
+// Durations returns a copy of the accumulated durations by resource for the given logical cluster.
+func (t *ResourceTimings) Durations(clusterName logicalcluster.Name) map[schema.GroupVersionResource]time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	ret := make(map[schema.GroupVersionResource]time.Duration, len(t.durations[clusterName]))
+	for gvr, d := range t.durations[clusterName] {
+		ret[gvr] = d
+	}
+	return ret
+}
+
+// Slowest returns up to n resources of the given logical cluster with the longest accumulated
+// durations, slowest first.
+func (t *ResourceTimings) Slowest(clusterName logicalcluster.Name, n int) []ResourceTiming {
+	durations := t.Durations(clusterName)
+	ret := make([]ResourceTiming, 0, len(durations))
+	for gvr, d := range durations {
+		ret = append(ret, ResourceTiming{GVR: gvr, Duration: d})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Duration != ret[j].Duration {
+			return ret[i].Duration > ret[j].Duration
+		}
+		return ret[i].GVR.String() < ret[j].GVR.String()
+	})
+	if n >= 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
+
+// Forget drops the durations of the given logical cluster.
+func (t *ResourceTimings) Forget(clusterName logicalcluster.Name) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.durations, clusterName)
+}
+
+func (t *ResourceTimings) add(clusterName logicalcluster.Name, gvr schema.GroupVersionResource, d time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.durations[clusterName] == nil {
+		t.durations[clusterName] = map[schema.GroupVersionResource]time.Duration{}
+	}
+	t.durations[clusterName][gvr] += d
+}
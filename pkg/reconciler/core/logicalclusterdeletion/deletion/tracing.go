@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// tracerName is the instrumentation name of the spans of the deleter.
+const tracerName = "github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+
+const (
+	// contentDeletionSpanName is the name of the span of a content deletion pass.
+	contentDeletionSpanName = "WorkspaceContentDeletion"
+	// deleteCollectionSpanName is the name of the span of the deletion of the content of one resource.
+	deleteCollectionSpanName = "DeleteCollection"
+)
+
+// tracer returns the tracer of the span in ctx if it is recording, such that the spans of the deleter
+// become its children, and otherwise a tracer of the configured provider.
+func (d *logicalClusterResourcesDeleter) tracer(ctx context.Context) trace.Tracer {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		return span.Tracer()
+	}
+	return d.tracerProvider.Tracer(tracerName)
+}
+
+// startContentDeletionSpan starts the span of a content deletion pass of the given logical cluster.
+func (d *logicalClusterResourcesDeleter) startContentDeletionSpan(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) (context.Context, trace.Span) {
+	return d.tracer(ctx).Start(ctx, contentDeletionSpanName, trace.WithAttributes(
+		attribute.String("logicalCluster", logicalcluster.From(logicalCluster).String()),
+		attribute.String("workspace", logicalCluster.Annotations[core.LogicalClusterPathAnnotationKey]),
+	))
+}
+
+// startDeleteCollectionSpan starts the span of the deletion of the content of gvr.
+func (d *logicalClusterResourcesDeleter) startDeleteCollectionSpan(ctx context.Context, gvr schema.GroupVersionResource) (context.Context, trace.Span) {
+	return d.tracer(ctx).Start(ctx, deleteCollectionSpanName, trace.WithAttributes(
+		attribute.String("gvr", gvr.String()),
+	))
+}
+
+// endDeleteCollectionSpan records the instances found and remaining of a resource, and the error if
+// any, and ends its span.
+func endDeleteCollectionSpan(span trace.Span, metadata gvrDeletionMetadata, err error) {
+	span.SetAttributes(
+		attribute.Int("found", metadata.numFound),
+		attribute.Int("remaining", metadata.numRemaining),
+	)
+	endSpan(span, err)
+}
+
+// endSpan records the error if any on span, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
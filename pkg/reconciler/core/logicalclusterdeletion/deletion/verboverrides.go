@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// applyVerbOverrides returns the discovered resources with the verbs of overridden resources replaced.
+// The resources passed in are not modified.
+func (d *logicalClusterResourcesDeleter) applyVerbOverrides(resources []*metav1.APIResourceList) []*metav1.APIResourceList {
+	if len(d.verbOverrides) == 0 {
+		return resources
+	}
+	ret := make([]*metav1.APIResourceList, 0, len(resources))
+	for _, rl := range resources {
+		gv, err := schema.ParseGroupVersion(rl.GroupVersion)
+		if err != nil {
+			// reported when the resources are grouped.
+			ret = append(ret, rl)
+			continue
+		}
+		var overridden *metav1.APIResourceList
+		for i := range rl.APIResources {
+			verbs, ok := d.verbOverrides[gv.WithResource(rl.APIResources[i].Name)]
+			if !ok {
+				continue
+			}
+			if overridden == nil {
+				overridden = rl.DeepCopy()
+			}
+			overridden.APIResources[i].Verbs = append(metav1.Verbs(nil), verbs...)
+		}
+		if overridden == nil {
+			overridden = rl
+		}
+		ret = append(ret, overridden)
+	}
+	return ret
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// partitionVetoed splits items into those to delete and those vetoed by shouldDeleteObject.
+func (d *logicalClusterResourcesDeleter) partitionVetoed(gvr schema.GroupVersionResource, items []metav1.PartialObjectMetadata) (eligible, vetoed []metav1.PartialObjectMetadata) {
+	if d.shouldDeleteObject == nil {
+		return items, nil
+	}
+	for i := range items {
+		if d.shouldDeleteObject(gvr, &items[i]) {
+			eligible = append(eligible, items[i])
+		} else {
+			vetoed = append(vetoed, items[i])
+		}
+	}
+	return eligible, vetoed
+}
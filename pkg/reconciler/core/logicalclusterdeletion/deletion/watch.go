@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/klog/v2"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+// completionPollInterval is how often resources that cannot be watched are listed while waiting for
+// their instances to be gone.
+const completionPollInterval = 5 * time.Second
+
+// DeletionProgress is the number of instances of a resource remaining while waiting for the content
+// of a logical cluster to be deleted.
+type DeletionProgress struct {
+	GVR       schema.GroupVersionResource
+	Remaining int
+}
+
+// WatchUntilDeleted blocks until no instances of the deletable resources of the logical cluster remain,
+// or ctx is done. It does not delete anything itself. Resources are watched, or polled if they do not
+// support watch, and their remaining instances are sent on progress whenever they change. progress is
+// closed when WatchUntilDeleted returns, and can be nil. Resources that do not support list are skipped.
+func (d *logicalClusterResourcesDeleter) WatchUntilDeleted(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster, progress chan<- DeletionProgress) error {
+	if progress != nil {
+		defer close(progress)
+	}
+	ctx, _ = withLogicalClusterLogger(ctx, logicalCluster)
+	clusterName := logicalcluster.From(logicalCluster)
+
+	resources, err := d.discoverResources(clusterName)
+	if isLogicalClusterGone(err) {
+		return nil
+	}
+	if err != nil {
+		return &DiscoveryFailedError{Err: err}
+	}
+	groupVersionResources, err := d.deletableGroupVersionResources(resources)
+	if err != nil {
+		return &DiscoveryFailedError{Err: err}
+	}
+
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan error, len(groupVersionResources))
+	for gvr, verbs := range groupVersionResources {
+		go func(gvr schema.GroupVersionResource, verbs sets.String) {
+			results <- d.waitUntilEmpty(waitCtx, clusterName, gvr, verbs, progress)
+		}(gvr, verbs)
+	}
+
+	var errs []error
+	for range groupVersionResources {
+		err := <-results
+		if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			continue
+		}
+		// the other resources are not waited for anymore.
+		errs = append(errs, err)
+		cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// waitUntilEmpty blocks until no instances of gvr remain, watching them if supported and polling
+// otherwise.
+func (d *logicalClusterResourcesDeleter) waitUntilEmpty(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, verbs sets.String, progress chan<- DeletionProgress) error {
+	logger := klog.FromContext(ctx).WithValues("operation", "waitUntilEmpty", "gvr", gvr)
+	watchSupported := verbs.Has(string(operationWatch))
+	for {
+		list, listSupported, err := d.listCollection(ctx, clusterName, gvr, verbs)
+		if err != nil {
+			return err
+		}
+		if !listSupported {
+			logger.V(5).Info("skipping resource not supporting list")
+			return nil
+		}
+		remaining := sets.NewString()
+		for _, item := range list.Items {
+			remaining.Insert(item.Namespace + "/" + item.Name)
+		}
+		resourceVersion := list.ResourceVersion
+		d.releaseList(list)
+		if !sendProgress(ctx, progress, DeletionProgress{GVR: gvr, Remaining: remaining.Len()}) {
+			return ctx.Err()
+		}
+		if remaining.Len() == 0 {
+			return nil
+		}
+
+		if watchSupported {
+			empty, err := d.watchUntilEmpty(ctx, clusterName, gvr, resourceVersion, remaining, progress)
+			if empty {
+				return nil
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err == nil {
+				// the watch ended, e.g. because it timed out. List again to catch up.
+				continue
+			}
+			if apierrors.IsMethodNotSupported(err) {
+				watchSupported = false
+			}
+			logger.V(4).Info("watch failed, polling instead", "err", err.Error())
+		}
+		if err := d.waitFor(ctx, completionPollInterval); err != nil {
+			return err
+		}
+	}
+}
+
+// watchUntilEmpty watches gvr from the given resource version and tracks the remaining instances by
+// namespace and name. It returns true once none remain, and false if the watch ended before.
+func (d *logicalClusterResourcesDeleter) watchUntilEmpty(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, resourceVersion string, remaining sets.String, progress chan<- DeletionProgress) (bool, error) {
+	opts := d.listOptions(gvr)
+	opts.ResourceVersion = resourceVersion
+	w, err := d.resourceClient(clusterName, gvr).Namespace(d.namespaceOf(gvr)).Watch(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			switch event.Type {
+			case watch.Added, watch.Modified, watch.Deleted:
+			case watch.Error:
+				return false, apierrors.FromObject(event.Object)
+			default:
+				continue
+			}
+			obj, err := meta.Accessor(event.Object)
+			if err != nil {
+				continue
+			}
+			key := obj.GetNamespace() + "/" + obj.GetName()
+			before := remaining.Len()
+			if event.Type == watch.Deleted {
+				remaining.Delete(key)
+			} else {
+				remaining.Insert(key)
+			}
+			if remaining.Len() == before {
+				continue
+			}
+			if !sendProgress(ctx, progress, DeletionProgress{GVR: gvr, Remaining: remaining.Len()}) {
+				return false, ctx.Err()
+			}
+			if remaining.Len() == 0 {
+				return true, nil
+			}
+		}
+	}
+}
+
+// sendProgress sends p on progress unless it is nil. It returns false if ctx is done before.
+func sendProgress(ctx context.Context, progress chan<- DeletionProgress, p DeletionProgress) bool {
+	if progress == nil {
+		return true
+	}
+	select {
+	case progress <- p:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletion
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// gvrDeletionResult is the outcome of deleting the content of one resource in a deletion pass.
+type gvrDeletionResult struct {
+	gvr      schema.GroupVersionResource
+	metadata gvrDeletionMetadata
+	err      error
+	// settled is true if gvr was skipped because it settled.
+	settled bool
+	// deferredBy are the resources with remaining instances gvr was not deleted for in this pass.
+	deferredBy []schema.GroupVersionResource
+}
+
+// deleteAllContentForPhase deletes the content of all resources of a deletion phase. CRDs are deleted
+// last, once the custom resources they define are gone. The results of the other resources are
+// returned in the order of phase, followed by those of the CRDs.
+func (d *logicalClusterResourcesDeleter) deleteAllContentForPhase(
+	ctx context.Context,
+	clusterName logicalcluster.Name,
+	phase []schema.GroupVersionResource,
+	groupVersionResources map[schema.GroupVersionResource]sets.String,
+	clusterDeletedAt metav1.Time,
+) []gvrDeletionResult {
+	resources, crds := splitCustomResourceDefinitions(phase)
+	results := d.deleteResources(ctx, clusterName, resources, groupVersionResources, clusterDeletedAt)
+	if len(crds) == 0 || ctx.Err() != nil {
+		return results
+	}
+	return append(results, d.deleteCustomResourceDefinitions(ctx, clusterName, crds, results, groupVersionResources, clusterDeletedAt)...)
+}
+
+// deleteResources deletes the content of the given resources, fanned out across the configured
+// number of workers. The results are returned in the order of phase, independent of the order the
+// workers complete in. Once ctx is done, the remaining resources are skipped and their results left
+// empty.
+func (d *logicalClusterResourcesDeleter) deleteResources(
+	ctx context.Context,
+	clusterName logicalcluster.Name,
+	phase []schema.GroupVersionResource,
+	groupVersionResources map[schema.GroupVersionResource]sets.String,
+	clusterDeletedAt metav1.Time,
+) []gvrDeletionResult {
+	results := make([]gvrDeletionResult, len(phase))
+
+	workers := d.workerCount
+	if workers > len(phase) {
+		workers = len(phase)
+	}
+	if workers <= 1 {
+		for i, gvr := range phase {
+			if ctx.Err() != nil {
+				break
+			}
+			results[i] = d.deleteAllContentForSettledGroupVersionResource(ctx, clusterName, gvr, groupVersionResources[gvr], clusterDeletedAt)
+		}
+		return results
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i] = d.deleteAllContentForSettledGroupVersionResource(ctx, clusterName, phase[i], groupVersionResources[phase[i]], clusterDeletedAt)
+			}
+		}()
+	}
+	for i := range phase {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// deleteAllContentForSettledGroupVersionResource deletes the content of gvr unless it has settled.
+func (d *logicalClusterResourcesDeleter) deleteAllContentForSettledGroupVersionResource(
+	ctx context.Context,
+	clusterName logicalcluster.Name,
+	gvr schema.GroupVersionResource,
+	verbs sets.String,
+	clusterDeletedAt metav1.Time,
+) gvrDeletionResult {
+	if d.settled != nil && d.settled.isSettled(clusterName, gvr, d.clock.Now()) {
+		klog.FromContext(ctx).V(5).Info("skipping settled resource", "gvr", gvr)
+		return gvrDeletionResult{gvr: gvr, metadata: gvrDeletionMetadata{numFound: -1}, settled: true}
+	}
+	start := d.clock.Now()
+	spanCtx, span := d.startDeleteCollectionSpan(ctx, gvr)
+	gvrDeletionMetadata, err := d.deleteAllContentForGroupVersionResource(spanCtx, clusterName, gvr, verbs, clusterDeletedAt)
+	endDeleteCollectionSpan(span, gvrDeletionMetadata, err)
+	d.observeDuration(ctx, clusterName, gvr, d.clock.Since(start))
+	if d.settled != nil {
+		empty := err == nil && gvrDeletionMetadata.numRemaining == 0 && gvrDeletionMetadata.finalizerEstimateSeconds == 0
+		d.settled.observe(clusterName, gvr, empty, d.clock.Now())
+	}
+	return gvrDeletionResult{gvr: gvr, metadata: gvrDeletionMetadata, err: err}
+}
+
+// observeDuration accumulates the duration of the content deletion of gvr and logs it if slow.
+func (d *logicalClusterResourcesDeleter) observeDuration(ctx context.Context, clusterName logicalcluster.Name, gvr schema.GroupVersionResource, duration time.Duration) {
+	if d.timings != nil {
+		d.timings.add(clusterName, gvr, duration)
+	}
+	if d.slowResourceThreshold > 0 && duration > d.slowResourceThreshold {
+		klog.FromContext(ctx).V(2).Info("slow resource deletion", "gvr", gvr, "duration", duration, "threshold", d.slowResourceThreshold)
+	}
+}
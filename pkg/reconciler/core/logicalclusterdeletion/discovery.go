@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	kcpapiextensionsv1informers "k8s.io/apiextensions-apiserver/pkg/client/kcp/informers/externalversions/apiextensions/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+)
+
+// WithDiscoveryCache invalidates the discovery cached for a logical cluster whenever a CRD or an APIBinding
+// in it changes, as both change the resources served. The cached discovery of a logical cluster is forgotten
+// once it is finalized or gone. The cache is expected to serve the discovery function of the deleter.
+func WithDiscoveryCache(
+	discoveryCache *deletion.CachedDiscovery,
+	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
+	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+) Option {
+	return func(c *Controller) {
+		c.discoveryCache = discoveryCache
+		handler := cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.invalidateDiscovery,
+			UpdateFunc: func(_, obj interface{}) { c.invalidateDiscovery(obj) },
+			DeleteFunc: c.invalidateDiscovery,
+		}
+		crdInformer.Informer().AddEventHandler(handler)
+		apiBindingInformer.Informer().AddEventHandler(handler)
+	}
+}
+
+// invalidateDiscovery drops the discovery cached for the logical cluster of obj.
+func (c *Controller) invalidateDiscovery(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if o, ok := obj.(logicalcluster.Object); ok {
+		c.discoveryCache.Invalidate(logicalcluster.From(o).Path())
+	}
+}
+
+// forgetDiscovery drops the discovery cached for a logical cluster that is not deleted anymore.
+func (c *Controller) forgetDiscovery(clusterName logicalcluster.Name) {
+	if c.discoveryCache != nil {
+		c.discoveryCache.Forget(clusterName.Path())
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"context"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+)
+
+// ForceFinalizeAnnotationKey requests that the deletion finalizer is stripped from a terminating
+// LogicalCluster even though its content could not be deleted. The value must echo the name of the
+// logical cluster to confirm the intent, as content left behind is orphaned irreversibly.
+const ForceFinalizeAnnotationKey = deletion.ForceFinalizeAnnotationKey
+
+// forceFinalizeRequested returns true if the LogicalCluster carries the force-finalize annotation.
+func forceFinalizeRequested(lc *corev1alpha1.LogicalCluster) bool {
+	_, ok := lc.Annotations[ForceFinalizeAnnotationKey]
+	return ok
+}
+
+// forceFinalize removes the deletion finalizer without checking that the content has been deleted.
+func forceFinalize(_ context.Context, lc *corev1alpha1.LogicalCluster) error {
+	deletion.RemoveDeletionFinalizer(lc)
+	return nil
+}
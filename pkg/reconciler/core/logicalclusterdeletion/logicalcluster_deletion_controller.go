@@ -18,9 +18,11 @@ package logicalclusterdeletion
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
@@ -28,10 +30,13 @@ import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	kcpmetadata "github.com/kcp-dev/client-go/metadata"
 	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -68,6 +73,7 @@ func NewController(
 	metadataClusterClient kcpmetadata.ClusterInterface,
 	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
 	discoverResourcesFn func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error),
+	opts ...Option,
 ) *Controller {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
@@ -98,9 +104,20 @@ func NewController(
 		},
 	})
 
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	return c
 }
 
+// WithDeleter replaces the deleter of the content of terminating LogicalClusters, e.g. by a stub in tests.
+func WithDeleter(deleter deletion.WorkspaceResourcesDeleterInterface) Option {
+	return func(c *Controller) {
+		c.deleter = deleter
+	}
+}
+
 type LogicalCluster = corev1alpha1.LogicalCluster
 type LogicalClusterSpec = corev1alpha1.LogicalClusterSpec
 type LogicalClusterStatus = corev1alpha1.LogicalClusterStatus
@@ -125,6 +142,20 @@ type Controller struct {
 	deleter deletion.WorkspaceResourcesDeleterInterface
 
 	commit CommitFunc
+
+	// progress detects stalled content deletions.
+	progress deletionProgress
+
+	// active is the number of workers processing a key, capacity the number of workers started.
+	active   atomic.Int32
+	capacity atomic.Int32
+
+	// discoveryCache serves the discovery of the deleter. Nil if discovery is not cached.
+	discoveryCache *deletion.CachedDiscovery
+
+	// tracer traces the deletion of LogicalCluster content. Nil if tracing is disabled.
+	tracer    trace.Tracer
+	shardName string
 }
 
 func (c *Controller) enqueue(obj interface{}) {
@@ -136,6 +167,7 @@ func (c *Controller) enqueue(obj interface{}) {
 	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
 	logger.V(4).Info("queueing LogicalCluster")
 	c.queue.Add(key)
+	c.updateQueueDepth()
 }
 
 func (c *Controller) Start(ctx context.Context, numThreads int) {
@@ -158,6 +190,14 @@ func (c *Controller) Start(ctx context.Context, numThreads int) {
 	}
 	c.dynamicFrontProxyClient = dynamicFrontProxyClient
 
+	workerCapacity.Set(float64(numThreads))
+	defer workerCapacity.Set(0)
+	c.capacity.Store(int32(numThreads))
+	defer c.capacity.Store(0)
+
+	// keys requeued with a delay are added to the queue later, so the depth is refreshed periodically.
+	go wait.Until(c.updateQueueDepth, time.Second, ctx.Done())
+
 	for i := 0; i < numThreads; i++ {
 		go wait.Until(func() { c.startWorker(ctx) }, time.Second, ctx.Done())
 	}
@@ -178,6 +218,14 @@ func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	}
 	key := k.(string)
 
+	c.updateQueueDepth()
+	activeWorkers.Inc()
+	c.active.Add(1)
+	defer func() {
+		activeWorkers.Dec()
+		c.active.Add(-1)
+	}()
+
 	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
 	ctx = klog.NewContext(ctx, logger)
 	logger.V(4).Info("processing key")
@@ -199,6 +247,9 @@ func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	if errors.As(err, &estimate) {
 		t := estimate.Estimate/2 + 1
 		duration := time.Duration(t) * time.Second
+		if estimate.RetryAfter > 0 {
+			duration = estimate.RetryAfter
+		}
 		logger.V(2).Error(err, "content remaining in logical cluster after a wait, waiting more to continue", "duration", time.Since(startTime), "waiting", duration)
 
 		c.queue.AddAfter(key, duration)
@@ -207,6 +258,7 @@ func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 		c.queue.AddRateLimited(key)
 		runtime.HandleError(fmt.Errorf("deletion of logical cluster %v failed: %w", key, err))
 	}
+	c.updateQueueDepth()
 
 	return true
 }
@@ -221,6 +273,7 @@ func (c *Controller) process(ctx context.Context, key string) error {
 	logicalCluster, deleteErr := c.logicalClusterLister.Cluster(clusterName).Get(name)
 	if apierrors.IsNotFound(deleteErr) {
 		logger.V(2).Info("Workspace has been deleted")
+		c.forgetDiscovery(clusterName)
 		return nil
 	}
 	if deleteErr != nil {
@@ -239,29 +292,117 @@ func (c *Controller) process(ctx context.Context, key string) error {
 
 	logger.V(2).Info("deleting logical cluster")
 	startTime := time.Now()
-	deleteErr = c.deleter.Delete(ctx, logicalClusterCopy)
+	deleteErr = c.deleteContent(ctx, logicalClusterCopy)
 	if deleteErr == nil {
+		c.progress.forget(logicalcluster.From(logicalClusterCopy))
 		logger.V(2).Info("finished deleting logical cluster content", "duration", time.Since(startTime))
-		return c.finalizeWorkspace(ctx, logicalClusterCopy)
+		if err := c.finalizeWorkspace(ctx, logicalClusterCopy, c.deleter.FinalizeWorkspace); err != nil {
+			return err
+		}
+		c.forgetDiscovery(clusterName)
+		return nil
+	}
+
+	if forceFinalizeRequested(logicalClusterCopy) {
+		if err := deletion.ValidateForceFinalizeConfirmation(logicalClusterCopy); err != nil {
+			logger.Error(err, "refusing to force-finalize LogicalCluster")
+		} else {
+			logger.Info("force-finalizing LogicalCluster, remaining content is orphaned", "err", deleteErr)
+			if err := c.finalizeWorkspace(ctx, logicalClusterCopy, forceFinalize); err != nil {
+				return err
+			}
+			c.forgetDiscovery(clusterName)
+			return nil
+		}
+	}
+
+	var remaining *deletion.ResourcesRemainingError
+	if errors.As(deleteErr, &remaining) {
+		c.progress.observe(logicalClusterCopy, remaining.RemainingByResource)
 	}
 
 	errs := []error{deleteErr}
+	var exceeded *deletion.DeletionAttemptsExceededError
+	if errors.As(deleteErr, &exceeded) {
+		// requeuing does not help. The deletion resumes once the attempts annotation is removed.
+		logger.Error(deleteErr, "giving up deleting the content of the logical cluster")
+		errs = nil
+	}
+	if errors.Is(deleteErr, deletion.ErrDeletionCancelled) {
+		// requeuing does not help. The deletion resumes once the cancellation annotation is removed.
+		logger.V(2).Info("not deleting the content of the logical cluster", "reason", deleteErr.Error())
+		errs = nil
+	}
 
-	oldResource := &Resource{ObjectMeta: logicalCluster.ObjectMeta, Spec: &logicalCluster.Spec, Status: &logicalCluster.Status}
-	newResource := &Resource{ObjectMeta: logicalClusterCopy.ObjectMeta, Spec: &logicalClusterCopy.Spec, Status: &logicalClusterCopy.Status}
+	// the committer does not allow changing metadata and status at once. The status is committed
+	// first, as its patch is conditional on the resource version of the logical cluster. The deleter
+	// might have written the status already, and passes the resource version it wrote back.
+	objectMeta := *logicalCluster.ObjectMeta.DeepCopy()
+	objectMeta.ResourceVersion = logicalClusterCopy.ResourceVersion
+	oldResource := &Resource{ObjectMeta: objectMeta, Spec: &logicalCluster.Spec, Status: &logicalCluster.Status}
+	newResource := &Resource{ObjectMeta: objectMeta, Spec: &logicalClusterCopy.Spec, Status: &logicalClusterCopy.Status}
 	if err := c.commit(ctx, oldResource, newResource); err != nil {
 		errs = append(errs, err)
+	} else if err := c.commitAnnotations(ctx, logicalCluster, logicalClusterCopy); err != nil {
+		errs = append(errs, err)
 	}
 
 	return utilerrors.NewAggregate(errs)
 }
 
-// finalizeNamespace removes the specified finalizer and finalizes the logical cluster.
-func (c *Controller) finalizeWorkspace(ctx context.Context, ws *corev1alpha1.LogicalCluster) error {
+// commitAnnotations patches the annotations the deleter changed on the logical cluster, e.g. the count
+// of deletion attempts.
+func (c *Controller) commitAnnotations(ctx context.Context, old, obj *corev1alpha1.LogicalCluster) error {
+	changed := map[string]interface{}{}
+	for k, v := range obj.Annotations {
+		if oldValue, ok := old.Annotations[k]; !ok || oldValue != v {
+			changed[k] = v
+		}
+	}
+	for k := range old.Annotations {
+		if _, ok := obj.Annotations[k]; !ok {
+			changed[k] = nil
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": changed}})
+	if err != nil {
+		return err
+	}
+	_, err = c.kcpClusterClient.Cluster(logicalcluster.From(obj).Path()).CoreV1alpha1().LogicalClusters().Patch(ctx, obj.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// deleteContent runs the deleter, within a span carrying the deletion baggage if tracing is enabled.
+func (c *Controller) deleteContent(ctx context.Context, logicalCluster *corev1alpha1.LogicalCluster) error {
+	if c.tracer == nil {
+		return c.deleter.Delete(ctx, logicalCluster)
+	}
+
+	ctx = deletionBaggage(ctx, logicalCluster, c.shardName)
+	ctx, span := c.tracer.Start(ctx, "DeleteLogicalClusterContent")
+	defer span.End()
+
+	err := c.deleter.Delete(ctx, logicalCluster)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// finalizeWorkspace removes the deletion finalizer using removeFinalizer, cleans up after the logical cluster
+// and persists the removal.
+func (c *Controller) finalizeWorkspace(ctx context.Context, ws *corev1alpha1.LogicalCluster, removeFinalizer func(context.Context, *corev1alpha1.LogicalCluster) error) error {
 	logger := klog.FromContext(ctx)
 	for i := range ws.Finalizers {
 		if ws.Finalizers[i] == deletion.LogicalClusterDeletionFinalizer {
-			ws.Finalizers = append(ws.Finalizers[:i], ws.Finalizers[i+1:]...)
+			if err := removeFinalizer(ctx, ws); err != nil {
+				return err
+			}
 			clusterName := logicalcluster.From(ws)
 
 			// TODO(hasheddan): ClusterRole and ClusterRoleBinding cleanup
@@ -321,8 +462,13 @@ func (c *Controller) finalizeWorkspace(ctx context.Context, ws *corev1alpha1.Log
 			}
 
 			logger.V(2).Info("removing finalizer from LogicalCluster")
-			_, err := c.kcpClusterClient.CoreV1alpha1().LogicalClusters().Cluster(clusterName.Path()).Update(ctx, ws, metav1.UpdateOptions{})
-			return err
+			if _, err := c.kcpClusterClient.CoreV1alpha1().LogicalClusters().Cluster(clusterName.Path()).Update(ctx, ws, metav1.UpdateOptions{}); err != nil {
+				reason := finalizerRemovalErrorReason(err)
+				finalizerRemovalErrors.WithLabelValues(reason).Inc()
+				logger.Error(err, "failed to remove finalizer from LogicalCluster", "reason", reason)
+				return err
+			}
+			return nil
 		}
 	}
 
@@ -0,0 +1,541 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	kcpfakemetadata "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/metadata/fake"
+	kcptesting "github.com/kcp-dev/client-go/third_party/k8s.io/client-go/testing"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/testutil"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+	corev1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/core/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+)
+
+func TestFinalizeWorkspaceRemovalErrors(t *testing.T) {
+	now := metav1.Now()
+	newLogicalCluster := func() *corev1alpha1.LogicalCluster {
+		return &corev1alpha1.LogicalCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              corev1alpha1.LogicalClusterName,
+				DeletionTimestamp: &now,
+				Finalizers:        []string{deletion.LogicalClusterDeletionFinalizer},
+				Annotations:       map[string]string{logicalcluster.AnnotationKey: "root:test"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		updateErr   error
+		wantErr     bool
+		wantReason  string
+		wantCounted float64
+	}{
+		{
+			name:        "finalizer removed",
+			wantReason:  string(metav1.StatusReasonConflict),
+			wantCounted: 0,
+		},
+		{
+			name:        "conflict",
+			updateErr:   apierrors.NewConflict(schema.GroupResource{Group: "core.kcp.io", Resource: "logicalclusters"}, corev1alpha1.LogicalClusterName, errors.New("object was modified")),
+			wantErr:     true,
+			wantReason:  string(metav1.StatusReasonConflict),
+			wantCounted: 1,
+		},
+		{
+			name:        "unclassified error",
+			updateErr:   errors.New("connection refused"),
+			wantErr:     true,
+			wantReason:  "Unknown",
+			wantCounted: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := newLogicalCluster()
+			kcpClient := kcpfakeclient.NewSimpleClientset(lc)
+			if tt.updateErr != nil {
+				kcpClient.PrependReactor("update", "logicalclusters", func(action kcptesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.updateErr
+				})
+			}
+			c := &Controller{
+				kubeClusterClient: kcpfakekubeclient.NewSimpleClientset(),
+				kcpClusterClient:  kcpClient,
+			}
+
+			before, err := testutil.GetCounterMetricValue(finalizerRemovalErrors.WithLabelValues(tt.wantReason))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.finalizeWorkspace(context.Background(), lc, forceFinalize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			after, err := testutil.GetCounterMetricValue(finalizerRemovalErrors.WithLabelValues(tt.wantReason))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := after - before; got != tt.wantCounted {
+				t.Errorf("expected %v finalizer removal errors with reason %q, got %v", tt.wantCounted, tt.wantReason, got)
+			}
+		})
+	}
+}
+
+func TestProcessNextWorkItemSaturation(t *testing.T) {
+	c := &Controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test"),
+	}
+	defer c.queue.ShutDown()
+
+	// invalid keys are dropped without touching the lister.
+	c.queue.Add("a/b/c/d")
+	c.queue.Add("e/f/g/h")
+
+	if !c.processNextWorkItem(context.Background()) {
+		t.Fatal("expected the queue to be running")
+	}
+
+	depth, err := testutil.GetGaugeMetricValue(queueDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depth != 1 {
+		t.Errorf("expected queue depth 1, got %v", depth)
+	}
+	active, err := testutil.GetGaugeMetricValue(activeWorkers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active != 0 {
+		t.Errorf("expected no active workers after processing, got %v", active)
+	}
+}
+
+// fakeDeleter stubs Delete. Other methods are not implemented.
+type fakeDeleter struct {
+	deletion.WorkspaceResourcesDeleterInterface
+	delete func(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error
+}
+
+func (f fakeDeleter) Delete(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error {
+	return f.delete(ctx, cluster)
+}
+
+func TestWithDeleter(t *testing.T) {
+	var deleted []string
+	c := &Controller{}
+	WithDeleter(fakeDeleter{delete: func(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error {
+		deleted = append(deleted, logicalcluster.From(cluster).String())
+		return nil
+	}})(c)
+
+	lc := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        corev1alpha1.LogicalClusterName,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "abc123"},
+		},
+	}
+	if err := c.deleteContent(context.Background(), lc); err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0] != "abc123" {
+		t.Errorf("expected the stub to delete the content of abc123, got %v", deleted)
+	}
+}
+
+func TestCommitAnnotations(t *testing.T) {
+	lc := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        corev1alpha1.LogicalClusterName,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:test", "stale": "true"},
+		},
+	}
+	kcpClient := kcpfakeclient.NewSimpleClientset(lc)
+	c := &Controller{kcpClusterClient: kcpClient}
+
+	updated := lc.DeepCopy()
+	updated.Annotations[deletion.DeletionAttemptsAnnotationKey] = "2"
+	delete(updated.Annotations, "stale")
+	if err := c.commitAnnotations(context.Background(), lc, updated); err != nil {
+		t.Fatal(err)
+	}
+
+	var patches []string
+	for _, action := range kcpClient.Actions() {
+		if patch, ok := action.(kcptesting.PatchAction); ok {
+			patches = append(patches, string(patch.GetPatch()))
+		}
+	}
+	expected := `{"metadata":{"annotations":{"internal.core.kcp.io/deletion-attempts":"2","stale":null}}}`
+	if len(patches) != 1 || patches[0] != expected {
+		t.Errorf("expected patch %s, got %v", expected, patches)
+	}
+
+	// unchanged annotations are not patched.
+	kcpClient.ClearActions()
+	if err := c.commitAnnotations(context.Background(), updated, updated); err != nil {
+		t.Fatal(err)
+	}
+	if actions := kcpClient.Actions(); len(actions) != 0 {
+		t.Errorf("expected no patch, got %v", actions)
+	}
+}
+
+func TestDeleteContentBaggage(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewBaggageSpanProcessor()),
+		sdktrace.WithSyncer(exporter),
+	)
+
+	c := &Controller{
+		deleter: fakeDeleter{delete: func(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error {
+			_, span := trace.SpanFromContext(ctx).Tracer().Start(ctx, "child")
+			span.End()
+			return nil
+		}},
+	}
+	WithTracing(tracerProvider, "alpha")(c)
+
+	lc := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: corev1alpha1.LogicalClusterName,
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:         "abc123",
+				core.LogicalClusterPathAnnotationKey: "root:org:team",
+			},
+		},
+	}
+	if err := c.deleteContent(context.Background(), lc); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		attrs := map[attribute.Key]string{}
+		for _, kv := range span.Attributes {
+			attrs[kv.Key] = kv.Value.AsString()
+		}
+		expected := map[attribute.Key]string{
+			BaggageWorkspaceKey: "root:org:team",
+			BaggageClusterKey:   "abc123",
+			BaggageShardKey:     "alpha",
+		}
+		for k, v := range expected {
+			if attrs[k] != v {
+				t.Errorf("span %q: expected attribute %s=%q, got %q", span.Name, k, v, attrs[k])
+			}
+		}
+	}
+}
+
+func TestValidateForceFinalizeConfirmation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "not requested",
+			annotations: map[string]string{logicalcluster.AnnotationKey: "abc123"},
+			wantErr:     true,
+		},
+		{
+			name:        "matching token",
+			annotations: map[string]string{logicalcluster.AnnotationKey: "abc123", ForceFinalizeAnnotationKey: "abc123"},
+		},
+		{
+			name:        "mismatching token",
+			annotations: map[string]string{logicalcluster.AnnotationKey: "abc123", ForceFinalizeAnnotationKey: "true"},
+			wantErr:     true,
+		},
+		{
+			name:        "empty token",
+			annotations: map[string]string{logicalcluster.AnnotationKey: "abc123", ForceFinalizeAnnotationKey: ""},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &corev1alpha1.LogicalCluster{ObjectMeta: metav1.ObjectMeta{Name: corev1alpha1.LogicalClusterName, Annotations: tt.annotations}}
+			if err := deletion.ValidateForceFinalizeConfirmation(lc); (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestProcessWithStatusClient(t *testing.T) {
+	now := metav1.Now()
+	lc := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              corev1alpha1.LogicalClusterName,
+			ResourceVersion:   "1",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{deletion.LogicalClusterDeletionFinalizer},
+			Annotations:       map[string]string{logicalcluster.AnnotationKey: "root:test"},
+		},
+	}
+	clusterPath := logicalcluster.NewPath("root:test")
+	logicalClusters := corev1alpha1.Resource("logicalclusters").WithVersion("v1alpha1")
+	kcpClient := kcpfakeclient.NewSimpleClientset(lc.DeepCopy())
+	// like the server, status updates bump the resource version and patches are conditional on it.
+	kcpClient.PrependReactor("update", "logicalclusters", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "status" {
+			return false, nil, nil
+		}
+		obj := action.(kcptesting.UpdateAction).GetObject().(*corev1alpha1.LogicalCluster).DeepCopy()
+		obj.ResourceVersion = "2"
+		return true, obj, kcpClient.Tracker().Cluster(clusterPath).Update(logicalClusters, obj, "")
+	})
+	kcpClient.PrependReactor("patch", "logicalclusters", func(action kcptesting.Action) (bool, runtime.Object, error) {
+		var patch struct {
+			Metadata struct {
+				ResourceVersion string `json:"resourceVersion"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(action.(kcptesting.PatchAction).GetPatch(), &patch); err != nil {
+			return true, nil, err
+		}
+		current, err := kcpClient.Tracker().Cluster(clusterPath).Get(logicalClusters, "", corev1alpha1.LogicalClusterName)
+		if err != nil {
+			return true, nil, err
+		}
+		if rv := current.(*corev1alpha1.LogicalCluster).ResourceVersion; patch.Metadata.ResourceVersion != "" && patch.Metadata.ResourceVersion != rv {
+			return true, nil, apierrors.NewConflict(corev1alpha1.Resource("logicalclusters"), corev1alpha1.LogicalClusterName, fmt.Errorf("resource version %s is not %s", patch.Metadata.ResourceVersion, rv))
+		}
+		return false, nil, nil
+	})
+
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(lc); err != nil {
+		t.Fatal(err)
+	}
+	discoveryErr := errors.New("discovery timeout")
+	c := &Controller{
+		kcpClusterClient:     kcpClient,
+		logicalClusterLister: corev1alpha1listers.NewLogicalClusterClusterLister(indexer),
+		deleter: deletion.NewWorkspacedResourcesDeleter(kcpfakemetadata.NewSimpleMetadataClient(runtime.NewScheme()), func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+			return nil, discoveryErr
+		}, deletion.WithStatusClient(kcpClient)),
+		commit: committer.NewCommitter[*LogicalCluster, Patcher, *LogicalClusterSpec, *LogicalClusterStatus](kcpClient.CoreV1alpha1().LogicalClusters()),
+	}
+
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(lc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.process(context.Background(), key)
+	if !errors.Is(err, discoveryErr) {
+		t.Fatalf("expected the discovery error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "Operation cannot be fulfilled") {
+		t.Fatalf("expected the conditions to be committed without conflict, got %v", err)
+	}
+
+	committed, err := kcpClient.Cluster(clusterPath).CoreV1alpha1().LogicalClusters().Get(context.Background(), corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if committed.Status.Deletion == nil {
+		t.Error("expected the deletion status to be written")
+	}
+	if c := conditions.Get(committed, tenancyv1alpha1.WorkspaceContentDeleted); c == nil || c.Reason != "DiscoveryFailed" {
+		t.Errorf("expected the conditions to be committed, got %v", c)
+	}
+}
+
+func TestProcessCancelledDeletion(t *testing.T) {
+	now := metav1.Now()
+	lc := &corev1alpha1.LogicalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              corev1alpha1.LogicalClusterName,
+			DeletionTimestamp: &now,
+			Finalizers:        []string{deletion.LogicalClusterDeletionFinalizer},
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:            "root:test",
+				deletion.DeletionCancelledAnnotationKey: now.UTC().Format(time.RFC3339),
+			},
+		},
+	}
+	kcpClient := kcpfakeclient.NewSimpleClientset(lc.DeepCopy())
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(lc); err != nil {
+		t.Fatal(err)
+	}
+	c := &Controller{
+		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test"),
+		kcpClusterClient:     kcpClient,
+		logicalClusterLister: corev1alpha1listers.NewLogicalClusterClusterLister(indexer),
+		deleter: fakeDeleter{delete: func(ctx context.Context, cluster *corev1alpha1.LogicalCluster) error {
+			cluster.Annotations["example.com/observed"] = "true"
+			return fmt.Errorf("%w for logical cluster %s", deletion.ErrDeletionCancelled, logicalcluster.From(cluster))
+		}},
+		commit: committer.NewCommitter[*LogicalCluster, Patcher, *LogicalClusterSpec, *LogicalClusterStatus](kcpClient.CoreV1alpha1().LogicalClusters()),
+	}
+	defer c.queue.ShutDown()
+
+	key, err := kcpcache.MetaClusterNamespaceKeyFunc(lc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.queue.Add(key)
+	if !c.processNextWorkItem(context.Background()) {
+		t.Fatal("expected the queue to be running")
+	}
+	if n := c.queue.NumRequeues(key); n != 0 {
+		t.Errorf("expected the cancelled logical cluster not to be requeued, got %d requeues", n)
+	}
+	if n := c.queue.Len(); n != 0 {
+		t.Errorf("expected an empty queue, got %d items", n)
+	}
+	committed, err := kcpClient.Cluster(logicalcluster.NewPath("root:test")).CoreV1alpha1().LogicalClusters().Get(context.Background(), corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if committed.Annotations["example.com/observed"] != "true" {
+		t.Errorf("expected the annotations to be committed, got %v", committed.Annotations)
+	}
+}
+
+func TestDiscoveryCacheInvalidation(t *testing.T) {
+	calls := 0
+	discoveryCache := deletion.NewCachedDiscovery(func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		calls++
+		return nil, nil
+	}, time.Hour)
+	c := &Controller{
+		logicalClusterLister: corev1alpha1listers.NewLogicalClusterClusterLister(cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{})),
+		discoveryCache:       discoveryCache,
+	}
+	clusterName := logicalcluster.Name("root:test")
+	annotations := map[string]string{logicalcluster.AnnotationKey: clusterName.String()}
+
+	discover := func(expected int, reason string) {
+		t.Helper()
+		if _, err := discoveryCache.Discover(clusterName.Path()); err != nil {
+			t.Fatal(err)
+		}
+		if calls != expected {
+			t.Errorf("%s: expected %d discoveries, got %d", reason, expected, calls)
+		}
+	}
+	discover(1, "first discovery")
+	discover(1, "cached discovery")
+
+	c.invalidateDiscovery(&apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "widgets.example.com", Annotations: annotations}})
+	discover(2, "after a CRD changed")
+
+	c.invalidateDiscovery(cache.DeletedFinalStateUnknown{Obj: &apisv1alpha1.APIBinding{ObjectMeta: metav1.ObjectMeta{Name: "widgets", Annotations: annotations}}})
+	discover(3, "after an APIBinding was deleted")
+
+	// the logical cluster is gone.
+	if err := c.process(context.Background(), kcpcache.ToClusterAwareKey(clusterName.String(), "", "cluster")); err != nil {
+		t.Fatal(err)
+	}
+	discover(4, "after the logical cluster is gone")
+}
+
+func TestQueueDepthOnEnqueue(t *testing.T) {
+	c := &Controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test"),
+	}
+	defer c.queue.ShutDown()
+
+	for _, name := range []string{"a", "b"} {
+		c.enqueue(&corev1alpha1.LogicalCluster{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: map[string]string{logicalcluster.AnnotationKey: "root:" + name}}})
+	}
+
+	depth, err := testutil.GetGaugeMetricValue(queueDepth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if depth != 2 {
+		t.Errorf("expected queue depth 2 before any key is processed, got %v", depth)
+	}
+}
+
+func TestSaturationHandler(t *testing.T) {
+	c := &Controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "test"),
+	}
+	defer c.queue.ShutDown()
+	c.capacity.Store(1)
+
+	get := func() (int, Saturation) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		c.SaturationHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, SaturationPath, nil))
+		var saturation Saturation
+		if err := json.Unmarshal(recorder.Body.Bytes(), &saturation); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code, saturation
+	}
+
+	if code, saturation := get(); code != http.StatusOK || saturation != (Saturation{Capacity: 1}) {
+		t.Errorf("expected idle workers to be healthy, got %d %+v", code, saturation)
+	}
+
+	// the only worker is busy while another logical cluster waits.
+	c.active.Store(1)
+	c.queue.Add("root:waiting|cluster")
+	if code, saturation := get(); code != http.StatusServiceUnavailable || saturation != (Saturation{ActiveWorkers: 1, Capacity: 1, QueueDepth: 1}) {
+		t.Errorf("expected saturated workers to be reported, got %d %+v", code, saturation)
+	}
+}
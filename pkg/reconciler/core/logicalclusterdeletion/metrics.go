@@ -0,0 +1,83 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	finalizerRemovalErrors = compbasemetrics.NewCounterVec(
+		&compbasemetrics.CounterOpts{
+			Name:           "kcp_workspace_finalizer_removal_errors_total",
+			Help:           "Number of failed attempts to remove the deletion finalizer from a LogicalCluster, by reason.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"reason"},
+	)
+
+	activeWorkers = compbasemetrics.NewGauge(
+		&compbasemetrics.GaugeOpts{
+			Name:           "kcp_logicalcluster_deletion_active_workers",
+			Help:           "Number of deletion workers currently processing a terminating LogicalCluster.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+	)
+	workerCapacity = compbasemetrics.NewGauge(
+		&compbasemetrics.GaugeOpts{
+			Name:           "kcp_logicalcluster_deletion_worker_capacity",
+			Help:           "Number of deletion workers started for terminating LogicalClusters.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+	)
+	queueDepth = compbasemetrics.NewGauge(
+		&compbasemetrics.GaugeOpts{
+			Name:           "kcp_logicalcluster_deletion_queue_depth",
+			Help:           "Number of terminating LogicalClusters waiting for a deletion worker.",
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+	)
+)
+
+var registerMetrics sync.Once
+
+// Register metrics.
+func Register() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(finalizerRemovalErrors)
+		legacyregistry.MustRegister(activeWorkers)
+		legacyregistry.MustRegister(workerCapacity)
+		legacyregistry.MustRegister(queueDepth)
+	})
+}
+
+func init() {
+	Register()
+}
+
+// finalizerRemovalErrorReason maps an error to a low-cardinality reason label.
+func finalizerRemovalErrorReason(err error) string {
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+	return "Unknown"
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// SaturationPath is the path of the endpoint reporting the saturation of the deletion workers.
+const SaturationPath = "/logicalcluster-deletion/saturation"
+
+// Saturation is the load of the deletion workers.
+type Saturation struct {
+	// ActiveWorkers is the number of workers processing a terminating LogicalCluster.
+	ActiveWorkers int `json:"activeWorkers"`
+	// Capacity is the number of workers started.
+	Capacity int `json:"capacity"`
+	// QueueDepth is the number of terminating LogicalClusters waiting for a worker.
+	QueueDepth int `json:"queueDepth"`
+}
+
+// Saturated returns true if all workers are busy while LogicalClusters are waiting for them.
+func (s Saturation) Saturated() bool {
+	return s.Capacity > 0 && s.ActiveWorkers >= s.Capacity && s.QueueDepth > 0
+}
+
+// Saturation returns the current load of the deletion workers.
+func (c *Controller) Saturation() Saturation {
+	return Saturation{
+		ActiveWorkers: int(c.active.Load()),
+		Capacity:      int(c.capacity.Load()),
+		QueueDepth:    c.queue.Len(),
+	}
+}
+
+// SaturationHandler serves the saturation of the deletion workers as JSON. It answers 503 Service
+// Unavailable while the workers are saturated, such that probes and alerts can act on the status code.
+func (c *Controller) SaturationHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		saturation := c.Saturation()
+		w.Header().Set("Content-Type", "application/json")
+		if saturation.Saturated() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(saturation)
+	})
+}
+
+// updateQueueDepth sets the queue depth gauge to the current length of the queue.
+func (c *Controller) updateQueueDepth() {
+	queueDepth.Set(float64(c.queue.Len()))
+}
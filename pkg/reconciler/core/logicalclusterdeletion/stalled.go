@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"sync"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+)
+
+// stalledAfterPasses is the number of deletion passes without progress after which a LogicalCluster
+// is marked as stalled.
+const stalledAfterPasses = 10
+
+// deletionProgress remembers the remaining content of terminating LogicalClusters between passes.
+type deletionProgress struct {
+	lock      sync.Mutex
+	snapshots map[logicalcluster.Name]deletion.RemainingSnapshot
+}
+
+// observe updates the WorkspaceDeletionStalled condition of the LogicalCluster from its remaining content.
+func (p *deletionProgress) observe(lc *corev1alpha1.LogicalCluster, remaining map[schema.GroupVersionResource]int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.snapshots == nil {
+		p.snapshots = map[logicalcluster.Name]deletion.RemainingSnapshot{}
+	}
+	clusterName := logicalcluster.From(lc)
+	p.snapshots[clusterName] = deletion.ObserveProgress(lc, p.snapshots[clusterName], remaining, stalledAfterPasses)
+}
+
+// forget drops the remaining content of the given logical cluster.
+func (p *deletionProgress) forget(clusterName logicalcluster.Name) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	delete(p.snapshots, clusterName)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalclusterdeletion
+
+import (
+	"context"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+const (
+	// BaggageWorkspaceKey is the baggage key carrying the path of the workspace being deleted.
+	BaggageWorkspaceKey = attribute.Key("kcp.io/workspace")
+	// BaggageClusterKey is the baggage key carrying the name of the logical cluster being deleted.
+	BaggageClusterKey = attribute.Key("kcp.io/cluster")
+	// BaggageShardKey is the baggage key carrying the name of the shard the deletion runs on.
+	BaggageShardKey = attribute.Key("kcp.io/shard")
+)
+
+// Option configures optional behaviour of the Controller.
+type Option func(*Controller)
+
+// WithTracing traces the deletion of each LogicalCluster's content with a tracer of the given provider.
+// The workspace, logical cluster and shard are propagated as baggage to the context of the deleter.
+func WithTracing(tracerProvider trace.TracerProvider, shardName string) Option {
+	return func(c *Controller) {
+		c.tracer = tracerProvider.Tracer(ControllerName)
+		c.shardName = shardName
+	}
+}
+
+// deletionBaggage returns a context carrying the baggage identifying the deletion of the given LogicalCluster.
+func deletionBaggage(ctx context.Context, lc *corev1alpha1.LogicalCluster, shardName string) context.Context {
+	clusterName := logicalcluster.From(lc).String()
+	workspace := clusterName
+	if path, ok := lc.Annotations[core.LogicalClusterPathAnnotationKey]; ok {
+		workspace = path
+	}
+	return baggage.ContextWithValues(ctx,
+		BaggageWorkspaceKey.String(workspace),
+		BaggageClusterKey.String(clusterName),
+		BaggageShardKey.String(shardName),
+	)
+}
+
+// baggageSpanProcessor copies the baggage of the parent context onto every started span.
+type baggageSpanProcessor struct{}
+
+// NewBaggageSpanProcessor returns a span processor that adds the context baggage as attributes to
+// every span, such that all spans below a LogicalCluster deletion can be correlated by the backend.
+func NewBaggageSpanProcessor() sdktrace.SpanProcessor {
+	return baggageSpanProcessor{}
+}
+
+func (baggageSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	set := baggage.Set(parent)
+	s.SetAttributes(set.ToSlice()...)
+}
+
+func (baggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (baggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (baggageSpanProcessor) ForceFlush(context.Context) error { return nil }
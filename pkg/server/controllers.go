@@ -67,6 +67,7 @@ import (
 	"github.com/kcp-dev/kcp/pkg/reconciler/cache/replication"
 	logicalclusterctrl "github.com/kcp-dev/kcp/pkg/reconciler/core/logicalcluster"
 	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
 	coresreplicateclusterrole "github.com/kcp-dev/kcp/pkg/reconciler/core/replicateclusterrole"
 	corereplicateclusterrolebinding "github.com/kcp-dev/kcp/pkg/reconciler/core/replicateclusterrolebinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/core/shard"
@@ -340,6 +341,7 @@ func (s *Server) installLogicalClusterDeletionController(ctx context.Context, co
 		}
 		return discoveryClient.ServerPreferredResources()
 	}
+	discoveryCache := deletion.NewCachedDiscovery(discoverResourcesFn, time.Minute)
 	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(config)
 	if err != nil {
 		return err
@@ -352,8 +354,14 @@ func (s *Server) installLogicalClusterDeletionController(ctx context.Context, co
 		shardExternalURL,
 		metadataClusterClient,
 		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
-		discoverResourcesFn,
+		discoveryCache.Discover,
+		logicalclusterdeletion.WithDiscoveryCache(
+			discoveryCache,
+			s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+			s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		),
 	)
+	s.MiniAggregator.GenericAPIServer.Handler.NonGoRestfulMux.Handle(logicalclusterdeletion.SaturationPath, logicalClusterDeletionController.SaturationHandler())
 
 	return s.AddPostStartHook(postStartHookName(logicalclusterdeletion.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
 		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(logicalclusterdeletion.ControllerName))
@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacedeletion
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+	"github.com/kcp-dev/kcp/test/e2e/framework"
+)
+
+func TestLogicalClusterContentDeletion(t *testing.T) {
+	t.Parallel()
+	framework.Suite(t, "control-plane")
+
+	server := framework.SharedKcpServer(t)
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	t.Cleanup(cancelFunc)
+
+	h := newDeletionHarness(t, server)
+
+	sheriffs := h.createCRD(ctx, t, "sheriffs", apiextensionsv1.ClusterScoped)
+	cowboys := h.createCRD(ctx, t, "cowboys", apiextensionsv1.NamespaceScoped)
+
+	t.Logf("Creating content in %s", h.workspacePath)
+	_, err := h.kubeClusterClient.Cluster(h.clusterName.Path()).CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "deletion-test"}}, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create namespace")
+	h.createObject(ctx, t, cowboys, "deletion-test", "woody")
+	h.createObject(ctx, t, sheriffs, "", "plain")
+	h.createObject(ctx, t, sheriffs, "", "guarded", "e2e.kcp.io/guard")
+
+	logicalCluster := h.startDeletion(ctx, t)
+	deleter := h.newDeleter()
+
+	t.Logf("A deletion pass should report the instance waiting for its finalizer")
+	err = deleter.Delete(ctx, logicalCluster)
+	var remaining *deletion.ResourcesRemainingError
+	require.True(t, errors.As(err, &remaining), "expected content to remain, got: %v", err)
+	require.True(t, conditions.IsFalse(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted), "expected content not to be reported deleted")
+	require.Contains(t, conditions.GetMessage(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted), "e2e.kcp.io/guard")
+
+	guarded, err := h.metadataClusterClient.Cluster(h.clusterName.Path()).Resource(sheriffs).Get(ctx, "guarded", metav1.GetOptions{})
+	require.NoError(t, err, "the guarded instance should be kept by its finalizer")
+	require.NotNil(t, guarded.DeletionTimestamp, "the guarded instance should be terminating")
+
+	t.Logf("Removing the finalizer of the guarded instance")
+	h.removeFinalizers(ctx, t, sheriffs, "", "guarded")
+
+	h.deleteUntilDone(ctx, t, deleter, logicalCluster)
+
+	for _, gvr := range []schema.GroupVersionResource{sheriffs, cowboys, {Version: "v1", Resource: "namespaces"}} {
+		h.requireGone(ctx, t, gvr)
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2022 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workspacedeletion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	kcpmetadata "github.com/kcp-dev/client-go/metadata"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	tenancyv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/tenancy/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion/deletion"
+	"github.com/kcp-dev/kcp/test/e2e/framework"
+)
+
+// harnessFinalizer keeps the LogicalCluster of a harness around while it is deleted, such that the
+// deleter under test can run passes against it until the test is done.
+const harnessFinalizer = "e2e.kcp.io/deletion-harness"
+
+// testGroup is the group of the CRDs created by the harness.
+const testGroup = "deletion.e2e.kcp.io"
+
+// deletionHarness runs the content deleter end-to-end against the logical cluster of a real workspace,
+// wired with a metadata client and discovery of the shard like the deletion controller. It exercises
+// what a fake client hides, e.g. delete-collection semantics, finalizers and namespace termination.
+// Like the rest of this suite, it needs a running kcp server: envtest serves no logical clusters.
+//
+// The deletion controller of the server deletes the content of the logical cluster concurrently once
+// its deletion has started. Both converge on the same state, so assertions are on the outcome of the
+// passes of the deleter under test rather than on the calls it issued.
+type deletionHarness struct {
+	workspacePath logicalcluster.Path
+	clusterName   logicalcluster.Name
+
+	// workspaceClusterClient accesses the parent of the workspace through the front-proxy.
+	workspaceClusterClient kcpclientset.ClusterInterface
+
+	config                *rest.Config
+	kcpClusterClient      kcpclientset.ClusterInterface
+	kubeClusterClient     kcpkubernetesclientset.ClusterInterface
+	dynamicClusterClient  kcpdynamic.ClusterInterface
+	crdClusterClient      kcpapiextensionsclientset.ClusterInterface
+	metadataClusterClient kcpmetadata.ClusterInterface
+}
+
+// newDeletionHarness creates a workspace on the root shard whose content can be deleted with a deleter
+// returned by newDeleter.
+func newDeletionHarness(t *testing.T, server framework.RunningServer) *deletionHarness {
+	t.Helper()
+
+	orgPath, _ := framework.NewOrganizationFixture(t, server, framework.WithRootShard())
+	workspacePath, ws := framework.NewWorkspaceFixture(t, server, orgPath, framework.WithRootShard())
+
+	// access the shard directly, the front-proxy stops serving the workspace once it is deleted.
+	cfg := server.RootShardSystemMasterBaseConfig(t)
+	h := &deletionHarness{
+		workspacePath: workspacePath,
+		clusterName:   logicalcluster.Name(ws.Spec.Cluster),
+		config:        cfg,
+	}
+
+	var err error
+	h.workspaceClusterClient, err = kcpclientset.NewForConfig(server.BaseConfig(t))
+	require.NoError(t, err, "failed to construct front-proxy kcp client")
+	h.kcpClusterClient, err = kcpclientset.NewForConfig(cfg)
+	require.NoError(t, err, "failed to construct kcp client")
+	h.kubeClusterClient, err = kcpkubernetesclientset.NewForConfig(cfg)
+	require.NoError(t, err, "failed to construct kube client")
+	h.dynamicClusterClient, err = kcpdynamic.NewForConfig(cfg)
+	require.NoError(t, err, "failed to construct dynamic client")
+	h.crdClusterClient, err = kcpapiextensionsclientset.NewForConfig(cfg)
+	require.NoError(t, err, "failed to construct CRD client")
+	h.metadataClusterClient, err = kcpmetadata.NewForConfig(cfg)
+	require.NoError(t, err, "failed to construct metadata client")
+
+	return h
+}
+
+// newDeleter returns a deleter for the content of the harness' logical cluster, configured with opts.
+func (h *deletionHarness) newDeleter(opts ...deletion.Option) deletion.WorkspaceResourcesDeleterInterface {
+	discoverResourcesFn := func(clusterName logicalcluster.Path) ([]*metav1.APIResourceList, error) {
+		logicalClusterConfig := rest.CopyConfig(h.config)
+		logicalClusterConfig.Host += clusterName.RequestPath()
+		discoveryClient, err := discovery.NewDiscoveryClientForConfig(logicalClusterConfig)
+		if err != nil {
+			return nil, err
+		}
+		return discoveryClient.ServerPreferredResources()
+	}
+	return deletion.NewWorkspacedResourcesDeleter(h.metadataClusterClient, discoverResourcesFn, opts...)
+}
+
+// createCRD creates a CRD of testGroup with the given plural and scope, and waits until instances can
+// be created. Instances accept arbitrary fields.
+func (h *deletionHarness) createCRD(ctx context.Context, t *testing.T, plural string, scope apiextensionsv1.ResourceScope) schema.GroupVersionResource {
+	t.Helper()
+
+	singular := strings.TrimSuffix(plural, "s")
+	kind := kindOf(plural)
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + testGroup},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: testGroup,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:   plural,
+				Singular: singular,
+				Kind:     kind,
+				ListKind: kind + "List",
+			},
+			Scope: scope,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    "v1",
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type:                   "object",
+						XPreserveUnknownFields: func() *bool { b := true; return &b }(),
+					},
+				},
+			}},
+		},
+	}
+	t.Logf("Creating CRD %s in %s", crd.Name, h.workspacePath)
+	_, err := h.crdClusterClient.Cluster(h.clusterName.Path()).ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create CRD %s", crd.Name)
+
+	gvr := schema.GroupVersionResource{Group: testGroup, Version: "v1", Resource: plural}
+	framework.Eventually(t, func() (bool, string) {
+		_, err := h.dynamicClusterClient.Cluster(h.clusterName.Path()).Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return false, fmt.Sprintf("%s not served yet: %v", gvr, err)
+		}
+		return true, ""
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "CRD %s never became served", crd.Name)
+
+	return gvr
+}
+
+// createObject creates an instance of gvr in the harness' logical cluster with the given finalizers.
+// The namespace is empty for cluster-scoped resources.
+func (h *deletionHarness) createObject(ctx context.Context, t *testing.T, gvr schema.GroupVersionResource, namespace, name string, finalizers ...string) {
+	t.Helper()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind(kindOf(gvr.Resource))
+	obj.SetName(name)
+	obj.SetNamespace(namespace)
+	obj.SetFinalizers(finalizers)
+	_, err := h.dynamicClusterClient.Cluster(h.clusterName.Path()).Resource(gvr).Namespace(namespace).Create(ctx, obj, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create %s %s/%s", gvr, namespace, name)
+}
+
+// removeFinalizers clears the finalizers of an instance of gvr, such that it can go away.
+func (h *deletionHarness) removeFinalizers(ctx context.Context, t *testing.T, gvr schema.GroupVersionResource, namespace, name string) {
+	t.Helper()
+
+	patch := []byte(`{"metadata":{"finalizers":null}}`)
+	_, err := h.dynamicClusterClient.Cluster(h.clusterName.Path()).Resource(gvr).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	require.NoError(t, err, "failed to remove finalizers of %s %s/%s", gvr, namespace, name)
+}
+
+// requireGone waits until no instances of gvr are left in the harness' logical cluster.
+func (h *deletionHarness) requireGone(ctx context.Context, t *testing.T, gvr schema.GroupVersionResource) {
+	t.Helper()
+
+	framework.Eventually(t, func() (bool, string) {
+		list, err := h.metadataClusterClient.Cluster(h.clusterName.Path()).Resource(gvr).List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, ""
+		}
+		if err != nil {
+			return false, fmt.Sprintf("failed to list %s: %v", gvr, err)
+		}
+		return len(list.Items) == 0, fmt.Sprintf("%d instances of %s remain", len(list.Items), gvr)
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "instances of %s were not deleted", gvr)
+}
+
+// startDeletion deletes the workspace of the harness and returns its LogicalCluster once it is
+// deleting. The LogicalCluster is kept until the end of the test.
+func (h *deletionHarness) startDeletion(ctx context.Context, t *testing.T) *corev1alpha1.LogicalCluster {
+	t.Helper()
+
+	logicalClusters := h.kcpClusterClient.Cluster(h.clusterName.Path()).CoreV1alpha1().LogicalClusters()
+	patch := []byte(fmt.Sprintf(`[{"op":"add","path":"/metadata/finalizers/-","value":%q}]`, harnessFinalizer))
+	_, err := logicalClusters.Patch(ctx, corev1alpha1.LogicalClusterName, types.JSONPatchType, patch, metav1.PatchOptions{})
+	require.NoError(t, err, "failed to add the harness finalizer to the LogicalCluster")
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), wait.ForeverTestTimeout)
+		defer cancel()
+		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			logicalCluster, err := logicalClusters.Get(ctx, corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			finalizers := sets.NewString(logicalCluster.Finalizers...)
+			if !finalizers.Has(harnessFinalizer) {
+				return nil
+			}
+			logicalCluster.Finalizers = finalizers.Delete(harnessFinalizer).List()
+			_, err = logicalClusters.Update(ctx, logicalCluster, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil && !apierrors.IsNotFound(err) {
+			t.Errorf("failed to remove the harness finalizer from the LogicalCluster: %v", err)
+		}
+	})
+
+	t.Logf("Deleting workspace %s", h.workspacePath)
+	parent, _ := h.workspacePath.Parent()
+	err = h.workspaceClusterClient.Cluster(parent).TenancyV1alpha1().Workspaces().Delete(ctx, h.workspacePath.Base(), metav1.DeleteOptions{})
+	require.NoError(t, err, "failed to delete workspace %s", h.workspacePath)
+
+	var logicalCluster *corev1alpha1.LogicalCluster
+	framework.Eventually(t, func() (bool, string) {
+		logicalCluster, err = logicalClusters.Get(ctx, corev1alpha1.LogicalClusterName, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Sprintf("failed to get LogicalCluster: %v", err)
+		}
+		return !logicalCluster.DeletionTimestamp.IsZero(), "LogicalCluster is not deleting"
+	}, wait.ForeverTestTimeout, 100*time.Millisecond, "LogicalCluster of %s was never deleted", h.workspacePath)
+
+	return logicalCluster
+}
+
+// deleteUntilDone runs deletion passes on logicalCluster until one completes, and requires the content
+// to be reported deleted afterwards.
+func (h *deletionHarness) deleteUntilDone(ctx context.Context, t *testing.T, deleter deletion.WorkspaceResourcesDeleterInterface, logicalCluster *corev1alpha1.LogicalCluster) {
+	t.Helper()
+
+	framework.Eventually(t, func() (bool, string) {
+		if err := deleter.Delete(ctx, logicalCluster); err != nil {
+			return false, fmt.Sprintf("deletion pass did not complete: %v", err)
+		}
+		return true, ""
+	}, wait.ForeverTestTimeout, time.Second, "content of %s was never deleted", h.workspacePath)
+
+	require.True(t, conditions.IsTrue(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted), "expected content to be reported deleted: %v", conditions.Get(logicalCluster, tenancyv1alpha1.WorkspaceContentDeleted))
+}
+
+// kindOf returns the kind of the harness CRD with the given plural, e.g. Sheriff for sheriffs.
+func kindOf(plural string) string {
+	singular := strings.TrimSuffix(plural, "s")
+	return strings.ToUpper(singular[:1]) + singular[1:]
+}